@@ -12,9 +12,17 @@ import (
 
 	"github.com/SeeXWH/pr-reviewer-service/configs"
 	"github.com/SeeXWH/pr-reviewer-service/internal/analytics"
+	"github.com/SeeXWH/pr-reviewer-service/internal/github"
+	"github.com/SeeXWH/pr-reviewer-service/internal/gitlab"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
+	"github.com/SeeXWH/pr-reviewer-service/internal/ownership"
 	"github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+	"github.com/SeeXWH/pr-reviewer-service/internal/selection"
+	"github.com/SeeXWH/pr-reviewer-service/internal/sla"
 	"github.com/SeeXWH/pr-reviewer-service/internal/team"
+	"github.com/SeeXWH/pr-reviewer-service/internal/teamsync"
 	"github.com/SeeXWH/pr-reviewer-service/internal/user"
+	"github.com/SeeXWH/pr-reviewer-service/internal/webhook"
 	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
 	"github.com/SeeXWH/pr-reviewer-service/pkg/logger"
 	"github.com/SeeXWH/pr-reviewer-service/pkg/middleware"
@@ -36,20 +44,52 @@ func main() {
 	}
 	mainRouter := http.NewServeMux()
 
-	teamRepository := team.NewRepository(postgresDB)
 	userRepository := user.NewRepository(postgresDB)
+	teamRepository := team.NewRepository(postgresDB, userRepository)
 	prRepository := pullrequest.NewRepository(postgresDB)
 	analyticRepository := analytics.NewRepository(postgresDB)
+	selectionRepository := selection.NewRepository(postgresDB)
+	webhookRepository := webhook.NewRepository(postgresDB)
+	githubRepository := github.NewRepository(postgresDB)
+	ownershipRepository := ownership.NewRepository(postgresDB)
+	slaRepository := sla.NewRepository(postgresDB)
+	notifyRepository := notify.NewRepository(postgresDB)
+	teamSyncRepository := teamsync.NewRepository(postgresDB, userRepository)
 
-	teamService := team.NewService(teamRepository, log)
-	userService := user.NewService(userRepository, log)
-	prService := pullrequest.NewService(userService, prRepository, log)
+	notifyService := notify.NewService(notifyRepository, notify.NewNotifiers(conf.Notify), conf.Notify, log)
+	teamService := team.NewService(teamRepository, notifyService, conf.Reviewers, log)
+	userService := user.NewService(userRepository, notifyService, log)
+	selectionService := selection.NewService(selectionRepository, conf.Reviewers, log)
+	ownershipService := ownership.NewService(ownershipRepository, log)
+	prService := pullrequest.NewService(userService, teamService, selectionService, ownershipService, prRepository, notifyService, conf.Reviewers, log)
 	analyticsService := analytics.NewService(analyticRepository, log)
+	webhookService := webhook.NewService(webhookRepository, log)
+	webhookDispatcher := webhook.NewDispatcher(webhookRepository, conf.Webhooks, log)
+	awayWorker := user.NewAwayWorker(userRepository, conf.Away, log)
+	slaService := sla.NewService(slaRepository, prService, conf.SLA, log)
+	githubService := github.NewService(prService, githubRepository, conf.GitHub, log)
+	gitlabService := gitlab.NewService(prService, conf.GitLab, log)
+	teamSyncService := teamsync.NewService(teamSyncRepository, notifyService, log)
 
 	user.NewHandler(mainRouter, userService, conf)
 	team.NewHandler(mainRouter, teamService, conf)
 	pullrequest.NewHandler(mainRouter, prService, conf)
+	ownership.NewHandler(mainRouter, ownershipService, conf)
 	analytics.NewHandler(mainRouter, analyticsService, conf)
+	webhook.NewHandler(mainRouter, webhookService, conf)
+	github.NewHandler(mainRouter, githubService, conf)
+	gitlab.NewHandler(mainRouter, gitlabService, conf)
+	sla.NewHandler(mainRouter, slaService, conf)
+	teamsync.NewHandler(mainRouter, teamSyncService, conf)
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go webhookDispatcher.Run(workerCtx)
+	go slaService.Run(workerCtx)
+	go notifyService.Run(workerCtx)
+	if conf.Away.AutoReassign {
+		go awayWorker.Run(workerCtx)
+	}
 
 	server := http.Server{
 		Addr:              conf.App.Port,
@@ -71,6 +111,7 @@ func main() {
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 	<-stop
 	log.Info("shutting down server")
+	stopWorkers()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	if err = server.Shutdown(ctx); err != nil {
@@ -31,9 +31,52 @@ func main() {
 		log.Fatal(err)
 	}
 	log.Println("Database connected. Running AutoMigrate...")
-	err = db.AutoMigrate(&model.Team{}, &model.User{}, &model.PullRequest{})
+	err = db.AutoMigrate(
+		&model.Team{},
+		&model.User{},
+		&model.TeamMember{},
+		&model.PullRequest{},
+		&model.PRReviewer{},
+		&model.PREvent{},
+		&model.OutboxMessage{},
+		&model.WebhookSubscription{},
+		&model.WebhookDelivery{},
+		&model.IntegrationDelivery{},
+		&model.OwnershipRule{},
+		&model.AwayPeriod{},
+		&model.TeamHoliday{},
+		&model.Escalation{},
+		&model.ReviewerCursor{},
+	)
 	if err != nil {
 		log.Fatal(err)
 	}
+	if err = moveTeamColumn(db); err != nil {
+		log.Fatal(err)
+	}
 	log.Printf("Migration completed successfully in %.3fs", time.Since(temp).Seconds())
 }
+
+// moveTeamColumn turns the old users.team_name column into primary memberships, stamps open PRs
+// with their author's team and drops the column. It does nothing once the column is gone.
+func moveTeamColumn(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("users", "team_name") {
+		return nil
+	}
+	log.Println("Moving users.team_name into team_members...")
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`INSERT INTO team_members (team_name, user_id, role, is_primary)
+			SELECT team_name, user_id, ?, true FROM users WHERE team_name IS NOT NULL AND team_name <> ''
+			ON CONFLICT DO NOTHING`, model.RoleMember).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Exec(`UPDATE pull_requests SET team_name = users.team_name FROM users
+			WHERE users.user_id = pull_requests.author_id
+			AND COALESCE(pull_requests.team_name, '') = '' AND users.team_name IS NOT NULL`).Error
+		if err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn("users", "team_name")
+	})
+}
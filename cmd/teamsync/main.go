@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
+	"github.com/SeeXWH/pr-reviewer-service/internal/teamsync"
+	"github.com/SeeXWH/pr-reviewer-service/internal/user"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/logger"
+)
+
+// syncNotifier delivers right away: the command exits before background workers would get to the queue.
+type syncNotifier struct {
+	service *notify.Service
+}
+
+func (n syncNotifier) Notify(ctx context.Context, notification notify.Notification) {
+	if err := n.service.Deliver(ctx, notification); err != nil {
+		log.Printf("notification for %s failed: %v", notification.PullRequestID, err)
+	}
+}
+
+func main() {
+	file := flag.String("file", "teams.yaml", "path to the teams file")
+	apply := flag.Bool("apply", false, "apply the plan instead of only printing it")
+	flag.Parse()
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	conf := configs.Load()
+	slogger := logger.Setup()
+	postgresDB, err := db.NewPostgresDB(conf)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	notifyService := notify.NewService(notify.NewRepository(postgresDB), notify.NewNotifiers(conf.Notify), conf.Notify, slogger)
+	syncRepository := teamsync.NewRepository(postgresDB, user.NewRepository(postgresDB))
+	syncService := teamsync.NewService(syncRepository, syncNotifier{service: notifyService}, slogger)
+
+	ctx := context.Background()
+	if !*apply {
+		plan, err := syncService.Plan(ctx, data)
+		if err != nil {
+			log.Fatal(err)
+		}
+		printPlan(plan)
+		if len(plan.NewTeams) > 0 || len(plan.Changes) > 0 {
+			fmt.Println("\nRun with -apply to make these changes.")
+		}
+		return
+	}
+
+	result, err := syncService.Apply(ctx, data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printPlan(result.Plan)
+	fmt.Printf("\nApplied. Reassigned reviews: %d\n", result.ReassignedCount)
+	for _, a := range result.Assignments {
+		fmt.Printf("  %s -> %s\n", a.PullRequestID, a.UserID)
+	}
+}
+
+func printPlan(plan teamsync.Plan) {
+	if len(plan.NewTeams) == 0 && len(plan.Changes) == 0 {
+		fmt.Println("Teams are up to date.")
+		return
+	}
+	for _, name := range plan.NewTeams {
+		fmt.Printf("+ team %s\n", name)
+	}
+	for _, c := range plan.Changes {
+		switch c.Action {
+		case teamsync.ActionAdd, teamsync.ActionJoin, teamsync.ActionRole:
+			fmt.Printf("  %-10s %s (%s, %s)\n", c.Action, c.UserID, c.TeamName, c.Role)
+		default:
+			fmt.Printf("  %-10s %s (%s)\n", c.Action, c.UserID, c.TeamName)
+		}
+	}
+}
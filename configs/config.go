@@ -2,14 +2,23 @@ package configs
 
 import (
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	DB  DB
-	App App
+	DB        DB
+	App       App
+	Reviewers Reviewers
+	Webhooks  Webhooks
+	GitHub    GitHub
+	GitLab    GitLab
+	Away      Away
+	SLA       SLA
+	Notify    Notify
 }
 
 type DB struct {
@@ -25,6 +34,58 @@ type App struct {
 	TimeOut time.Duration
 }
 
+type Reviewers struct {
+	Strategy       string
+	TeamStrategies map[string]string
+	// GlobalPool is the last level of every team's fallback chain.
+	GlobalPool []string
+	// MaxPerPR caps manually added reviewers; a team's required_reviewers raises it.
+	MaxPerPR int
+	// DefaultApprovals is the merge quorum of teams that never set required_approvals.
+	DefaultApprovals int
+}
+
+type Webhooks struct {
+	PollInterval   time.Duration
+	RequestTimeout time.Duration
+	MaxAttempts    int
+	BaseBackoff    time.Duration
+	MaxBackoff     time.Duration
+	BatchSize      int
+}
+
+type Away struct {
+	AutoReassign bool
+	PollInterval time.Duration
+}
+
+type SLA struct {
+	PollInterval time.Duration
+}
+
+type Notify struct {
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+	// ChatWebhookURL is an incoming webhook of Mattermost or Slack.
+	ChatWebhookURL string
+	QueueSize      int
+	Workers        int
+	SendTimeout    time.Duration
+}
+
+type GitHub struct {
+	WebhookSecret string
+	UserMap       map[string]string
+}
+
+type GitLab struct {
+	WebhookToken string
+	UserMap      map[string]string
+}
+
 func Load() *Config {
 	_ = godotenv.Load(".env")
 	timeoutStr := os.Getenv("APP_TIMEOUT")
@@ -45,5 +106,106 @@ func Load() *Config {
 			Port:    os.Getenv("APP_PORT"),
 			TimeOut: timeout,
 		},
+		Reviewers: Reviewers{
+			Strategy:       os.Getenv("REVIEWER_STRATEGY"),
+			TeamStrategies: parsePairs(os.Getenv("REVIEWER_TEAM_STRATEGIES")),
+			GlobalPool:     parseList(os.Getenv("REVIEWER_GLOBAL_POOL")),
+			MaxPerPR:       parseInt(os.Getenv("REVIEWER_MAX_PER_PR"), 5),
+			// 0 is a valid value here: it keeps merges without approvals for teams that set none.
+			DefaultApprovals: parseCount(os.Getenv("REVIEWER_DEFAULT_APPROVALS"), 1),
+		},
+		Webhooks: Webhooks{
+			PollInterval:   parseDuration(os.Getenv("WEBHOOK_POLL_INTERVAL"), time.Second),
+			RequestTimeout: parseDuration(os.Getenv("WEBHOOK_TIMEOUT"), 5*time.Second),
+			MaxAttempts:    parseInt(os.Getenv("WEBHOOK_MAX_ATTEMPTS"), 8),
+			BaseBackoff:    parseDuration(os.Getenv("WEBHOOK_BACKOFF"), time.Second),
+			MaxBackoff:     parseDuration(os.Getenv("WEBHOOK_MAX_BACKOFF"), 10*time.Minute),
+			BatchSize:      parseInt(os.Getenv("WEBHOOK_BATCH_SIZE"), 50),
+		},
+		GitHub: GitHub{
+			WebhookSecret: os.Getenv("GITHUB_WEBHOOK_SECRET"),
+			UserMap:       parsePairs(os.Getenv("GITHUB_USER_MAP")),
+		},
+		GitLab: GitLab{
+			WebhookToken: os.Getenv("GITLAB_WEBHOOK_TOKEN"),
+			UserMap:      parsePairs(os.Getenv("GITLAB_USER_MAP")),
+		},
+		Away: Away{
+			AutoReassign: parseBool(os.Getenv("AWAY_AUTO_REASSIGN"), false),
+			PollInterval: parseDuration(os.Getenv("AWAY_POLL_INTERVAL"), time.Minute),
+		},
+		SLA: SLA{
+			PollInterval: parseDuration(os.Getenv("SLA_POLL_INTERVAL"), 5*time.Minute),
+		},
+		Notify: Notify{
+			SMTPHost:       os.Getenv("NOTIFY_SMTP_HOST"),
+			SMTPPort:       os.Getenv("NOTIFY_SMTP_PORT"),
+			SMTPUsername:   os.Getenv("NOTIFY_SMTP_USERNAME"),
+			SMTPPassword:   os.Getenv("NOTIFY_SMTP_PASSWORD"),
+			SMTPFrom:       os.Getenv("NOTIFY_SMTP_FROM"),
+			ChatWebhookURL: os.Getenv("NOTIFY_CHAT_WEBHOOK_URL"),
+			QueueSize:      parseInt(os.Getenv("NOTIFY_QUEUE_SIZE"), 256),
+			Workers:        parseInt(os.Getenv("NOTIFY_WORKERS"), 2),
+			SendTimeout:    parseDuration(os.Getenv("NOTIFY_TIMEOUT"), 10*time.Second),
+		},
+	}
+}
+
+func parseDuration(raw string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+func parseInt(raw string, fallback int) int {
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		return fallback
+	}
+	return n
+}
+
+// parseCount is parseInt that also accepts 0.
+func parseCount(raw string, fallback int) int {
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return fallback
+	}
+	return n
+}
+
+func parseBool(raw string, fallback bool) bool {
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return fallback
+	}
+	return b
+}
+
+func parseList(raw string) []string {
+	var items []string
+	for item := range strings.SplitSeq(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parsePairs(raw string) map[string]string {
+	pairs := make(map[string]string)
+	for item := range strings.SplitSeq(raw, ",") {
+		key, value, ok := strings.Cut(item, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if key == "" || value == "" {
+			continue
+		}
+		pairs[key] = value
 	}
+	return pairs
 }
@@ -8,3 +8,17 @@ type StatItemDTO struct {
 type StatsResponseDTO struct {
 	Stats []StatItemDTO `json:"stats"`
 }
+
+type TeamStatDTO struct {
+	TeamName         string `json:"team_name"`
+	PullRequests     int    `json:"pull_requests"`
+	OpenPullRequests int    `json:"open_pull_requests"`
+	Reviews          int    `json:"reviews"`
+}
+
+// DepartmentStatsResponseDTO holds the department total and one row per team in it, the department included.
+type DepartmentStatsResponseDTO struct {
+	TeamName string        `json:"team_name"`
+	Total    TeamStatDTO   `json:"total"`
+	Teams    []TeamStatDTO `json:"teams"`
+}
@@ -0,0 +1,9 @@
+package analytics
+
+import "errors"
+
+var (
+	ErrBadFilter    = errors.New("include_subteams must be true or false")
+	ErrNoTeam       = errors.New("team_name is required")
+	ErrTeamNotFound = errors.New("team not found")
+)
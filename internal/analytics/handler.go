@@ -2,6 +2,7 @@ package analytics
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/SeeXWH/pr-reviewer-service/configs"
@@ -20,13 +21,19 @@ func NewHandler(router *http.ServeMux, analyticService Provider, conf *configs.C
 	}
 
 	router.HandleFunc("GET /analytics/pr", handler.GetStats())
+	router.HandleFunc("GET /analytics/teams", handler.GetDepartmentStats())
 }
 
 func (h *Handler) GetStats() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
 		defer cancel()
-		data, err := h.analyticService.GetStats(ctx)
+		filter, err := ToStatsFilter(r.URL.Query())
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+			return
+		}
+		data, err := h.analyticService.GetStats(ctx, filter)
 		if err != nil {
 			res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
 			return
@@ -35,3 +42,23 @@ func (h *Handler) GetStats() http.HandlerFunc {
 		res.JSON(w, http.StatusOK, resp)
 	}
 }
+
+func (h *Handler) GetDepartmentStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		data, err := h.analyticService.GetDepartmentStats(ctx, r.URL.Query().Get("team_name"))
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrNoTeam):
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+			case errors.Is(err, ErrTeamNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+			}
+			return
+		}
+		res.JSON(w, http.StatusOK, ToDepartmentDTO(data))
+	}
+}
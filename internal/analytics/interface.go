@@ -3,9 +3,11 @@ package analytics
 import "context"
 
 type Provider interface {
-	GetStats(context.Context) ([]ReviewerStat, error)
+	GetStats(context.Context, StatsFilter) ([]ReviewerStat, error)
+	GetDepartmentStats(context.Context, string) (*DepartmentStats, error)
 }
 
 type Storer interface {
-	GetReviewerStats(context.Context) ([]ReviewerStat, error)
+	GetReviewerStats(context.Context, StatsFilter) ([]ReviewerStat, error)
+	GetTeamStats(context.Context, string) ([]TeamStat, error)
 }
@@ -1,5 +1,10 @@
 package analytics
 
+import (
+	"net/url"
+	"strconv"
+)
+
 func ToDTO(stats []ReviewerStat) StatsResponseDTO {
 	items := make([]StatItemDTO, len(stats))
 
@@ -14,3 +19,36 @@ func ToDTO(stats []ReviewerStat) StatsResponseDTO {
 		Stats: items,
 	}
 }
+
+func ToDepartmentDTO(stats *DepartmentStats) DepartmentStatsResponseDTO {
+	teams := make([]TeamStatDTO, len(stats.Teams))
+	for i, t := range stats.Teams {
+		teams[i] = toTeamStatDTO(t)
+	}
+	return DepartmentStatsResponseDTO{
+		TeamName: stats.TeamName,
+		Total:    toTeamStatDTO(stats.Total),
+		Teams:    teams,
+	}
+}
+
+func toTeamStatDTO(t TeamStat) TeamStatDTO {
+	return TeamStatDTO{
+		TeamName:         t.TeamName,
+		PullRequests:     t.PullRequests,
+		OpenPullRequests: t.OpenPullRequests,
+		Reviews:          t.Reviews,
+	}
+}
+
+func ToStatsFilter(query url.Values) (StatsFilter, error) {
+	filter := StatsFilter{TeamName: query.Get("team_name")}
+	if raw := query.Get("include_subteams"); raw != "" {
+		include, err := strconv.ParseBool(raw)
+		if err != nil {
+			return StatsFilter{}, ErrBadFilter
+		}
+		filter.IncludeSubTeams = include
+	}
+	return filter, nil
+}
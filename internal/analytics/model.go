@@ -1,6 +1,28 @@
 package analytics
 
+// StatsFilter narrows reviewer stats to the PRs of TeamName, and with IncludeSubTeams also to
+// the PRs of every team below it, so a department's stats roll up its squads.
+type StatsFilter struct {
+	TeamName        string
+	IncludeSubTeams bool
+}
+
 type ReviewerStat struct {
 	UserID string
 	Count  int
 }
+
+// TeamStat counts the PRs of one team and the review assignments on them.
+type TeamStat struct {
+	TeamName         string
+	PullRequests     int
+	OpenPullRequests int
+	Reviews          int
+}
+
+// DepartmentStats rolls a team and every team below it up into one total, with a row per team.
+type DepartmentStats struct {
+	TeamName string
+	Total    TeamStat
+	Teams    []TeamStat
+}
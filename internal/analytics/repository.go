@@ -3,6 +3,7 @@ package analytics
 import (
 	"context"
 
+	"github.com/SeeXWH/pr-reviewer-service/internal/team"
 	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
 )
 
@@ -14,12 +15,22 @@ func NewRepository(db *db.PostgresDB) *Repository {
 	return &Repository{db: db}
 }
 
-func (r *Repository) GetReviewerStats(ctx context.Context) ([]ReviewerStat, error) {
+// GetReviewerStats counts assignments per reviewer, only on PRs of the filtered team when it is set.
+func (r *Repository) GetReviewerStats(ctx context.Context, filter StatsFilter) ([]ReviewerStat, error) {
 	var stats []ReviewerStat
-	err := r.db.PostgresDB.WithContext(ctx).
+	query := r.db.PostgresDB.WithContext(ctx).
 		Table("pr_reviewers").
-		Select("user_id, count(*) as count").
-		Group("user_id").
+		Select("pr_reviewers.user_id, count(*) as count")
+	if filter.TeamName != "" {
+		query = query.Joins("JOIN pull_requests ON pull_requests.pull_request_id = pr_reviewers.pull_request_id")
+		if filter.IncludeSubTeams {
+			query = query.Scopes(team.InSubtree("pull_requests.team_name", filter.TeamName))
+		} else {
+			query = query.Where("pull_requests.team_name = ?", filter.TeamName)
+		}
+	}
+	err := query.
+		Group("pr_reviewers.user_id").
 		Order("count desc").
 		Scan(&stats).Error
 
@@ -29,3 +40,25 @@ func (r *Repository) GetReviewerStats(ctx context.Context) ([]ReviewerStat, erro
 
 	return stats, nil
 }
+
+// GetTeamStats returns one row per team in teamName's subtree, teamName included, ordered by name.
+// Teams without PRs are listed with zero counts; an unknown team yields no rows.
+func (r *Repository) GetTeamStats(ctx context.Context, teamName string) ([]TeamStat, error) {
+	var stats []TeamStat
+	err := r.db.PostgresDB.WithContext(ctx).
+		Table("teams").
+		Select("teams.team_name, " +
+			"count(DISTINCT pull_requests.pull_request_id) AS pull_requests, " +
+			"count(DISTINCT pull_requests.pull_request_id) FILTER (WHERE pull_requests.status = 'OPEN') AS open_pull_requests, " +
+			"count(pr_reviewers.user_id) AS reviews").
+		Joins("LEFT JOIN pull_requests ON pull_requests.team_name = teams.team_name").
+		Joins("LEFT JOIN pr_reviewers ON pr_reviewers.pull_request_id = pull_requests.pull_request_id").
+		Scopes(team.InSubtree("teams.team_name", teamName)).
+		Group("teams.team_name").
+		Order("teams.team_name").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
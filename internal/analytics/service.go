@@ -17,12 +17,38 @@ func NewService(repository Storer, log *slog.Logger) *Service {
 	}
 }
 
-func (s *Service) GetStats(ctx context.Context) ([]ReviewerStat, error) {
-	stats, err := s.repo.GetReviewerStats(ctx)
+// GetStats returns review counts per reviewer across all PRs or, when a team is set, the team's PRs.
+func (s *Service) GetStats(ctx context.Context, filter StatsFilter) ([]ReviewerStat, error) {
+	stats, err := s.repo.GetReviewerStats(ctx, filter)
 	if err != nil {
-		s.log.ErrorContext(ctx, "failed to fetch reviewer stats", "op", "GetStats", "error", err)
+		s.log.ErrorContext(ctx, "failed to fetch reviewer stats", "op", "GetStats",
+			"team_name", filter.TeamName, "include_subteams", filter.IncludeSubTeams, "error", err)
 		return nil, err
 	}
 
 	return stats, nil
 }
+
+// GetDepartmentStats returns PR and review counts for teamName and each team below it, plus their total.
+func (s *Service) GetDepartmentStats(ctx context.Context, teamName string) (*DepartmentStats, error) {
+	if teamName == "" {
+		return nil, ErrNoTeam
+	}
+	teams, err := s.repo.GetTeamStats(ctx, teamName)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to fetch team stats", "op", "GetDepartmentStats", "team_name", teamName, "error", err)
+		return nil, err
+	}
+	if len(teams) == 0 {
+		return nil, ErrTeamNotFound
+	}
+
+	stats := &DepartmentStats{TeamName: teamName, Teams: teams}
+	stats.Total.TeamName = teamName
+	for _, t := range teams {
+		stats.Total.PullRequests += t.PullRequests
+		stats.Total.OpenPullRequests += t.OpenPullRequests
+		stats.Total.Reviews += t.Reviews
+	}
+	return stats, nil
+}
@@ -16,14 +16,22 @@ type MockStorer struct {
 	mock.Mock
 }
 
-func (m *MockStorer) GetReviewerStats(ctx context.Context) ([]ReviewerStat, error) {
-	args := m.Called(ctx)
+func (m *MockStorer) GetReviewerStats(ctx context.Context, filter StatsFilter) ([]ReviewerStat, error) {
+	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]ReviewerStat), args.Error(1)
 }
 
+func (m *MockStorer) GetTeamStats(ctx context.Context, teamName string) ([]TeamStat, error) {
+	args := m.Called(ctx, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]TeamStat), args.Error(1)
+}
+
 func setupService() (*Service, *MockStorer) {
 	mockRepo := new(MockStorer)
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
@@ -38,9 +46,9 @@ func TestService_GetStats(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		svc, mockRepo := setupService()
-		mockRepo.On("GetReviewerStats", ctx).Return(dummyStats, nil)
+		mockRepo.On("GetReviewerStats", ctx, StatsFilter{}).Return(dummyStats, nil)
 
-		stats, err := svc.GetStats(ctx)
+		stats, err := svc.GetStats(ctx, StatsFilter{})
 
 		require.NoError(t, err)
 		assert.Equal(t, dummyStats, stats)
@@ -49,14 +57,76 @@ func TestService_GetStats(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("team filter", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		filter := StatsFilter{TeamName: "backend", IncludeSubTeams: true}
+		mockRepo.On("GetReviewerStats", ctx, filter).Return(dummyStats[:1], nil)
+
+		stats, err := svc.GetStats(ctx, filter)
+
+		require.NoError(t, err)
+		assert.Len(t, stats, 1)
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("repository error", func(t *testing.T) {
 		svc, mockRepo := setupService()
 		expectedErr := errors.New("db connection failed")
-		mockRepo.On("GetReviewerStats", ctx).Return(nil, expectedErr)
-		stats, err := svc.GetStats(ctx)
+		mockRepo.On("GetReviewerStats", ctx, StatsFilter{}).Return(nil, expectedErr)
+		stats, err := svc.GetStats(ctx, StatsFilter{})
 		require.ErrorIs(t, err, expectedErr)
 		assert.Nil(t, stats)
 
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestService_GetDepartmentStats(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sums sub-teams into the total", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		teams := []TeamStat{
+			{TeamName: "billing", PullRequests: 2, OpenPullRequests: 1, Reviews: 3},
+			{TeamName: "finance", PullRequests: 1, OpenPullRequests: 1, Reviews: 2},
+			{TeamName: "payments"},
+		}
+		mockRepo.On("GetTeamStats", ctx, "finance").Return(teams, nil)
+
+		stats, err := svc.GetDepartmentStats(ctx, "finance")
+
+		require.NoError(t, err)
+		assert.Equal(t, teams, stats.Teams)
+		assert.Equal(t, TeamStat{TeamName: "finance", PullRequests: 3, OpenPullRequests: 2, Reviews: 5}, stats.Total)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("missing team name", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		_, err := svc.GetDepartmentStats(ctx, "")
+
+		require.ErrorIs(t, err, ErrNoTeam)
+		mockRepo.AssertNotCalled(t, "GetTeamStats")
+	})
+
+	t.Run("unknown team", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		mockRepo.On("GetTeamStats", ctx, "ghost").Return([]TeamStat{}, nil)
+
+		_, err := svc.GetDepartmentStats(ctx, "ghost")
+
+		require.ErrorIs(t, err, ErrTeamNotFound)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		expectedErr := errors.New("db connection failed")
+		mockRepo.On("GetTeamStats", ctx, "finance").Return(nil, expectedErr)
+
+		stats, err := svc.GetDepartmentStats(ctx, "finance")
+
+		require.ErrorIs(t, err, expectedErr)
+		assert.Nil(t, stats)
+	})
+}
@@ -0,0 +1,113 @@
+// Package event keeps the append-only timeline of pull request changes.
+// Events and their outbox messages are written with the same transaction as the change they describe.
+package event
+
+import (
+	"slices"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+const (
+	TypeCreated         = "CREATED"
+	TypeAssigned        = "ASSIGNED"
+	TypeUnassigned      = "UNASSIGNED"
+	TypeReassigned      = "REASSIGNED"
+	TypeStatusChanged   = "STATUS_CHANGED"
+	TypeReviewSubmitted = "REVIEW_SUBMITTED"
+	// TypeApprovalBypassed marks a merge done on the code host without the approvals the team requires.
+	TypeApprovalBypassed = "APPROVAL_BYPASSED"
+)
+
+var types = []string{
+	TypeCreated,
+	TypeAssigned,
+	TypeUnassigned,
+	TypeReassigned,
+	TypeStatusChanged,
+	TypeReviewSubmitted,
+	TypeApprovalBypassed,
+}
+
+func Known(eventType string) bool {
+	return slices.Contains(types, eventType)
+}
+
+func New(prID, eventType string) model.PREvent {
+	return model.PREvent{
+		PullRequestID: prID,
+		Type:          eventType,
+		CreatedAt:     time.Now(),
+	}
+}
+
+func Created(prID, authorID, status string) model.PREvent {
+	e := New(prID, TypeCreated)
+	e.UserID = authorID
+	e.ToStatus = status
+	return e
+}
+
+func Assigned(prID, userID string) model.PREvent {
+	e := New(prID, TypeAssigned)
+	e.UserID = userID
+	return e
+}
+
+func Unassigned(prID, userID, details string) model.PREvent {
+	e := New(prID, TypeUnassigned)
+	e.UserID = userID
+	e.Details = details
+	return e
+}
+
+func Reassigned(prID, oldUserID, newUserID string) model.PREvent {
+	e := New(prID, TypeReassigned)
+	e.UserID = newUserID
+	e.PreviousUserID = oldUserID
+	return e
+}
+
+func StatusChanged(prID, from, to string) model.PREvent {
+	e := New(prID, TypeStatusChanged)
+	e.FromStatus = from
+	e.ToStatus = to
+	return e
+}
+
+func ReviewSubmitted(prID, userID, verdict string) model.PREvent {
+	e := New(prID, TypeReviewSubmitted)
+	e.UserID = userID
+	e.Details = verdict
+	return e
+}
+
+func ApprovalBypassed(prID, details string) model.PREvent {
+	e := New(prID, TypeApprovalBypassed)
+	e.Details = details
+	return e
+}
+
+// Append stores events and queues them for webhook delivery using tx,
+// so they commit or roll back together with the caller's changes.
+func Append(tx *gorm.DB, events ...model.PREvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := tx.Create(&events).Error; err != nil {
+		return err
+	}
+
+	messages := make([]model.OutboxMessage, 0, len(events))
+	for _, e := range events {
+		msg, err := toOutboxMessage(e)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, msg)
+	}
+	return tx.Create(&messages).Error
+}
@@ -0,0 +1,44 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+// Payload is the JSON body delivered to webhook subscribers.
+type Payload struct {
+	EventID        uint64    `json:"event_id"`
+	Type           string    `json:"type"`
+	PullRequestID  string    `json:"pull_request_id"`
+	UserID         string    `json:"user_id,omitempty"`
+	PreviousUserID string    `json:"previous_user_id,omitempty"`
+	FromStatus     string    `json:"from_status,omitempty"`
+	ToStatus       string    `json:"to_status,omitempty"`
+	Details        string    `json:"details,omitempty"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+func toOutboxMessage(e model.PREvent) (model.OutboxMessage, error) {
+	body, err := json.Marshal(Payload{
+		EventID:        e.ID,
+		Type:           e.Type,
+		PullRequestID:  e.PullRequestID,
+		UserID:         e.UserID,
+		PreviousUserID: e.PreviousUserID,
+		FromStatus:     e.FromStatus,
+		ToStatus:       e.ToStatus,
+		Details:        e.Details,
+		OccurredAt:     e.CreatedAt,
+	})
+	if err != nil {
+		return model.OutboxMessage{}, err
+	}
+	return model.OutboxMessage{
+		EventType:     e.Type,
+		PullRequestID: e.PullRequestID,
+		Payload:       string(body),
+		CreatedAt:     e.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,36 @@
+package github
+
+import "github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+
+type PullRequestEventDTO struct {
+	Action      string         `json:"action"`
+	PullRequest PullRequestDTO `json:"pull_request"`
+	Repository  RepositoryDTO  `json:"repository"`
+}
+
+type PullRequestDTO struct {
+	Number int        `json:"number"`
+	Title  string     `json:"title"`
+	Draft  bool       `json:"draft"`
+	Merged bool       `json:"merged"`
+	User   UserDTO    `json:"user"`
+	Labels []LabelDTO `json:"labels"`
+}
+
+type LabelDTO struct {
+	Name string `json:"name"`
+}
+
+type RepositoryDTO struct {
+	FullName string `json:"full_name"`
+}
+
+type UserDTO struct {
+	Login string `json:"login"`
+}
+
+type ResponseDTO struct {
+	Status        string                 `json:"status"`
+	PullRequestID string                 `json:"pull_request_id,omitempty"`
+	PR            *pullrequest.PRInfoDTO `json:"pr,omitempty"`
+}
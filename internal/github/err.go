@@ -0,0 +1,9 @@
+package github
+
+import "errors"
+
+var (
+	ErrNotConfigured    = errors.New("github integration is not configured")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrBadPayload       = errors.New("invalid pull_request payload")
+)
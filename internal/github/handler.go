@@ -0,0 +1,93 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/res"
+)
+
+const maxPayloadSize = 5 << 20
+
+type Handler struct {
+	githubService Provider
+	conf          *configs.Config
+}
+
+func NewHandler(router *http.ServeMux, githubService Provider, conf *configs.Config) {
+	handler := &Handler{
+		githubService: githubService,
+		conf:          conf,
+	}
+	router.HandleFunc("POST /integrations/github/webhook", handler.Webhook())
+}
+
+func (h *Handler) Webhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "cannot read body")
+			return
+		}
+		if err = h.githubService.VerifySignature(body, r.Header.Get(SignatureHeader)); err != nil {
+			switch {
+			case errors.Is(err, ErrNotConfigured):
+				res.Error(w, http.StatusServiceUnavailable, "NOT_CONFIGURED", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+				return
+			}
+		}
+
+		deliveryID := r.Header.Get(DeliveryHeader)
+		if deliveryID == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", DeliveryHeader+" is required")
+			return
+		}
+		if r.Header.Get(EventHeader) != PullRequestEvent {
+			res.JSON(w, http.StatusOK, ResponseDTO{Status: StatusIgnored})
+			return
+		}
+
+		var evt PullRequestEventDTO
+		if err = json.Unmarshal(body, &evt); err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+
+		result, err := h.githubService.HandlePullRequest(ctx, deliveryID, evt)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrBadPayload):
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+				return
+			case errors.Is(err, pullrequest.ErrAuthorNotFound), errors.Is(err, pullrequest.ErrPRNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			case errors.Is(err, pullrequest.ErrPRExists):
+				res.Error(w, http.StatusConflict, "PR_EXISTS", err.Error())
+				return
+			case errors.Is(err, pullrequest.ErrInvalidTransition):
+				res.Error(w, http.StatusConflict, "INVALID_TRANSITION", err.Error())
+				return
+			case errors.Is(err, pullrequest.ErrNotApproved):
+				res.Error(w, http.StatusConflict, "NOT_APPROVED", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		resp := ToResponse(result)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
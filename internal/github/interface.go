@@ -0,0 +1,26 @@
+package github
+
+import (
+	"context"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+type PRService interface {
+	Create(context.Context, model.PullRequest) (*model.PullRequest, error)
+	MergeExternal(context.Context, string) (*model.PullRequest, error)
+	Close(context.Context, string) (*model.PullRequest, error)
+	Reopen(context.Context, string) (*model.PullRequest, error)
+	MarkReady(context.Context, string) (*model.PullRequest, error)
+}
+
+type DeliveryStorer interface {
+	// Transaction runs fn in a transaction that Claim and the PR changes made with fn's context join.
+	Transaction(ctx context.Context, fn func(context.Context) error) error
+	Claim(ctx context.Context, source, deliveryID string) (bool, error)
+}
+
+type Provider interface {
+	VerifySignature(body []byte, signature string) error
+	HandlePullRequest(ctx context.Context, deliveryID string, evt PullRequestEventDTO) (*Result, error)
+}
@@ -0,0 +1,15 @@
+package github
+
+import "github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+
+func ToResponse(result *Result) ResponseDTO {
+	resp := ResponseDTO{
+		Status:        result.Status,
+		PullRequestID: result.PullRequestID,
+	}
+	if result.PR != nil {
+		info := pullrequest.ToResponse(result.PR).PR
+		resp.PR = &info
+	}
+	return resp
+}
@@ -0,0 +1,33 @@
+package github
+
+import "github.com/SeeXWH/pr-reviewer-service/internal/model"
+
+const (
+	Source = "github"
+
+	EventHeader     = "X-GitHub-Event"
+	DeliveryHeader  = "X-GitHub-Delivery"
+	SignatureHeader = "X-Hub-Signature-256"
+
+	PullRequestEvent = "pull_request"
+)
+
+const (
+	ActionOpened         = "opened"
+	ActionReopened       = "reopened"
+	ActionClosed         = "closed"
+	ActionReadyForReview = "ready_for_review"
+)
+
+const (
+	StatusProcessed = "processed"
+	StatusIgnored   = "ignored"
+	StatusDuplicate = "duplicate"
+)
+
+// Result describes what happened to a delivery.
+type Result struct {
+	Status        string
+	PullRequestID string
+	PR            *model.PullRequest
+}
@@ -0,0 +1,35 @@
+package github
+
+import (
+	"context"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
+
+	"gorm.io/gorm/clause"
+)
+
+type Repository struct {
+	db *db.PostgresDB
+}
+
+func NewRepository(db *db.PostgresDB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Transaction(ctx context.Context, fn func(context.Context) error) error {
+	return r.db.Transaction(ctx, fn)
+}
+
+// Claim records the delivery and reports false if it has been seen before. A concurrent claim of the
+// same delivery waits for the first one's transaction and only wins if that one rolls back.
+func (r *Repository) Claim(ctx context.Context, source, deliveryID string) (bool, error) {
+	res := r.db.Conn(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.IntegrationDelivery{Source: source, DeliveryID: deliveryID, ReceivedAt: time.Now()})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
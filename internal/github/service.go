@@ -0,0 +1,147 @@
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+)
+
+type Service struct {
+	prService PRService
+	repo      DeliveryStorer
+	conf      configs.GitHub
+	log       *slog.Logger
+}
+
+func NewService(prService PRService, repo DeliveryStorer, conf configs.GitHub, log *slog.Logger) *Service {
+	return &Service{
+		prService: prService,
+		repo:      repo,
+		conf:      conf,
+		log:       log.With("component", "githubService"),
+	}
+}
+
+// VerifySignature checks the X-Hub-Signature-256 header against the configured secret.
+func (s *Service) VerifySignature(body []byte, signature string) error {
+	if s.conf.WebhookSecret == "" {
+		return ErrNotConfigured
+	}
+	digest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(digest)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(s.conf.WebhookSecret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s *Service) HandlePullRequest(ctx context.Context, deliveryID string, evt PullRequestEventDTO) (*Result, error) {
+	if evt.Repository.FullName == "" || evt.PullRequest.Number == 0 {
+		return nil, ErrBadPayload
+	}
+	prID := PullRequestID(evt.Repository.FullName, evt.PullRequest.Number)
+	log := s.log.With("op", "HandlePullRequest", "delivery_id", deliveryID, "action", evt.Action, "pr_id", prID)
+
+	// The delivery is recorded in the transaction that applies it: a failure or a crash before
+	// the commit leaves no trace of it, so GitHub's redelivery is processed again.
+	var result *Result
+	err := s.repo.Transaction(ctx, func(ctx context.Context) error {
+		fresh, err := s.repo.Claim(ctx, Source, deliveryID)
+		if err != nil {
+			log.ErrorContext(ctx, "failed to record delivery", "error", err)
+			return err
+		}
+		if !fresh {
+			log.InfoContext(ctx, "duplicate delivery ignored")
+			result = &Result{Status: StatusDuplicate, PullRequestID: prID}
+			return nil
+		}
+
+		pr, handled, err := s.apply(ctx, prID, evt)
+		if err != nil {
+			log.WarnContext(ctx, "failed to apply github event", "error", err)
+			return err
+		}
+		if !handled {
+			result = &Result{Status: StatusIgnored, PullRequestID: prID}
+			return nil
+		}
+		result = &Result{Status: StatusProcessed, PullRequestID: prID, PR: pr}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Status == StatusProcessed {
+		log.InfoContext(ctx, "github event applied", "status", result.PR.Status)
+	}
+	return result, nil
+}
+
+func (s *Service) apply(ctx context.Context, prID string, evt PullRequestEventDTO) (*model.PullRequest, bool, error) {
+	var pr *model.PullRequest
+	var err error
+	switch evt.Action {
+	case ActionOpened:
+		pr, err = s.create(ctx, prID, evt.PullRequest)
+	case ActionReopened:
+		pr, err = s.prService.Reopen(ctx, prID)
+	case ActionClosed:
+		if evt.PullRequest.Merged {
+			// GitHub has already merged it, so the approval quorum cannot block recording that.
+			pr, err = s.prService.MergeExternal(ctx, prID)
+		} else {
+			pr, err = s.prService.Close(ctx, prID)
+		}
+	case ActionReadyForReview:
+		pr, err = s.prService.MarkReady(ctx, prID)
+	default:
+		return nil, false, nil
+	}
+	return pr, true, err
+}
+
+func (s *Service) create(ctx context.Context, prID string, ghPR PullRequestDTO) (*model.PullRequest, error) {
+	pr := model.PullRequest{
+		ID:       prID,
+		Name:     ghPR.Title,
+		AuthorID: s.userID(ghPR.User.Login),
+	}
+	for _, l := range ghPR.Labels {
+		pr.Labels = append(pr.Labels, l.Name)
+	}
+	if ghPR.Draft {
+		pr.Status = pullrequest.DraftStatus
+	}
+	return s.prService.Create(ctx, pr)
+}
+
+// userID maps a GitHub login to a user ID; unmapped logins are used as is.
+func (s *Service) userID(login string) string {
+	if id, ok := s.conf.UserMap[login]; ok {
+		return id
+	}
+	return login
+}
+
+// PullRequestID builds the service PR id for a GitHub pull request, e.g. "org/repo#42".
+func PullRequestID(repoFullName string, number int) string {
+	return repoFullName + "#" + strconv.Itoa(number)
+}
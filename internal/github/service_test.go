@@ -0,0 +1,220 @@
+package github
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+	"github.com/SeeXWH/pr-reviewer-service/internal/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPRService struct {
+	mock.Mock
+}
+
+func (m *MockPRService) Create(ctx context.Context, pr model.PullRequest) (*model.PullRequest, error) {
+	args := m.Called(ctx, pr)
+	if val, ok := args.Get(0).(*model.PullRequest); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPRService) MergeExternal(ctx context.Context, id string) (*model.PullRequest, error) {
+	return m.statusCall("MergeExternal", ctx, id)
+}
+
+func (m *MockPRService) Close(ctx context.Context, id string) (*model.PullRequest, error) {
+	return m.statusCall("Close", ctx, id)
+}
+
+func (m *MockPRService) Reopen(ctx context.Context, id string) (*model.PullRequest, error) {
+	return m.statusCall("Reopen", ctx, id)
+}
+
+func (m *MockPRService) MarkReady(ctx context.Context, id string) (*model.PullRequest, error) {
+	return m.statusCall("MarkReady", ctx, id)
+}
+
+func (m *MockPRService) statusCall(method string, ctx context.Context, id string) (*model.PullRequest, error) {
+	args := m.MethodCalled(method, ctx, id)
+	if val, ok := args.Get(0).(*model.PullRequest); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockDeliveryStorer struct {
+	mock.Mock
+	// rolledBack is set when the last transaction ended with an error.
+	rolledBack bool
+}
+
+func (m *MockDeliveryStorer) Transaction(ctx context.Context, fn func(context.Context) error) error {
+	err := fn(ctx)
+	m.rolledBack = err != nil
+	return err
+}
+
+func (m *MockDeliveryStorer) Claim(ctx context.Context, source, deliveryID string) (bool, error) {
+	args := m.Called(ctx, source, deliveryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func setupService() (*Service, *MockPRService, *MockDeliveryStorer) {
+	prService := new(MockPRService)
+	repo := new(MockDeliveryStorer)
+	conf := configs.GitHub{
+		WebhookSecret: "secret",
+		UserMap:       map[string]string{"octocat": "u1"},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewService(prService, repo, conf, logger), prService, repo
+}
+
+func event(action string) PullRequestEventDTO {
+	return PullRequestEventDTO{
+		Action: action,
+		PullRequest: PullRequestDTO{
+			Number: 42,
+			Title:  "Add feature",
+			User:   UserDTO{Login: "octocat"},
+		},
+		Repository: RepositoryDTO{FullName: "org/repo"},
+	}
+}
+
+func TestService_VerifySignature(t *testing.T) {
+	svc, _, _ := setupService()
+	body := []byte(`{"action":"opened"}`)
+
+	require.NoError(t, svc.VerifySignature(body, webhook.Sign("secret", body)))
+	require.ErrorIs(t, svc.VerifySignature(body, webhook.Sign("other", body)), ErrInvalidSignature)
+	require.ErrorIs(t, svc.VerifySignature(body, "sha1=abc"), ErrInvalidSignature)
+	require.ErrorIs(t, svc.VerifySignature(body, ""), ErrInvalidSignature)
+
+	unconfigured := NewService(nil, nil, configs.GitHub{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.ErrorIs(t, unconfigured.VerifySignature(body, webhook.Sign("", body)), ErrNotConfigured)
+}
+
+func TestService_HandlePullRequest(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("opened creates pr with mapped author", func(t *testing.T) {
+		svc, prService, repo := setupService()
+		created := &model.PullRequest{ID: "org/repo#42", Status: pullrequest.OpenStatus}
+
+		repo.On("Claim", ctx, Source, "d1").Return(true, nil)
+		prService.On("Create", ctx, model.PullRequest{
+			ID:       "org/repo#42",
+			Name:     "Add feature",
+			AuthorID: "u1",
+		}).Return(created, nil)
+
+		result, err := svc.HandlePullRequest(ctx, "d1", event(ActionOpened))
+
+		require.NoError(t, err)
+		assert.Equal(t, StatusProcessed, result.Status)
+		assert.Equal(t, created, result.PR)
+	})
+
+	t.Run("draft opened creates draft with unmapped login", func(t *testing.T) {
+		svc, prService, repo := setupService()
+		evt := event(ActionOpened)
+		evt.PullRequest.Draft = true
+		evt.PullRequest.User.Login = "u7"
+		evt.PullRequest.Labels = []LabelDTO{{Name: "database"}}
+
+		repo.On("Claim", ctx, Source, "d2").Return(true, nil)
+		prService.On("Create", ctx, mock.MatchedBy(func(pr model.PullRequest) bool {
+			return pr.Status == pullrequest.DraftStatus && pr.AuthorID == "u7" &&
+				len(pr.Labels) == 1 && pr.Labels[0] == "database"
+		})).Return(&model.PullRequest{}, nil)
+
+		_, err := svc.HandlePullRequest(ctx, "d2", evt)
+
+		require.NoError(t, err)
+		prService.AssertExpectations(t)
+	})
+
+	t.Run("closed with merge merges", func(t *testing.T) {
+		svc, prService, repo := setupService()
+		evt := event(ActionClosed)
+		evt.PullRequest.Merged = true
+
+		repo.On("Claim", ctx, Source, "d3").Return(true, nil)
+		prService.On("MergeExternal", ctx, "org/repo#42").Return(&model.PullRequest{Status: pullrequest.MergeStatus}, nil)
+
+		result, err := svc.HandlePullRequest(ctx, "d3", evt)
+
+		require.NoError(t, err)
+		assert.Equal(t, StatusProcessed, result.Status)
+		prService.AssertNotCalled(t, "Close", ctx, mock.Anything)
+	})
+
+	t.Run("closed without merge closes", func(t *testing.T) {
+		svc, prService, repo := setupService()
+
+		repo.On("Claim", ctx, Source, "d4").Return(true, nil)
+		prService.On("Close", ctx, "org/repo#42").Return(&model.PullRequest{Status: pullrequest.ClosedStatus}, nil)
+
+		_, err := svc.HandlePullRequest(ctx, "d4", event(ActionClosed))
+
+		require.NoError(t, err)
+		prService.AssertExpectations(t)
+	})
+
+	t.Run("unknown action is ignored", func(t *testing.T) {
+		svc, prService, repo := setupService()
+
+		repo.On("Claim", ctx, Source, "d5").Return(true, nil)
+
+		result, err := svc.HandlePullRequest(ctx, "d5", event("labeled"))
+
+		require.NoError(t, err)
+		assert.Equal(t, StatusIgnored, result.Status)
+		prService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("replayed delivery is ignored", func(t *testing.T) {
+		svc, prService, repo := setupService()
+
+		repo.On("Claim", ctx, Source, "d1").Return(false, nil)
+
+		result, err := svc.HandlePullRequest(ctx, "d1", event(ActionOpened))
+
+		require.NoError(t, err)
+		assert.Equal(t, StatusDuplicate, result.Status)
+		prService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("failed delivery is rolled back with its claim", func(t *testing.T) {
+		svc, prService, repo := setupService()
+		evt := event(ActionClosed)
+
+		repo.On("Claim", ctx, Source, "d6").Return(true, nil)
+		prService.On("Close", ctx, "org/repo#42").Return(nil, pullrequest.ErrInvalidTransition)
+
+		_, err := svc.HandlePullRequest(ctx, "d6", evt)
+
+		require.ErrorIs(t, err, pullrequest.ErrInvalidTransition)
+		assert.True(t, repo.rolledBack)
+	})
+
+	t.Run("bad payload", func(t *testing.T) {
+		svc, _, repo := setupService()
+
+		_, err := svc.HandlePullRequest(ctx, "d7", PullRequestEventDTO{Action: ActionOpened})
+
+		require.ErrorIs(t, err, ErrBadPayload)
+		repo.AssertNotCalled(t, "Claim", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,43 @@
+package gitlab
+
+import "github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+
+type MergeRequestEventDTO struct {
+	ObjectKind       string              `json:"object_kind"`
+	User             UserDTO             `json:"user"`
+	Project          ProjectDTO          `json:"project"`
+	ObjectAttributes ObjectAttributesDTO `json:"object_attributes"`
+	Labels           []LabelDTO          `json:"labels"`
+}
+
+type ObjectAttributesDTO struct {
+	IID    int    `json:"iid"`
+	Title  string `json:"title"`
+	Action string `json:"action"`
+	Draft  bool   `json:"draft"`
+}
+
+type ProjectDTO struct {
+	PathWithNamespace string `json:"path_with_namespace"`
+}
+
+type LabelDTO struct {
+	Title string `json:"title"`
+}
+
+type UserDTO struct {
+	Username string `json:"username"`
+}
+
+type ReviewerDTO struct {
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	GitLabUsername string `json:"gitlab_username"`
+}
+
+type ResponseDTO struct {
+	Status            string                 `json:"status"`
+	PullRequestID     string                 `json:"pull_request_id,omitempty"`
+	AssignedReviewers []ReviewerDTO          `json:"assigned_reviewers"`
+	PR                *pullrequest.PRInfoDTO `json:"pr,omitempty"`
+}
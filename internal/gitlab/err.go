@@ -0,0 +1,9 @@
+package gitlab
+
+import "errors"
+
+var (
+	ErrNotConfigured = errors.New("gitlab integration is not configured")
+	ErrInvalidToken  = errors.New("invalid token")
+	ErrBadPayload    = errors.New("invalid merge request payload")
+)
@@ -0,0 +1,76 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/req"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/res"
+)
+
+type Handler struct {
+	gitlabService Provider
+	conf          *configs.Config
+}
+
+func NewHandler(router *http.ServeMux, gitlabService Provider, conf *configs.Config) {
+	handler := &Handler{
+		gitlabService: gitlabService,
+		conf:          conf,
+	}
+	router.HandleFunc("POST /integrations/gitlab/webhook", handler.Webhook())
+}
+
+func (h *Handler) Webhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+
+		if err := h.gitlabService.VerifyToken(r.Header.Get(TokenHeader)); err != nil {
+			switch {
+			case errors.Is(err, ErrNotConfigured):
+				res.Error(w, http.StatusServiceUnavailable, "NOT_CONFIGURED", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+				return
+			}
+		}
+		if r.Header.Get(EventHeader) != MergeRequestEvent {
+			res.JSON(w, http.StatusOK, ResponseDTO{Status: StatusIgnored, AssignedReviewers: []ReviewerDTO{}})
+			return
+		}
+
+		body, err := req.HandleBody[MergeRequestEventDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+
+		result, err := h.gitlabService.HandleMergeRequest(ctx, *body)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrBadPayload):
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+				return
+			case errors.Is(err, pullrequest.ErrAuthorNotFound), errors.Is(err, pullrequest.ErrPRNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			case errors.Is(err, pullrequest.ErrInvalidTransition):
+				res.Error(w, http.StatusConflict, "INVALID_TRANSITION", err.Error())
+				return
+			case errors.Is(err, pullrequest.ErrNotApproved):
+				res.Error(w, http.StatusConflict, "NOT_APPROVED", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		resp := ToResponse(result, h.gitlabService.GitLabUsername)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,22 @@
+package gitlab
+
+import (
+	"context"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+type PRService interface {
+	Get(context.Context, string) (*model.PullRequest, error)
+	Create(context.Context, model.PullRequest) (*model.PullRequest, error)
+	MergeExternal(context.Context, string) (*model.PullRequest, error)
+	Close(context.Context, string) (*model.PullRequest, error)
+	Reopen(context.Context, string) (*model.PullRequest, error)
+	MarkReady(context.Context, string) (*model.PullRequest, error)
+}
+
+type Provider interface {
+	VerifyToken(token string) error
+	HandleMergeRequest(ctx context.Context, evt MergeRequestEventDTO) (*Result, error)
+	GitLabUsername(userID string) string
+}
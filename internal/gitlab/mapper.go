@@ -0,0 +1,24 @@
+package gitlab
+
+import "github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+
+func ToResponse(result *Result, gitlabUsername func(string) string) ResponseDTO {
+	resp := ResponseDTO{
+		Status:            result.Status,
+		PullRequestID:     result.PullRequestID,
+		AssignedReviewers: []ReviewerDTO{},
+	}
+	if result.PR == nil {
+		return resp
+	}
+	for _, reviewer := range result.PR.Reviewers {
+		resp.AssignedReviewers = append(resp.AssignedReviewers, ReviewerDTO{
+			UserID:         reviewer.ID,
+			Username:       reviewer.Username,
+			GitLabUsername: gitlabUsername(reviewer.ID),
+		})
+	}
+	info := pullrequest.ToResponse(result.PR).PR
+	resp.PR = &info
+	return resp
+}
@@ -0,0 +1,31 @@
+package gitlab
+
+import "github.com/SeeXWH/pr-reviewer-service/internal/model"
+
+const (
+	EventHeader = "X-Gitlab-Event"
+	TokenHeader = "X-Gitlab-Token"
+
+	MergeRequestEvent = "Merge Request Hook"
+	MergeRequestKind  = "merge_request"
+)
+
+const (
+	ActionOpen   = "open"
+	ActionReopen = "reopen"
+	ActionUpdate = "update"
+	ActionMerge  = "merge"
+	ActionClose  = "close"
+)
+
+const (
+	StatusProcessed = "processed"
+	StatusIgnored   = "ignored"
+)
+
+// Result describes what happened to a merge request hook.
+type Result struct {
+	Status        string
+	PullRequestID string
+	PR            *model.PullRequest
+}
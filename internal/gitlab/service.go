@@ -0,0 +1,148 @@
+package gitlab
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"strconv"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+)
+
+type Service struct {
+	prService PRService
+	conf      configs.GitLab
+	usernames map[string]string
+	log       *slog.Logger
+}
+
+func NewService(prService PRService, conf configs.GitLab, log *slog.Logger) *Service {
+	usernames := make(map[string]string, len(conf.UserMap))
+	for username, userID := range conf.UserMap {
+		usernames[userID] = username
+	}
+	return &Service{
+		prService: prService,
+		conf:      conf,
+		usernames: usernames,
+		log:       log.With("component", "gitlabService"),
+	}
+}
+
+// VerifyToken checks the X-Gitlab-Token header against the configured token.
+func (s *Service) VerifyToken(token string) error {
+	if s.conf.WebhookToken == "" {
+		return ErrNotConfigured
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.conf.WebhookToken)) != 1 {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// HandleMergeRequest applies a merge request hook. GitLab retries failed hooks,
+// so replays of open, merge and close resolve to the current PR instead of a conflict.
+func (s *Service) HandleMergeRequest(ctx context.Context, evt MergeRequestEventDTO) (*Result, error) {
+	attrs := evt.ObjectAttributes
+	if evt.ObjectKind != MergeRequestKind || evt.Project.PathWithNamespace == "" || attrs.IID == 0 {
+		return nil, ErrBadPayload
+	}
+	prID := PullRequestID(evt.Project.PathWithNamespace, attrs.IID)
+	log := s.log.With("op", "HandleMergeRequest", "action", attrs.Action, "pr_id", prID)
+
+	var pr *model.PullRequest
+	var err error
+	switch attrs.Action {
+	case ActionOpen:
+		pr, err = s.create(ctx, prID, evt)
+		if errors.Is(err, pullrequest.ErrPRExists) {
+			pr, err = s.prService.Get(ctx, prID)
+		}
+	case ActionReopen:
+		pr, err = s.settle(ctx, prID, pullrequest.OpenStatus, s.prService.Reopen)
+	case ActionUpdate:
+		pr, err = s.update(ctx, prID, attrs)
+	case ActionMerge:
+		// GitLab has already merged it, so the approval quorum cannot block recording that.
+		pr, err = s.settle(ctx, prID, pullrequest.MergeStatus, s.prService.MergeExternal)
+	case ActionClose:
+		pr, err = s.settle(ctx, prID, pullrequest.ClosedStatus, s.prService.Close)
+	default:
+		return &Result{Status: StatusIgnored, PullRequestID: prID}, nil
+	}
+	if err != nil {
+		log.WarnContext(ctx, "failed to apply gitlab event", "error", err)
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "gitlab event applied", "status", pr.Status)
+	return &Result{Status: StatusProcessed, PullRequestID: prID, PR: pr}, nil
+}
+
+func (s *Service) create(ctx context.Context, prID string, evt MergeRequestEventDTO) (*model.PullRequest, error) {
+	pr := model.PullRequest{
+		ID:       prID,
+		Name:     evt.ObjectAttributes.Title,
+		AuthorID: s.userID(evt.User.Username),
+	}
+	for _, l := range evt.Labels {
+		pr.Labels = append(pr.Labels, l.Title)
+	}
+	if evt.ObjectAttributes.Draft {
+		pr.Status = pullrequest.DraftStatus
+	}
+	return s.prService.Create(ctx, pr)
+}
+
+// update marks a draft ready once the draft flag is cleared; other edits leave the PR as is.
+func (s *Service) update(ctx context.Context, prID string, attrs ObjectAttributesDTO) (*model.PullRequest, error) {
+	pr, err := s.prService.Get(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Status == pullrequest.DraftStatus && !attrs.Draft {
+		return s.prService.MarkReady(ctx, prID)
+	}
+	return pr, nil
+}
+
+// settle runs a status change and treats a PR already in the target status as success.
+func (s *Service) settle(
+	ctx context.Context,
+	prID, target string,
+	change func(context.Context, string) (*model.PullRequest, error),
+) (*model.PullRequest, error) {
+	pr, err := change(ctx, prID)
+	if !errors.Is(err, pullrequest.ErrInvalidTransition) {
+		return pr, err
+	}
+	current, getErr := s.prService.Get(ctx, prID)
+	if getErr != nil || current.Status != target {
+		return nil, err
+	}
+	return current, nil
+}
+
+// userID maps a GitLab username to a user ID; unmapped usernames are used as is.
+func (s *Service) userID(username string) string {
+	if id, ok := s.conf.UserMap[username]; ok {
+		return id
+	}
+	return username
+}
+
+// GitLabUsername is the reverse of userID, used to mention reviewers back in GitLab.
+func (s *Service) GitLabUsername(userID string) string {
+	if username, ok := s.usernames[userID]; ok {
+		return username
+	}
+	return userID
+}
+
+// PullRequestID builds the service PR id for a GitLab merge request, e.g. "group/project!42".
+func PullRequestID(projectPath string, iid int) string {
+	return projectPath + "!" + strconv.Itoa(iid)
+}
@@ -0,0 +1,236 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPRService struct {
+	mock.Mock
+}
+
+func (m *MockPRService) Create(ctx context.Context, pr model.PullRequest) (*model.PullRequest, error) {
+	args := m.Called(ctx, pr)
+	if val, ok := args.Get(0).(*model.PullRequest); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPRService) Get(ctx context.Context, id string) (*model.PullRequest, error) {
+	return m.idCall("Get", ctx, id)
+}
+
+func (m *MockPRService) MergeExternal(ctx context.Context, id string) (*model.PullRequest, error) {
+	return m.idCall("MergeExternal", ctx, id)
+}
+
+func (m *MockPRService) Close(ctx context.Context, id string) (*model.PullRequest, error) {
+	return m.idCall("Close", ctx, id)
+}
+
+func (m *MockPRService) Reopen(ctx context.Context, id string) (*model.PullRequest, error) {
+	return m.idCall("Reopen", ctx, id)
+}
+
+func (m *MockPRService) MarkReady(ctx context.Context, id string) (*model.PullRequest, error) {
+	return m.idCall("MarkReady", ctx, id)
+}
+
+func (m *MockPRService) idCall(method string, ctx context.Context, id string) (*model.PullRequest, error) {
+	args := m.MethodCalled(method, ctx, id)
+	if val, ok := args.Get(0).(*model.PullRequest); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func setupService() (*Service, *MockPRService) {
+	prService := new(MockPRService)
+	conf := configs.GitLab{
+		WebhookToken: "token",
+		UserMap:      map[string]string{"alice": "u1", "bob": "u2"},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewService(prService, conf, logger), prService
+}
+
+func hook(action string) MergeRequestEventDTO {
+	return MergeRequestEventDTO{
+		ObjectKind: MergeRequestKind,
+		User:       UserDTO{Username: "alice"},
+		Project:    ProjectDTO{PathWithNamespace: "group/project"},
+		ObjectAttributes: ObjectAttributesDTO{
+			IID:    3,
+			Title:  "Fix login",
+			Action: action,
+		},
+	}
+}
+
+func TestService_VerifyToken(t *testing.T) {
+	svc, _ := setupService()
+
+	require.NoError(t, svc.VerifyToken("token"))
+	require.ErrorIs(t, svc.VerifyToken("wrong"), ErrInvalidToken)
+	require.ErrorIs(t, svc.VerifyToken(""), ErrInvalidToken)
+
+	unconfigured := NewService(nil, configs.GitLab{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.ErrorIs(t, unconfigured.VerifyToken(""), ErrNotConfigured)
+}
+
+func TestService_GitLabUsername(t *testing.T) {
+	svc, _ := setupService()
+
+	assert.Equal(t, "bob", svc.GitLabUsername("u2"))
+	assert.Equal(t, "u9", svc.GitLabUsername("u9"))
+}
+
+func TestService_HandleMergeRequest(t *testing.T) {
+	ctx := context.Background()
+	const prID = "group/project!3"
+
+	t.Run("open creates pr with mapped author", func(t *testing.T) {
+		svc, prService := setupService()
+		created := &model.PullRequest{ID: prID, Status: pullrequest.OpenStatus}
+
+		prService.On("Create", ctx, model.PullRequest{ID: prID, Name: "Fix login", AuthorID: "u1"}).Return(created, nil)
+
+		result, err := svc.HandleMergeRequest(ctx, hook(ActionOpen))
+
+		require.NoError(t, err)
+		assert.Equal(t, StatusProcessed, result.Status)
+		assert.Equal(t, created, result.PR)
+	})
+
+	t.Run("replayed open returns existing pr", func(t *testing.T) {
+		svc, prService := setupService()
+		existing := &model.PullRequest{ID: prID, Status: pullrequest.OpenStatus}
+
+		prService.On("Create", ctx, mock.Anything).Return(nil, fmt.Errorf("%w: %s", pullrequest.ErrPRExists, prID))
+		prService.On("Get", ctx, prID).Return(existing, nil)
+
+		result, err := svc.HandleMergeRequest(ctx, hook(ActionOpen))
+
+		require.NoError(t, err)
+		assert.Equal(t, existing, result.PR)
+	})
+
+	t.Run("draft open creates draft", func(t *testing.T) {
+		svc, prService := setupService()
+		evt := hook(ActionOpen)
+		evt.ObjectAttributes.Draft = true
+
+		prService.On("Create", ctx, mock.MatchedBy(func(pr model.PullRequest) bool {
+			return pr.Status == pullrequest.DraftStatus
+		})).Return(&model.PullRequest{Status: pullrequest.DraftStatus}, nil)
+
+		_, err := svc.HandleMergeRequest(ctx, evt)
+
+		require.NoError(t, err)
+		prService.AssertExpectations(t)
+	})
+
+	t.Run("update clearing draft marks ready", func(t *testing.T) {
+		svc, prService := setupService()
+
+		prService.On("Get", ctx, prID).Return(&model.PullRequest{ID: prID, Status: pullrequest.DraftStatus}, nil)
+		prService.On("MarkReady", ctx, prID).Return(&model.PullRequest{ID: prID, Status: pullrequest.OpenStatus}, nil)
+
+		result, err := svc.HandleMergeRequest(ctx, hook(ActionUpdate))
+
+		require.NoError(t, err)
+		assert.Equal(t, pullrequest.OpenStatus, result.PR.Status)
+	})
+
+	t.Run("update of open pr returns it unchanged", func(t *testing.T) {
+		svc, prService := setupService()
+
+		prService.On("Get", ctx, prID).Return(&model.PullRequest{ID: prID, Status: pullrequest.OpenStatus}, nil)
+
+		_, err := svc.HandleMergeRequest(ctx, hook(ActionUpdate))
+
+		require.NoError(t, err)
+		prService.AssertNotCalled(t, "MarkReady", ctx, prID)
+	})
+
+	t.Run("merge merges", func(t *testing.T) {
+		svc, prService := setupService()
+
+		prService.On("MergeExternal", ctx, prID).Return(&model.PullRequest{Status: pullrequest.MergeStatus}, nil)
+
+		result, err := svc.HandleMergeRequest(ctx, hook(ActionMerge))
+
+		require.NoError(t, err)
+		assert.Equal(t, pullrequest.MergeStatus, result.PR.Status)
+	})
+
+	t.Run("replayed close resolves to closed pr", func(t *testing.T) {
+		svc, prService := setupService()
+
+		prService.On("Close", ctx, prID).Return(nil, fmt.Errorf("%w: CLOSED -> CLOSED", pullrequest.ErrInvalidTransition))
+		prService.On("Get", ctx, prID).Return(&model.PullRequest{Status: pullrequest.ClosedStatus}, nil)
+
+		result, err := svc.HandleMergeRequest(ctx, hook(ActionClose))
+
+		require.NoError(t, err)
+		assert.Equal(t, pullrequest.ClosedStatus, result.PR.Status)
+	})
+
+	t.Run("close of merged pr is a conflict", func(t *testing.T) {
+		svc, prService := setupService()
+
+		prService.On("Close", ctx, prID).Return(nil, fmt.Errorf("%w: MERGED -> CLOSED", pullrequest.ErrInvalidTransition))
+		prService.On("Get", ctx, prID).Return(&model.PullRequest{Status: pullrequest.MergeStatus}, nil)
+
+		_, err := svc.HandleMergeRequest(ctx, hook(ActionClose))
+
+		require.ErrorIs(t, err, pullrequest.ErrInvalidTransition)
+	})
+
+	t.Run("unknown action is ignored", func(t *testing.T) {
+		svc, prService := setupService()
+
+		result, err := svc.HandleMergeRequest(ctx, hook("approved"))
+
+		require.NoError(t, err)
+		assert.Equal(t, StatusIgnored, result.Status)
+		prService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("bad payload", func(t *testing.T) {
+		svc, _ := setupService()
+
+		_, err := svc.HandleMergeRequest(ctx, MergeRequestEventDTO{ObjectKind: "push"})
+
+		require.ErrorIs(t, err, ErrBadPayload)
+	})
+}
+
+func TestToResponse(t *testing.T) {
+	svc, _ := setupService()
+	result := &Result{
+		Status:        StatusProcessed,
+		PullRequestID: "group/project!3",
+		PR: &model.PullRequest{
+			ID:        "group/project!3",
+			Reviewers: []*model.User{{ID: "u2", Username: "Bob"}},
+		},
+	}
+
+	resp := ToResponse(result, svc.GitLabUsername)
+
+	require.Len(t, resp.AssignedReviewers, 1)
+	assert.Equal(t, ReviewerDTO{UserID: "u2", Username: "Bob", GitLabUsername: "bob"}, resp.AssignedReviewers[0])
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// AwayPeriod is a dated out-of-office interval [StartsAt, EndsAt) during which the user gets no reviews.
+type AwayPeriod struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement"`
+	UserID       string    `gorm:"not null;index"`
+	StartsAt     time.Time `gorm:"not null"`
+	EndsAt       time.Time `gorm:"not null"`
+	Reason       string
+	ReassignedAt *time.Time
+	CreatedAt    time.Time
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Escalation records a reviewer who missed the first-response SLA and what was done about it.
+type Escalation struct {
+	ID            uint64    `gorm:"primaryKey;autoIncrement"`
+	PullRequestID string    `gorm:"not null;uniqueIndex:idx_escalation_assignment"`
+	UserID        string    `gorm:"not null;uniqueIndex:idx_escalation_assignment"`
+	AssignedAt    time.Time `gorm:"not null;uniqueIndex:idx_escalation_assignment"`
+	TeamName      string    `gorm:"not null;index"`
+	Action        string    `gorm:"not null"`
+	NewUserID     string
+	Details       string
+	CreatedAt     time.Time
+}
@@ -0,0 +1,9 @@
+package model
+
+import "time"
+
+type IntegrationDelivery struct {
+	Source     string `gorm:"primaryKey"`
+	DeliveryID string `gorm:"primaryKey"`
+	ReceivedAt time.Time
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+type OutboxMessage struct {
+	ID            uint64 `gorm:"primaryKey;autoIncrement"`
+	EventType     string `gorm:"not null"`
+	PullRequestID string `gorm:"not null"`
+	Payload       string `gorm:"type:jsonb;not null"`
+	CreatedAt     time.Time
+	DispatchedAt  *time.Time `gorm:"index"`
+}
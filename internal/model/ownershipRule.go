@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// OwnershipRule is one line of a team's CODEOWNERS file; Owners holds comma-joined user IDs.
+type OwnershipRule struct {
+	TeamName  string `gorm:"primaryKey;column:team_name"`
+	Position  int    `gorm:"primaryKey"`
+	Pattern   string `gorm:"not null"`
+	Owners    string
+	CreatedAt time.Time
+}
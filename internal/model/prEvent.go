@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type PREvent struct {
+	ID             uint64 `gorm:"primaryKey;autoIncrement"`
+	PullRequestID  string `gorm:"not null;index"`
+	Type           string `gorm:"not null"`
+	UserID         string
+	PreviousUserID string
+	FromStatus     string
+	ToStatus       string
+	Details        string
+	CreatedAt      time.Time
+}
+
+func (PREvent) TableName() string {
+	return "pr_events"
+}
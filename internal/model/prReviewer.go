@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+type PRReviewer struct {
+	PullRequestID string `gorm:"primaryKey;column:pull_request_id"`
+	UserID        string `gorm:"primaryKey;column:user_id"`
+	Verdict       string `gorm:"not null;default:PENDING"`
+	VerdictAt     *time.Time
+	AssignedAt    time.Time `gorm:"not null;default:now()"`
+}
+
+func (PRReviewer) TableName() string {
+	return "pr_reviewers"
+}
@@ -7,8 +7,19 @@ type PullRequest struct {
 	Name      string
 	Status    string
 	AuthorID  string
-	Author    User    `gorm:"foreignKey:AuthorID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
-	Reviewers []*User `gorm:"many2many:pr_reviewers;"`
-	CreatedAt time.Time
-	MergedAt  *time.Time
+	Author    User         `gorm:"foreignKey:AuthorID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Reviewers []*User      `gorm:"many2many:pr_reviewers;"`
+	Reviews   []PRReviewer `gorm:"foreignKey:PullRequestID;references:ID"`
+	// TeamName is the team reviewers are picked from: the one named at creation or the author's primary team.
+	TeamName string `gorm:"column:team_name;index"`
+	// ChangedFiles are the paths touched by the PR, matched against CODEOWNERS rules.
+	ChangedFiles []string `gorm:"serializer:json;type:jsonb"`
+	// Labels give priority to reviewers whose tags match.
+	Labels []string `gorm:"serializer:json;type:jsonb"`
+	// FallbackLevel tells which level of the team's fallback chain supplied the reviewers
+	// assigned by the current operation; it is not stored.
+	FallbackLevel string `gorm:"-"`
+	CreatedAt     time.Time
+	MergedAt      *time.Time
+	ClosedAt      *time.Time
 }
@@ -0,0 +1,9 @@
+package model
+
+import "time"
+
+type ReviewerCursor struct {
+	TeamName   string `gorm:"primaryKey;column:team_name"`
+	LastUserID string
+	UpdatedAt  time.Time
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"slices"
+	"strings"
+)
+
+// NormalizeTags lowercases and trims tags, dropping blanks and duplicates.
+// A nil slice stays nil so callers can tell "not provided" from "clear".
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	normalized := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" && !slices.Contains(normalized, t) {
+			normalized = append(normalized, t)
+		}
+	}
+	return normalized
+}
+
+// MatchesAny reports whether the user has at least one of the labels as a tag.
+func (u *User) MatchesAny(labels []string) bool {
+	return slices.ContainsFunc(u.Tags, func(t string) bool { return slices.Contains(labels, t) })
+}
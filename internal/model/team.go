@@ -1,6 +1,27 @@
 package model
 
+const DefaultRequiredReviewers = 2
+
+// SLA policies: what happens to a reviewer who misses the team's first-response SLA.
+const (
+	SLAPolicyFlag     = "flag"
+	SLAPolicyReassign = "reassign"
+)
+
 type Team struct {
-	Name    string `gorm:"primaryKey;column:team_name"`
-	Members []User `gorm:"foreignKey:TeamName;references:Name"`
+	Name              string `gorm:"primaryKey;column:team_name"`
+	RequiredReviewers int    `gorm:"not null;default:2"`
+	// RequiredApprovals is the merge quorum; nil means the team never set one and follows the global default.
+	RequiredApprovals *int `gorm:"column:required_approvals"`
+	// PartnerTeam and LeadUserID are the fallback chain used when the team has no eligible reviewers.
+	PartnerTeam string `gorm:"column:partner_team"`
+	LeadUserID  string `gorm:"column:lead_user_id"`
+	// SLAHours is the first-response time in the reviewer's working hours; 0 disables tracking.
+	SLAHours  int          `gorm:"column:sla_hours;not null;default:0"`
+	SLAPolicy string       `gorm:"column:sla_policy;not null;default:flag"`
+	Members   []TeamMember `gorm:"foreignKey:TeamName;references:Name"`
+	// ParentTeam places the team in a hierarchy (e.g. a squad inside a department); empty for top-level teams.
+	ParentTeam string `gorm:"column:parent_team;index"`
+	// SubTeams is filled only when the hierarchy below the team is requested.
+	SubTeams []Team `gorm:"-"`
 }
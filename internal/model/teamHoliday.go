@@ -0,0 +1,8 @@
+package model
+
+// TeamHoliday is a day off for the whole team, matched against each member's local date.
+type TeamHoliday struct {
+	TeamName string `gorm:"primaryKey;column:team_name"`
+	Day      string `gorm:"primaryKey;column:day;size:10"`
+	Name     string
+}
@@ -0,0 +1,22 @@
+package model
+
+// Membership roles. Roles are informational; every active member is a review candidate.
+const (
+	RoleMember = "member"
+	RoleLead   = "lead"
+)
+
+// TeamMember links a user to one of their teams. A user with memberships has exactly one primary
+// team: the one whose settings apply to the PRs they author unless a PR names another team.
+type TeamMember struct {
+	TeamName  string `gorm:"primaryKey;column:team_name"`
+	UserID    string `gorm:"primaryKey;column:user_id;index"`
+	Role      string `gorm:"not null;default:member"`
+	IsPrimary bool   `gorm:"not null;default:false"`
+	User      User   `gorm:"foreignKey:UserID;references:ID"`
+}
+
+// ValidRole reports whether role is one of the membership roles.
+func ValidRole(role string) bool {
+	return role == RoleMember || role == RoleLead
+}
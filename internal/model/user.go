@@ -4,5 +4,19 @@ type User struct {
 	ID       string `gorm:"primaryKey;column:user_id"`
 	Username string
 	IsActive bool
-	TeamName string `gorm:"column:team_name;index"`
+	// TeamName is not stored: memberships live in team_members. Candidate queries set it to the team
+	// the user was picked from, single-user lookups to the primary team.
+	TeamName string `gorm:"column:team_name;->;-:migration"`
+	// Email receives review notifications; empty means chat mentions only.
+	Email string
+	// Tags name the areas of expertise matched against PR labels, e.g. "database" or "infra".
+	Tags []string `gorm:"serializer:json;type:jsonb"`
+	// Timezone is an IANA name, WorkStart and WorkEnd are local "HH:MM". Empty values mean always on duty.
+	Timezone  string
+	WorkStart string
+	WorkEnd   string
+	// WorkDays are the local weekdays the window applies to, "mon" to "sun"; empty means Monday to Friday.
+	WorkDays []string `gorm:"serializer:json;type:jsonb"`
+	// OnDuty is set by candidate queries: the user is within working hours and not on a team holiday.
+	OnDuty bool `gorm:"-"`
 }
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+type WebhookSubscription struct {
+	ID         string `gorm:"primaryKey;column:subscription_id"`
+	URL        string `gorm:"not null"`
+	Secret     string `gorm:"not null"`
+	EventTypes string
+	CreatedAt  time.Time
+}
+
+type WebhookDelivery struct {
+	ID             uint64 `gorm:"primaryKey;autoIncrement"`
+	OutboxID       uint64 `gorm:"not null;index"`
+	SubscriptionID string `gorm:"not null;index"`
+	Status         string `gorm:"not null;index"`
+	Attempts       int    `gorm:"not null;default:0"`
+	NextAttemptAt  time.Time
+	LastError      string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+}
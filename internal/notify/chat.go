@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ChatNotifier posts messages to an incoming webhook. The {"text": ...} payload is
+// understood by both Mattermost and Slack.
+type ChatNotifier struct {
+	url    string
+	client *http.Client
+}
+
+type chatPayload struct {
+	Text string `json:"text"`
+}
+
+func NewChatNotifier(url string) *ChatNotifier {
+	return &ChatNotifier{url: url, client: &http.Client{}}
+}
+
+func (n *ChatNotifier) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(chatPayload{Text: msg.Subject + "\n" + msg.Text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
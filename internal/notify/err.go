@@ -0,0 +1,5 @@
+package notify
+
+import "errors"
+
+var ErrUnknownEvent = errors.New("unknown notification event")
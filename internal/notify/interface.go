@@ -0,0 +1,16 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+// Notifier delivers a rendered message over one channel.
+type Notifier interface {
+	Send(context.Context, Message) error
+}
+
+type UserStorer interface {
+	GetUsers(ctx context.Context, userIDs []string) ([]model.User, error)
+}
@@ -0,0 +1,34 @@
+package notify
+
+import "github.com/SeeXWH/pr-reviewer-service/internal/model"
+
+const (
+	EventAssigned   = "assigned"
+	EventUnassigned = "unassigned"
+	EventMerged     = "merged"
+)
+
+// Notification is what services queue; recipients are resolved and the text is rendered
+// by the delivery workers, off the request path.
+type Notification struct {
+	Event           string
+	PullRequestID   string
+	PullRequestName string
+	RecipientIDs    []string
+	Reason          string
+}
+
+// Message is a rendered notification handed to every configured channel.
+type Message struct {
+	Event      string
+	Recipients []model.User
+	Subject    string
+	Text       string
+}
+
+type templateData struct {
+	Notification
+	Title      string
+	Names      string
+	Recipients []model.User
+}
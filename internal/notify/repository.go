@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
+)
+
+type Repository struct {
+	db *db.PostgresDB
+}
+
+func NewRepository(db *db.PostgresDB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) GetUsers(ctx context.Context, userIDs []string) ([]model.User, error) {
+	var users []model.User
+	err := r.db.PostgresDB.WithContext(ctx).
+		Where("user_id IN ?", userIDs).
+		Order("user_id").
+		Find(&users).Error
+	return users, err
+}
@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+)
+
+// Service queues notifications in memory and delivers them from background workers,
+// so a slow mail server or chat never holds up an API request.
+type Service struct {
+	repo      UserStorer
+	notifiers []Notifier
+	queue     chan Notification
+	conf      configs.Notify
+	log       *slog.Logger
+}
+
+func NewService(repo UserStorer, notifiers []Notifier, conf configs.Notify, log *slog.Logger) *Service {
+	return &Service{
+		repo:      repo,
+		notifiers: notifiers,
+		queue:     make(chan Notification, max(conf.QueueSize, 1)),
+		conf:      conf,
+		log:       log.With("component", "notifyService"),
+	}
+}
+
+// NewNotifiers builds the channels enabled in conf: SMTP when a host is set and
+// chat when a webhook URL is set.
+func NewNotifiers(conf configs.Notify) []Notifier {
+	var notifiers []Notifier
+	if conf.SMTPHost != "" {
+		notifiers = append(notifiers, NewSMTPNotifier(conf))
+	}
+	if conf.ChatWebhookURL != "" {
+		notifiers = append(notifiers, NewChatNotifier(conf.ChatWebhookURL))
+	}
+	return notifiers
+}
+
+// Notify queues n and returns at once. When the queue is full the notification is dropped.
+func (s *Service) Notify(ctx context.Context, n Notification) {
+	if len(s.notifiers) == 0 || len(n.RecipientIDs) == 0 {
+		return
+	}
+	select {
+	case s.queue <- n:
+	default:
+		s.log.WarnContext(ctx, "notification queue is full, dropping",
+			"event", n.Event,
+			"pr_id", n.PullRequestID,
+			"recipients_count", len(n.RecipientIDs),
+		)
+	}
+}
+
+func (s *Service) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range max(s.conf.Workers, 1) {
+		wg.Go(func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case n := <-s.queue:
+					if err := s.Deliver(ctx, n); err != nil && ctx.Err() == nil {
+						s.log.ErrorContext(ctx, "notification failed", "event", n.Event, "pr_id", n.PullRequestID, "error", err)
+					}
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// Deliver resolves the recipients of n, renders it and sends it over every channel.
+// A failing channel does not stop the others.
+func (s *Service) Deliver(ctx context.Context, n Notification) error {
+	if s.conf.SendTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.conf.SendTimeout)
+		defer cancel()
+	}
+
+	users, err := s.repo.GetUsers(ctx, n.RecipientIDs)
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return nil
+	}
+	msg, err := render(n, users)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, notifier := range s.notifiers {
+		if err = notifier.Send(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,183 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockUserStorer struct {
+	mock.Mock
+}
+
+func (m *MockUserStorer) GetUsers(ctx context.Context, userIDs []string) ([]model.User, error) {
+	args := m.Called(ctx, userIDs)
+	if val, ok := args.Get(0).([]model.User); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Send(ctx context.Context, msg Message) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestService_Notify(t *testing.T) {
+	ctx := context.Background()
+	n := Notification{Event: EventAssigned, PullRequestID: "pr-1", RecipientIDs: []string{"u1"}}
+
+	t.Run("queues without blocking and drops when full", func(t *testing.T) {
+		svc := NewService(new(MockUserStorer), []Notifier{new(MockNotifier)}, configs.Notify{QueueSize: 1}, discardLogger())
+
+		svc.Notify(ctx, n)
+		svc.Notify(ctx, n)
+
+		assert.Len(t, svc.queue, 1)
+	})
+
+	t.Run("ignored without channels or recipients", func(t *testing.T) {
+		svc := NewService(new(MockUserStorer), nil, configs.Notify{QueueSize: 4}, discardLogger())
+		svc.Notify(ctx, n)
+		assert.Empty(t, svc.queue)
+
+		svc = NewService(new(MockUserStorer), []Notifier{new(MockNotifier)}, configs.Notify{QueueSize: 4}, discardLogger())
+		svc.Notify(ctx, Notification{Event: EventAssigned, PullRequestID: "pr-1"})
+		assert.Empty(t, svc.queue)
+	})
+}
+
+func TestService_Deliver(t *testing.T) {
+	ctx := context.Background()
+	users := []model.User{{ID: "u1", Username: "alice", Email: "alice@example.com"}, {ID: "u2"}}
+	n := Notification{
+		Event:           EventAssigned,
+		PullRequestID:   "pr-1",
+		PullRequestName: "Add login",
+		RecipientIDs:    []string{"u1", "u2"},
+		Reason:          "reviewer deactivated",
+	}
+
+	t.Run("renders and sends to every channel", func(t *testing.T) {
+		repo := new(MockUserStorer)
+		first, second := new(MockNotifier), new(MockNotifier)
+		svc := NewService(repo, []Notifier{first, second}, configs.Notify{SendTimeout: time.Second}, discardLogger())
+
+		repo.On("GetUsers", mock.Anything, []string{"u1", "u2"}).Return(users, nil)
+		matchMessage := mock.MatchedBy(func(msg Message) bool {
+			return msg.Subject == `Review requested: "Add login" (pr-1)` &&
+				msg.Text == `@alice, @u2: you have been asked to review "Add login" (pr-1). Reason: reviewer deactivated.` &&
+				len(msg.Recipients) == 2
+		})
+		first.On("Send", mock.Anything, matchMessage).Return(errors.New("smtp down"))
+		second.On("Send", mock.Anything, matchMessage).Return(nil)
+
+		err := svc.Deliver(ctx, n)
+
+		require.ErrorContains(t, err, "smtp down")
+		first.AssertExpectations(t)
+		second.AssertExpectations(t)
+	})
+
+	t.Run("unknown users are skipped", func(t *testing.T) {
+		repo := new(MockUserStorer)
+		notifier := new(MockNotifier)
+		svc := NewService(repo, []Notifier{notifier}, configs.Notify{}, discardLogger())
+
+		repo.On("GetUsers", mock.Anything, []string{"u1", "u2"}).Return([]model.User{}, nil)
+
+		require.NoError(t, svc.Deliver(ctx, n))
+		notifier.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		repo := new(MockUserStorer)
+		svc := NewService(repo, []Notifier{new(MockNotifier)}, configs.Notify{}, discardLogger())
+
+		repo.On("GetUsers", mock.Anything, []string{"u1"}).Return(users[:1], nil)
+
+		err := svc.Deliver(ctx, Notification{Event: "bogus", PullRequestID: "pr-1", RecipientIDs: []string{"u1"}})
+		assert.ErrorIs(t, err, ErrUnknownEvent)
+	})
+}
+
+func TestRender(t *testing.T) {
+	users := []model.User{{ID: "u1", Username: "alice"}}
+
+	msg, err := render(Notification{Event: EventMerged, PullRequestID: "pr-7", RecipientIDs: []string{"u1"}}, users)
+	require.NoError(t, err)
+	assert.Equal(t, "Merged: pull request pr-7", msg.Subject)
+	assert.Equal(t, "@alice: pull request pr-7 has been merged, no further review is needed.", msg.Text)
+
+	msg, err = render(Notification{Event: EventUnassigned, PullRequestID: "pr-7", PullRequestName: "Fix", Reason: "reshuffled"}, users)
+	require.NoError(t, err)
+	assert.Equal(t, `@alice: you have been removed from the review of "Fix" (pr-7). Reason: reshuffled.`, msg.Text)
+}
+
+func TestChatNotifier_Send(t *testing.T) {
+	var payload chatPayload
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	notifier := NewChatNotifier(server.URL)
+	msg := Message{Subject: "Merged: pull request pr-1", Text: "@alice: merged"}
+
+	require.NoError(t, notifier.Send(context.Background(), msg))
+	assert.Equal(t, "Merged: pull request pr-1\n@alice: merged", payload.Text)
+
+	status = http.StatusBadGateway
+	assert.ErrorContains(t, notifier.Send(context.Background(), msg), "unexpected status 502")
+}
+
+func TestBuildMail(t *testing.T) {
+	now := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+	mail := string(buildMail("bot@example.com", []string{"a@example.com", "b@example.com"}, Message{
+		Subject: "Review requested: pr-1",
+		Text:    "line one\nline two",
+	}, now))
+
+	assert.True(t, strings.HasPrefix(mail, "From: bot@example.com\r\nTo: a@example.com, b@example.com\r\n"))
+	assert.Contains(t, mail, "Subject: Review requested: pr-1\r\n")
+	assert.Contains(t, mail, "Content-Type: text/plain; charset=utf-8\r\n")
+	assert.True(t, strings.HasSuffix(mail, "\r\n\r\nline one\r\nline two\r\n"))
+}
+
+func TestSMTPNotifier_SkipsRecipientsWithoutEmail(t *testing.T) {
+	notifier := NewSMTPNotifier(configs.Notify{SMTPHost: "127.0.0.1", SMTPPort: "1"})
+
+	err := notifier.Send(context.Background(), Message{Recipients: []model.User{{ID: "u1"}}})
+
+	assert.NoError(t, err)
+}
+
+func TestNewNotifiers(t *testing.T) {
+	assert.Empty(t, NewNotifiers(configs.Notify{}))
+	assert.Len(t, NewNotifiers(configs.Notify{SMTPHost: "mail", ChatWebhookURL: "http://chat"}), 2)
+}
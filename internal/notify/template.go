@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+type messageTemplate struct {
+	subject *template.Template
+	text    *template.Template
+}
+
+var templates = map[string]messageTemplate{
+	EventAssigned: newTemplate(EventAssigned,
+		"Review requested: {{.Title}}",
+		"{{.Names}}: you have been asked to review {{.Title}}.{{with .Reason}} Reason: {{.}}.{{end}}",
+	),
+	EventUnassigned: newTemplate(EventUnassigned,
+		"Review no longer needed: {{.Title}}",
+		"{{.Names}}: you have been removed from the review of {{.Title}}.{{with .Reason}} Reason: {{.}}.{{end}}",
+	),
+	EventMerged: newTemplate(EventMerged,
+		"Merged: {{.Title}}",
+		"{{.Names}}: {{.Title}} has been merged, no further review is needed.",
+	),
+}
+
+func newTemplate(name, subject, text string) messageTemplate {
+	return messageTemplate{
+		subject: template.Must(template.New(name + "_subject").Parse(subject)),
+		text:    template.Must(template.New(name + "_text").Parse(text)),
+	}
+}
+
+func render(n Notification, recipients []model.User) (Message, error) {
+	tmpl, ok := templates[n.Event]
+	if !ok {
+		return Message{}, fmt.Errorf("%w: %s", ErrUnknownEvent, n.Event)
+	}
+	data := templateData{
+		Notification: n,
+		Title:        title(n),
+		Names:        names(recipients),
+		Recipients:   recipients,
+	}
+
+	var subject, text strings.Builder
+	if err := tmpl.subject.Execute(&subject, data); err != nil {
+		return Message{}, err
+	}
+	if err := tmpl.text.Execute(&text, data); err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Event:      n.Event,
+		Recipients: recipients,
+		Subject:    subject.String(),
+		Text:       text.String(),
+	}, nil
+}
+
+func title(n Notification) string {
+	if n.PullRequestName == "" {
+		return "pull request " + n.PullRequestID
+	}
+	return fmt.Sprintf("%q (%s)", n.PullRequestName, n.PullRequestID)
+}
+
+// names mentions every recipient by username so chat clients highlight the message.
+func names(users []model.User) string {
+	mentions := make([]string, 0, len(users))
+	for _, u := range users {
+		name := u.Username
+		if name == "" {
+			name = u.ID
+		}
+		mentions = append(mentions, "@"+name)
+	}
+	return strings.Join(mentions, ", ")
+}
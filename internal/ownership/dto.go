@@ -0,0 +1,16 @@
+package ownership
+
+type UploadRequestDTO struct {
+	TeamName   string `json:"team_name"`
+	Codeowners string `json:"codeowners"`
+}
+
+type RuleDTO struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+type RulesResponseDTO struct {
+	TeamName string    `json:"team_name"`
+	Rules    []RuleDTO `json:"rules"`
+}
@@ -0,0 +1,9 @@
+package ownership
+
+import "errors"
+
+var (
+	ErrTeamNotFound = errors.New("resource not found")
+	ErrBadRules     = errors.New("invalid codeowners")
+	ErrUnknownOwner = errors.New("unknown owner")
+)
@@ -0,0 +1,85 @@
+package ownership
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/req"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/res"
+)
+
+type Handler struct {
+	ownershipService Provider
+	conf             *configs.Config
+}
+
+func NewHandler(router *http.ServeMux, ownershipService Provider, conf *configs.Config) {
+	handler := &Handler{
+		ownershipService: ownershipService,
+		conf:             conf,
+	}
+	router.HandleFunc("POST /team/uploadCodeowners", handler.Upload())
+	router.HandleFunc("GET /team/codeowners", handler.Get())
+}
+
+func (h *Handler) Upload() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		reqBody, err := req.HandleBody[UploadRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+		if reqBody.TeamName == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+			return
+		}
+
+		rules, err := h.ownershipService.Upload(ctx, reqBody.TeamName, reqBody.Codeowners)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrBadRules), errors.Is(err, ErrUnknownOwner):
+				res.Error(w, http.StatusBadRequest, "BAD_CODEOWNERS", err.Error())
+				return
+			case errors.Is(err, ErrTeamNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		resp := ToResponse(reqBody.TeamName, rules)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) Get() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+
+		teamName := r.URL.Query().Get("team_name")
+		if teamName == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+			return
+		}
+
+		rules, err := h.ownershipService.Get(ctx, teamName)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrTeamNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		resp := ToResponse(teamName, rules)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,21 @@
+package ownership
+
+import (
+	"context"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+type Storer interface {
+	TeamExists(ctx context.Context, teamName string) (bool, error)
+	MissingUsers(ctx context.Context, userIDs []string) ([]string, error)
+	ReplaceRules(ctx context.Context, teamName string, rules []model.OwnershipRule) error
+	GetRules(ctx context.Context, teamName string) ([]model.OwnershipRule, error)
+	ListRules(ctx context.Context) ([]model.OwnershipRule, error)
+	GetActiveUsers(ctx context.Context, userIDs []string) ([]model.User, error)
+}
+
+type Provider interface {
+	Upload(ctx context.Context, teamName, content string) ([]Rule, error)
+	Get(ctx context.Context, teamName string) ([]Rule, error)
+}
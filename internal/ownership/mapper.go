@@ -0,0 +1,16 @@
+package ownership
+
+func ToResponse(teamName string, rules []Rule) RulesResponseDTO {
+	resp := RulesResponseDTO{
+		TeamName: teamName,
+		Rules:    make([]RuleDTO, len(rules)),
+	}
+	for i, rule := range rules {
+		owners := rule.Owners
+		if owners == nil {
+			owners = []string{}
+		}
+		resp.Rules[i] = RuleDTO{Pattern: rule.Pattern, Owners: owners}
+	}
+	return resp
+}
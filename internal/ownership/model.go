@@ -0,0 +1,75 @@
+package ownership
+
+import (
+	"path"
+	"strings"
+)
+
+// Rule is a parsed CODEOWNERS line.
+type Rule struct {
+	Pattern string
+	Owners  []string
+}
+
+// Match reports whether a CODEOWNERS pattern matches file, following the usual rules:
+// a leading "/" anchors the pattern to the repository root, a pattern without a slash
+// matches at any depth, a trailing "/" or a matched directory covers everything below it,
+// and "**" matches any number of directories.
+func Match(pattern, file string) bool {
+	file = strings.Trim(file, "/")
+	if pattern == "" || file == "" {
+		return false
+	}
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		return false
+	}
+	if !anchored && !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(file, "/"))
+}
+
+func matchSegments(pattern, file []string) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(file); i++ {
+			if matchSegments(pattern[1:], file[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(file) == 0 {
+		return false
+	}
+	ok, err := path.Match(pattern[0], file[0])
+	if err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], file[1:])
+}
+
+// validPattern reports whether every segment of pattern is a well-formed glob.
+func validPattern(pattern string) bool {
+	for _, segment := range strings.Split(strings.Trim(pattern, "/"), "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return false
+		}
+	}
+	return strings.Trim(pattern, "/") != ""
+}
+
+func joinOwners(owners []string) string {
+	return strings.Join(owners, ",")
+}
+
+func splitOwners(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	return strings.Split(raw, ",")
+}
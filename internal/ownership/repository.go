@@ -0,0 +1,99 @@
+package ownership
+
+import (
+	"context"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/user"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *db.PostgresDB
+}
+
+func NewRepository(db *db.PostgresDB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	var count int64
+	err := r.db.PostgresDB.WithContext(ctx).Model(&model.Team{}).Where("team_name = ?", teamName).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *Repository) MissingUsers(ctx context.Context, userIDs []string) ([]string, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	var found []string
+	err := r.db.PostgresDB.WithContext(ctx).
+		Model(&model.User{}).
+		Where("user_id IN ?", userIDs).
+		Pluck("user_id", &found).Error
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(found))
+	for _, id := range found {
+		known[id] = true
+	}
+	var missing []string
+	for _, id := range userIDs {
+		if !known[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing, nil
+}
+
+// ReplaceRules swaps the team's whole rule set, like re-uploading a CODEOWNERS file.
+func (r *Repository) ReplaceRules(ctx context.Context, teamName string, rules []model.OwnershipRule) error {
+	return r.db.PostgresDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("team_name = ?", teamName).Delete(&model.OwnershipRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		return tx.Create(&rules).Error
+	})
+}
+
+func (r *Repository) GetRules(ctx context.Context, teamName string) ([]model.OwnershipRule, error) {
+	var rules []model.OwnershipRule
+	err := r.db.PostgresDB.WithContext(ctx).
+		Where("team_name = ?", teamName).
+		Order("position").
+		Find(&rules).Error
+	return rules, err
+}
+
+func (r *Repository) ListRules(ctx context.Context) ([]model.OwnershipRule, error) {
+	var rules []model.OwnershipRule
+	err := r.db.PostgresDB.WithContext(ctx).
+		Order("team_name").
+		Order("position").
+		Find(&rules).Error
+	return rules, err
+}
+
+func (r *Repository) GetActiveUsers(ctx context.Context, userIDs []string) ([]model.User, error) {
+	var users []model.User
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+	now := time.Now()
+	err := r.db.PostgresDB.WithContext(ctx).
+		Scopes(user.AvailableAt(now), user.WithPrimaryTeam).
+		Where("users.user_id IN ? AND users.is_active = ?", userIDs, true).
+		Order("users.user_id").
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, user.MarkOnDuty(r.db.PostgresDB.WithContext(ctx), users, now)
+}
@@ -0,0 +1,210 @@
+package ownership
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+type Service struct {
+	repo Storer
+	log  *slog.Logger
+}
+
+func NewService(repo Storer, log *slog.Logger) *Service {
+	return &Service{
+		repo: repo,
+		log:  log.With("component", "ownershipService"),
+	}
+}
+
+// Upload replaces the team's rules with the ones parsed from a CODEOWNERS file.
+func (s *Service) Upload(ctx context.Context, teamName, content string) ([]Rule, error) {
+	log := s.log.With("op", "Upload", "team_name", teamName)
+
+	rules, err := Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := s.repo.TeamExists(ctx, teamName)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to check team", "error", err)
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrTeamNotFound
+	}
+	missing, err := s.repo.MissingUsers(ctx, owners(rules))
+	if err != nil {
+		log.ErrorContext(ctx, "failed to check owners", "error", err)
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownOwner, strings.Join(missing, ", "))
+	}
+
+	now := time.Now()
+	rows := make([]model.OwnershipRule, len(rules))
+	for i, rule := range rules {
+		rows[i] = model.OwnershipRule{
+			TeamName:  teamName,
+			Position:  i,
+			Pattern:   rule.Pattern,
+			Owners:    joinOwners(rule.Owners),
+			CreatedAt: now,
+		}
+	}
+	if err = s.repo.ReplaceRules(ctx, teamName, rows); err != nil {
+		log.ErrorContext(ctx, "failed to save rules", "error", err)
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "codeowners uploaded", "rules_count", len(rules))
+	return rules, nil
+}
+
+func (s *Service) Get(ctx context.Context, teamName string) ([]Rule, error) {
+	exists, err := s.repo.TeamExists(ctx, teamName)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to check team", "op", "Get", "team_name", teamName, "error", err)
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrTeamNotFound
+	}
+	rows, err := s.repo.GetRules(ctx, teamName)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to fetch rules", "op", "Get", "team_name", teamName, "error", err)
+		return nil, err
+	}
+	return toRules(rows), nil
+}
+
+// ResolveOwners returns the active owners of every changed file matched by some rule.
+// Within one team's file the last matching rule wins; owners from different teams are combined.
+func (s *Service) ResolveOwners(ctx context.Context, files []string) (map[string][]model.User, error) {
+	log := s.log.With("op", "ResolveOwners")
+
+	result := make(map[string][]model.User)
+	if len(files) == 0 {
+		return result, nil
+	}
+	rows, err := s.repo.ListRules(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to fetch rules", "error", err)
+		return nil, err
+	}
+
+	byTeam := make(map[string][]model.OwnershipRule)
+	var teams []string
+	for _, row := range rows {
+		if _, ok := byTeam[row.TeamName]; !ok {
+			teams = append(teams, row.TeamName)
+		}
+		byTeam[row.TeamName] = append(byTeam[row.TeamName], row)
+	}
+
+	fileOwners := make(map[string][]string, len(files))
+	var allOwners []string
+	for _, file := range files {
+		for _, team := range teams {
+			ids := lastMatch(byTeam[team], file)
+			for _, id := range ids {
+				if !slices.Contains(fileOwners[file], id) {
+					fileOwners[file] = append(fileOwners[file], id)
+				}
+			}
+			allOwners = append(allOwners, ids...)
+		}
+	}
+	if len(allOwners) == 0 {
+		return result, nil
+	}
+
+	slices.Sort(allOwners)
+	users, err := s.repo.GetActiveUsers(ctx, slices.Compact(allOwners))
+	if err != nil {
+		log.ErrorContext(ctx, "failed to fetch owners", "error", err)
+		return nil, err
+	}
+	active := make(map[string]model.User, len(users))
+	for _, u := range users {
+		active[u.ID] = u
+	}
+	for file, ids := range fileOwners {
+		for _, id := range ids {
+			if u, ok := active[id]; ok {
+				result[file] = append(result[file], u)
+			}
+		}
+		if len(result[file]) == 0 {
+			log.WarnContext(ctx, "no active owner for path", "path", file)
+		}
+	}
+	return result, nil
+}
+
+// Parse reads CODEOWNERS content: one "pattern owner..." rule per line, "#" starts a comment
+// and a leading "@" on owners is optional.
+func Parse(content string) ([]Rule, error) {
+	var rules []Rule
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if !validPattern(fields[0]) {
+			return nil, fmt.Errorf("%w: line %d: bad pattern %q", ErrBadRules, line, fields[0])
+		}
+		rule := Rule{Pattern: fields[0]}
+		for _, owner := range fields[1:] {
+			owner = strings.TrimPrefix(owner, "@")
+			if owner == "" || strings.Contains(owner, ",") {
+				return nil, fmt.Errorf("%w: line %d: bad owner %q", ErrBadRules, line, owner)
+			}
+			rule.Owners = append(rule.Owners, owner)
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadRules, err)
+	}
+	return rules, nil
+}
+
+func lastMatch(rules []model.OwnershipRule, file string) []string {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if Match(rules[i].Pattern, file) {
+			return splitOwners(rules[i].Owners)
+		}
+	}
+	return nil
+}
+
+func owners(rules []Rule) []string {
+	var ids []string
+	for _, rule := range rules {
+		for _, id := range rule.Owners {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+func toRules(rows []model.OwnershipRule) []Rule {
+	rules := make([]Rule, len(rows))
+	for i, row := range rows {
+		rules[i] = Rule{Pattern: row.Pattern, Owners: splitOwners(row.Owners)}
+	}
+	return rules
+}
@@ -0,0 +1,191 @@
+package ownership
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockStorer struct {
+	mock.Mock
+}
+
+func (m *MockStorer) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	args := m.Called(ctx, teamName)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStorer) MissingUsers(ctx context.Context, userIDs []string) ([]string, error) {
+	args := m.Called(ctx, userIDs)
+	if val, ok := args.Get(0).([]string); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStorer) ReplaceRules(ctx context.Context, teamName string, rules []model.OwnershipRule) error {
+	args := m.Called(ctx, teamName, rules)
+	return args.Error(0)
+}
+
+func (m *MockStorer) GetRules(ctx context.Context, teamName string) ([]model.OwnershipRule, error) {
+	args := m.Called(ctx, teamName)
+	if val, ok := args.Get(0).([]model.OwnershipRule); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStorer) ListRules(ctx context.Context) ([]model.OwnershipRule, error) {
+	args := m.Called(ctx)
+	if val, ok := args.Get(0).([]model.OwnershipRule); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStorer) GetActiveUsers(ctx context.Context, userIDs []string) ([]model.User, error) {
+	args := m.Called(ctx, userIDs)
+	if val, ok := args.Get(0).([]model.User); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func setupService() (*Service, *MockStorer) {
+	mockRepo := new(MockStorer)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewService(mockRepo, logger), mockRepo
+}
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		pattern string
+		file    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "internal/user/service.go", true},
+		{"*.go", "README.md", false},
+		{"/docs/", "docs/api/intro.md", true},
+		{"/docs/", "internal/docs/x.md", false},
+		{"docs/", "docs/intro.md", true},
+		{"internal/user", "internal/user/service.go", true},
+		{"internal/*.go", "internal/user/service.go", false},
+		{"internal/**/*.go", "internal/user/service.go", true},
+		{"internal/**/*.go", "internal/main.go", true},
+		{"**/migrations/*.sql", "db/migrations/001.sql", true},
+		{"/Makefile", "Makefile", true},
+		{"/Makefile", "sub/Makefile", false},
+		{"*", "anything/at/all.txt", true},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, Match(c.pattern, c.file), "%s vs %s", c.pattern, c.file)
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Run("reads rules and skips comments", func(t *testing.T) {
+		rules, err := Parse("# backend owners\n\n*.go @u1 u2\n/docs/ u3 # writers\n/vendor/\n")
+
+		require.NoError(t, err)
+		assert.Equal(t, []Rule{
+			{Pattern: "*.go", Owners: []string{"u1", "u2"}},
+			{Pattern: "/docs/", Owners: []string{"u3"}},
+			{Pattern: "/vendor/"},
+		}, rules)
+	})
+
+	t.Run("bad pattern", func(t *testing.T) {
+		_, err := Parse("*.go u1\n[abc u2\n")
+
+		require.ErrorIs(t, err, ErrBadRules)
+		assert.Contains(t, err.Error(), "line 2")
+	})
+}
+
+func TestService_Upload(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("replaces team rules", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		mockRepo.On("TeamExists", ctx, "backend").Return(true, nil)
+		mockRepo.On("MissingUsers", ctx, []string{"u1", "u2"}).Return([]string(nil), nil)
+		mockRepo.On("ReplaceRules", ctx, "backend", mock.MatchedBy(func(rows []model.OwnershipRule) bool {
+			return len(rows) == 2 &&
+				rows[0].Position == 0 && rows[0].Pattern == "*.go" && rows[0].Owners == "u1,u2" &&
+				rows[1].Position == 1 && rows[1].Owners == "u2"
+		})).Return(nil)
+
+		rules, err := svc.Upload(ctx, "backend", "*.go u1 u2\n/api/ u2\n")
+
+		require.NoError(t, err)
+		assert.Len(t, rules, 2)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown owner", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		mockRepo.On("TeamExists", ctx, "backend").Return(true, nil)
+		mockRepo.On("MissingUsers", ctx, []string{"ghost"}).Return([]string{"ghost"}, nil)
+
+		_, err := svc.Upload(ctx, "backend", "*.go ghost")
+
+		require.ErrorIs(t, err, ErrUnknownOwner)
+		mockRepo.AssertNotCalled(t, "ReplaceRules", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		mockRepo.On("TeamExists", ctx, "ghost").Return(false, nil)
+
+		_, err := svc.Upload(ctx, "ghost", "*.go u1")
+
+		require.ErrorIs(t, err, ErrTeamNotFound)
+	})
+}
+
+func TestService_ResolveOwners(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("last matching rule per team wins and teams combine", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		rules := []model.OwnershipRule{
+			{TeamName: "backend", Position: 0, Pattern: "*", Owners: "u1"},
+			{TeamName: "backend", Position: 1, Pattern: "/api/", Owners: "u2"},
+			{TeamName: "dba", Position: 0, Pattern: "*.sql", Owners: "u3,u4"},
+		}
+
+		mockRepo.On("ListRules", ctx).Return(rules, nil)
+		mockRepo.On("GetActiveUsers", ctx, []string{"u1", "u2", "u3", "u4"}).
+			Return([]model.User{{ID: "u1"}, {ID: "u2"}, {ID: "u3"}}, nil)
+
+		owners, err := svc.ResolveOwners(ctx, []string{"api/handler.go", "db/001.sql"})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]model.User{
+			"api/handler.go": {{ID: "u2"}},
+			"db/001.sql":     {{ID: "u1"}, {ID: "u3"}},
+		}, owners)
+	})
+
+	t.Run("no files skips lookup", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		owners, err := svc.ResolveOwners(ctx, nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, owners)
+		mockRepo.AssertNotCalled(t, "ListRules", mock.Anything)
+	})
+}
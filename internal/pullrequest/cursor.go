@@ -0,0 +1,34 @@
+package pullrequest
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+)
+
+// PageCursor points at the last PR of a page in (created_at, pull_request_id) order.
+type PageCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+func encodeCursor(c PageCursor) string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(s string) (*PageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrBadCursor
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, ErrBadCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return nil, ErrBadCursor
+	}
+	return &PageCursor{CreatedAt: createdAt, ID: id}, nil
+}
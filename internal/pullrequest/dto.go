@@ -6,6 +6,13 @@ type CreatePRRequestDTO struct {
 	PRID     string `json:"pull_request_id"`
 	Name     string `json:"pull_request_name"`
 	AuthorID string `json:"author_id"`
+	Draft    bool   `json:"draft"`
+	// ChangedFiles optionally lists the touched paths so CODEOWNERS rules can pick owners.
+	ChangedFiles []string `json:"changed_files,omitempty"`
+	// Labels give priority to reviewers tagged with the same areas.
+	Labels []string `json:"labels,omitempty"`
+	// TeamName picks reviewers from this team instead of the author's primary team.
+	TeamName string `json:"team_name,omitempty"`
 }
 
 type PRResponseWrapper struct {
@@ -13,22 +20,74 @@ type PRResponseWrapper struct {
 }
 
 type PRInfoDTO struct {
-	PRID      string     `json:"pull_request_id"`
-	Name      string     `json:"pull_request_name"`
-	AuthorID  string     `json:"author_id"`
-	Status    string     `json:"status"`
-	Reviewers []string   `json:"assigned_reviewers"`
-	CreatedAt time.Time  `json:"createdAt"`
-	MergedAt  *time.Time `json:"mergedAt"`
+	PRID         string      `json:"pull_request_id"`
+	Name         string      `json:"pull_request_name"`
+	AuthorID     string      `json:"author_id"`
+	TeamName     string      `json:"team_name,omitempty"`
+	Status       string      `json:"status"`
+	Reviewers    []string    `json:"assigned_reviewers"`
+	Reviews      []ReviewDTO `json:"reviews"`
+	ChangedFiles []string    `json:"changed_files,omitempty"`
+	Labels       []string    `json:"labels,omitempty"`
+	// FallbackLevel is set when the request assigned reviewers: team, partner_team, sibling_teams, parent_team,
+	// team_lead, global_pool or none.
+	FallbackLevel string     `json:"fallback_level,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	MergedAt      *time.Time `json:"mergedAt"`
+	ClosedAt      *time.Time `json:"closedAt"`
+}
+
+type ListResponseDTO struct {
+	PullRequests []PRInfoDTO `json:"pull_requests"`
+	NextCursor   string      `json:"next_cursor,omitempty"`
+}
+
+type HistoryResponseDTO struct {
+	PRID   string     `json:"pull_request_id"`
+	Events []EventDTO `json:"events"`
+}
+
+type EventDTO struct {
+	ID             uint64    `json:"id"`
+	Type           string    `json:"type"`
+	UserID         string    `json:"user_id,omitempty"`
+	PreviousUserID string    `json:"previous_user_id,omitempty"`
+	FromStatus     string    `json:"from_status,omitempty"`
+	ToStatus       string    `json:"to_status,omitempty"`
+	Details        string    `json:"details,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type ReviewDTO struct {
+	UserID    string     `json:"user_id"`
+	Verdict   string     `json:"verdict"`
+	VerdictAt *time.Time `json:"verdictAt"`
 }
 
 type MergePRRequestDTO struct {
 	PRID string `json:"pull_request_id"`
 }
 
+type ChangeStatusRequestDTO struct {
+	PRID string `json:"pull_request_id"`
+}
+
+type SubmitReviewRequestDTO struct {
+	PRID    string `json:"pull_request_id"`
+	UserID  string `json:"user_id"`
+	Verdict string `json:"verdict"`
+}
+
 type ReassignPRRequestDTO struct {
 	PRID      string `json:"pull_request_id"`
 	OldUserID string `json:"old_user_id"`
+	// NewUserID picks the replacement explicitly; when empty the fallback chain chooses one.
+	NewUserID string `json:"new_user_id,omitempty"`
+}
+
+type ReviewerRequestDTO struct {
+	PRID   string `json:"pull_request_id"`
+	UserID string `json:"user_id"`
 }
 
 type ReassignResponseWrapper struct {
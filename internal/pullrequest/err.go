@@ -3,10 +3,22 @@ package pullrequest
 import "errors"
 
 var (
-	ErrPRExists       = errors.New("PR id already exists")
-	ErrAuthorNotFound = errors.New("author not found")
-	ErrPRNotFound     = errors.New("PR not found")
-	ErrPRMerged       = errors.New("cannot reassign on merged PR")
-	ErrNotAssigned    = errors.New("reviewer is not assigned to this PR")
-	ErrNoCandidate    = errors.New("no active replacement candidate in team")
+	ErrPRExists          = errors.New("PR id already exists")
+	ErrAuthorNotFound    = errors.New("author not found")
+	ErrTeamNotFound      = errors.New("team not found")
+	ErrPRNotFound        = errors.New("PR not found")
+	ErrPRMerged          = errors.New("cannot reassign on merged PR")
+	ErrNotAssigned       = errors.New("reviewer is not assigned to this PR")
+	ErrNoCandidate       = errors.New("no active replacement candidate in team")
+	ErrPRNotOpen         = errors.New("PR is not open")
+	ErrInvalidTransition = errors.New("invalid status transition")
+	ErrInvalidVerdict    = errors.New("verdict must be APPROVED or CHANGES_REQUESTED")
+	ErrNotApproved       = errors.New("PR does not have enough approvals")
+	ErrBadCursor         = errors.New("invalid cursor")
+	ErrBadFilter         = errors.New("invalid list filter")
+	ErrReviewerNotFound  = errors.New("reviewer not found")
+	ErrAuthorReviewer    = errors.New("author cannot review own PR")
+	ErrReviewerInactive  = errors.New("reviewer is not active")
+	ErrAlreadyAssigned   = errors.New("reviewer is already assigned to this PR")
+	ErrReviewerLimit     = errors.New("PR already has the maximum number of reviewers")
 )
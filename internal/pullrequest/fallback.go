@@ -0,0 +1,200 @@
+package pullrequest
+
+import (
+	"context"
+	"slices"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+// Levels of the fallback chain, reported as fallback_level when reviewers are assigned.
+const (
+	LevelTeam        = "team"
+	LevelPartnerTeam = "partner_team"
+	LevelSiblingTeam = "sibling_teams"
+	LevelParentTeam  = "parent_team"
+	LevelTeamLead    = "team_lead"
+	LevelGlobalPool  = "global_pool"
+	LevelNone        = "none"
+)
+
+// globalPoolKey is the selector key of the global pool step. The pool is shared by every team,
+// so it rotates on a cursor of its own under the default strategy.
+const globalPoolKey = "*global_pool"
+
+type fallbackStep struct {
+	level string
+	// teamName picks the strategy and the round-robin cursor of the step. It is empty when
+	// the candidates are taken as they are, e.g. the team lead.
+	teamName   string
+	candidates func(ctx context.Context, excludeIDs []string) ([]model.User, error)
+}
+
+// fallbackChain lists where reviewers come from, in order: the team itself, its partner team,
+// the other teams under the same parent, the parent team, the lead and finally the global pool.
+// Unset levels are skipped.
+func (s *Service) fallbackChain(team *model.Team) []fallbackStep {
+	chain := []fallbackStep{{
+		level:    LevelTeam,
+		teamName: team.Name,
+		candidates: func(ctx context.Context, excludeIDs []string) ([]model.User, error) {
+			return s.userProvider.GetReviewCandidates(ctx, team.Name, excludeIDs)
+		},
+	}}
+	if team.PartnerTeam != "" {
+		chain = append(chain, fallbackStep{
+			level:    LevelPartnerTeam,
+			teamName: team.PartnerTeam,
+			candidates: func(ctx context.Context, excludeIDs []string) ([]model.User, error) {
+				return s.userProvider.GetReviewCandidates(ctx, team.PartnerTeam, excludeIDs)
+			},
+		})
+	}
+	if team.ParentTeam != "" {
+		chain = append(chain,
+			fallbackStep{
+				level:    LevelSiblingTeam,
+				teamName: team.ParentTeam,
+				candidates: func(ctx context.Context, excludeIDs []string) ([]model.User, error) {
+					return s.siblingCandidates(ctx, team, excludeIDs)
+				},
+			},
+			fallbackStep{
+				level:    LevelParentTeam,
+				teamName: team.ParentTeam,
+				candidates: func(ctx context.Context, excludeIDs []string) ([]model.User, error) {
+					return s.userProvider.GetReviewCandidates(ctx, team.ParentTeam, excludeIDs)
+				},
+			},
+		)
+	}
+	if team.LeadUserID != "" {
+		chain = append(chain, fallbackStep{
+			level: LevelTeamLead,
+			candidates: func(ctx context.Context, excludeIDs []string) ([]model.User, error) {
+				return s.userProvider.GetActiveUsers(ctx, []string{team.LeadUserID}, excludeIDs)
+			},
+		})
+	}
+	if len(s.globalPool) > 0 {
+		chain = append(chain, fallbackStep{
+			level:    LevelGlobalPool,
+			teamName: globalPoolKey,
+			candidates: func(ctx context.Context, excludeIDs []string) ([]model.User, error) {
+				return s.userProvider.GetActiveUsers(ctx, s.globalPool, excludeIDs)
+			},
+		})
+	}
+	return chain
+}
+
+// siblingCandidates pools the candidates of every other team under the team's parent, so the
+// selector balances the load across sibling teams rather than filling the first one.
+func (s *Service) siblingCandidates(ctx context.Context, team *model.Team, excludeIDs []string) ([]model.User, error) {
+	siblings, err := s.teamProvider.GetSubTeams(ctx, team.ParentTeam)
+	if err != nil {
+		return nil, err
+	}
+	var pooled []model.User
+	seen := make(map[string]bool)
+	for _, sibling := range siblings {
+		if sibling.Name == team.Name || sibling.Name == team.PartnerTeam {
+			continue
+		}
+		candidates, err := s.userProvider.GetReviewCandidates(ctx, sibling.Name, excludeIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range candidates {
+			if !seen[c.ID] {
+				seen[c.ID] = true
+				pooled = append(pooled, c)
+			}
+		}
+	}
+	return pooled, nil
+}
+
+// selectWithFallback walks the chain until count reviewers are picked and returns
+// the deepest level that contributed, or LevelNone if nobody was found.
+func (s *Service) selectWithFallback(
+	ctx context.Context,
+	team *model.Team,
+	excludeIDs []string,
+	labels []string,
+	count int,
+) ([]model.User, string, error) {
+	log := s.log.With("op", "selectWithFallback", "team", team.Name)
+
+	var selected []model.User
+	level := LevelNone
+	exclude := slices.Clone(excludeIDs)
+	for _, step := range s.fallbackChain(team) {
+		if len(selected) >= count {
+			break
+		}
+		candidates, err := step.candidates(ctx, exclude)
+		if err != nil {
+			log.ErrorContext(ctx, "failed to fetch review candidates", "level", step.level, "error", err)
+			return nil, "", err
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+		picked := candidates[:min(len(candidates), count-len(selected))]
+		if step.teamName != "" {
+			picked, err = s.selectPreferred(ctx, step.teamName, candidates, labels, count-len(selected))
+			if err != nil {
+				log.ErrorContext(ctx, "failed to select reviewers", "level", step.level, "error", err)
+				return nil, "", err
+			}
+		}
+		if len(picked) == 0 {
+			continue
+		}
+		for _, u := range picked {
+			exclude = append(exclude, u.ID)
+		}
+		selected = append(selected, picked...)
+		level = step.level
+	}
+	if level != LevelTeam {
+		log.InfoContext(ctx, "fallback chain used", "level", level, "selected_count", len(selected))
+	}
+	return selected, level, nil
+}
+
+// selectPreferred picks reviewers tier by tier: people whose tags match the PR labels come first,
+// and within each of those groups people currently within working hours come before the rest.
+func (s *Service) selectPreferred(
+	ctx context.Context,
+	teamName string,
+	candidates []model.User,
+	labels []string,
+	count int,
+) ([]model.User, error) {
+	var tiers [4][]model.User
+	for _, c := range candidates {
+		tier := 0
+		if !c.MatchesAny(labels) {
+			tier += 2
+		}
+		if !c.OnDuty {
+			tier++
+		}
+		tiers[tier] = append(tiers[tier], c)
+	}
+
+	var picked []model.User
+	for _, group := range tiers {
+		if len(group) == 0 || len(picked) >= count {
+			continue
+		}
+		chosen, err := s.selector.Select(ctx, teamName, group, count-len(picked))
+		if err != nil {
+			return nil, err
+		}
+		picked = append(picked, chosen...)
+	}
+	return picked, nil
+}
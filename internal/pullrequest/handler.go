@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
 	"github.com/SeeXWH/pr-reviewer-service/pkg/req"
 	"github.com/SeeXWH/pr-reviewer-service/pkg/res"
 )
@@ -22,8 +23,18 @@ func NewHandler(router *http.ServeMux, prService PRProvider, conf *configs.Confi
 	}
 
 	router.HandleFunc("POST /pullRequest/create", handler.Create())
+	router.HandleFunc("GET /pullRequest/get", handler.Get())
+	router.HandleFunc("GET /pullRequest/list", handler.List())
+	router.HandleFunc("GET /pullRequest/history", handler.History())
 	router.HandleFunc("POST /pullRequest/merge", handler.Merge())
 	router.HandleFunc("POST /pullRequest/reassign", handler.Reassign())
+	router.HandleFunc("POST /pullRequest/addReviewer", handler.ChangeReviewer(prService.AddReviewer))
+	router.HandleFunc("POST /pullRequest/removeReviewer", handler.ChangeReviewer(prService.RemoveReviewer))
+	router.HandleFunc("POST /pullRequest/reshuffle", handler.Reshuffle())
+	router.HandleFunc("POST /pullRequest/submitReview", handler.SubmitReview())
+	router.HandleFunc("POST /pullRequest/close", handler.ChangeStatus(prService.Close))
+	router.HandleFunc("POST /pullRequest/reopen", handler.ChangeStatus(prService.Reopen))
+	router.HandleFunc("POST /pullRequest/markReady", handler.ChangeStatus(prService.MarkReady))
 }
 
 func (h *Handler) Create() http.HandlerFunc {
@@ -46,7 +57,7 @@ func (h *Handler) Create() http.HandlerFunc {
 		createdPR, err := h.prService.Create(ctx, prModel)
 		if err != nil {
 			switch {
-			case errors.Is(err, ErrAuthorNotFound):
+			case errors.Is(err, ErrAuthorNotFound), errors.Is(err, ErrTeamNotFound):
 				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
 				return
 			case errors.Is(err, ErrPRExists):
@@ -62,6 +73,87 @@ func (h *Handler) Create() http.HandlerFunc {
 	}
 }
 
+func (h *Handler) Get() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+
+		prID := r.URL.Query().Get("pull_request_id")
+		if prID == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id is required")
+			return
+		}
+
+		pr, err := h.prService.Get(ctx, prID)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrPRNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		resp := ToResponse(pr)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) History() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+
+		prID := r.URL.Query().Get("pull_request_id")
+		if prID == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id is required")
+			return
+		}
+
+		events, err := h.prService.History(ctx, prID)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrPRNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		resp := ToHistoryResponse(prID, events)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+
+		filter, err := ToListFilter(r.URL.Query())
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+			return
+		}
+
+		page, err := h.prService.List(ctx, filter)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrBadCursor):
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		resp := ToListResponse(page)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
 func (h *Handler) Merge() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
@@ -83,6 +175,12 @@ func (h *Handler) Merge() http.HandlerFunc {
 			case errors.Is(err, ErrPRNotFound):
 				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
 				return
+			case errors.Is(err, ErrInvalidTransition):
+				res.Error(w, http.StatusConflict, "INVALID_TRANSITION", err.Error())
+				return
+			case errors.Is(err, ErrNotApproved):
+				res.Error(w, http.StatusConflict, "NOT_APPROVED", err.Error())
+				return
 			default:
 				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
 				return
@@ -93,6 +191,84 @@ func (h *Handler) Merge() http.HandlerFunc {
 	}
 }
 
+func (h *Handler) ChangeStatus(apply func(context.Context, string) (*model.PullRequest, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		reqBody, err := req.HandleBody[ChangeStatusRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+
+		if reqBody.PRID == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id is required")
+			return
+		}
+
+		updatedPR, err := apply(ctx, reqBody.PRID)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrPRNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			case errors.Is(err, ErrInvalidTransition):
+				res.Error(w, http.StatusConflict, "INVALID_TRANSITION", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		resp := ToResponse(updatedPR)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) SubmitReview() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+
+		reqBody, err := req.HandleBody[SubmitReviewRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+
+		if reqBody.PRID == "" || reqBody.UserID == "" || reqBody.Verdict == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "fields required")
+			return
+		}
+
+		updatedPR, err := h.prService.SubmitReview(ctx, reqBody.PRID, reqBody.UserID, reqBody.Verdict)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrInvalidVerdict):
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+				return
+			case errors.Is(err, ErrPRNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			case errors.Is(err, ErrPRMerged):
+				res.Error(w, http.StatusConflict, "PR_MERGED", err.Error())
+				return
+			case errors.Is(err, ErrPRNotOpen):
+				res.Error(w, http.StatusConflict, "PR_NOT_OPEN", err.Error())
+				return
+			case errors.Is(err, ErrNotAssigned):
+				res.Error(w, http.StatusConflict, "NOT_ASSIGNED", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		resp := ToResponse(updatedPR)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
 func (h *Handler) Reassign() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
@@ -109,21 +285,33 @@ func (h *Handler) Reassign() http.HandlerFunc {
 			return
 		}
 
-		updatedPR, newReviewer, err := h.prService.ReassignReviewer(ctx, reqBody.PRID, reqBody.OldUserID)
+		updatedPR, newReviewer, err := h.prService.ReassignReviewer(ctx, reqBody.PRID, reqBody.OldUserID, reqBody.NewUserID)
 		if err != nil {
 			switch {
-			case errors.Is(err, ErrPRNotFound):
+			case errors.Is(err, ErrPRNotFound), errors.Is(err, ErrReviewerNotFound):
 				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
 				return
 			case errors.Is(err, ErrPRMerged):
 				res.Error(w, http.StatusConflict, "PR_MERGED", err.Error())
 				return
+			case errors.Is(err, ErrPRNotOpen):
+				res.Error(w, http.StatusConflict, "PR_NOT_OPEN", err.Error())
+				return
 			case errors.Is(err, ErrNotAssigned):
 				res.Error(w, http.StatusConflict, "NOT_ASSIGNED", err.Error())
 				return
 			case errors.Is(err, ErrNoCandidate):
 				res.Error(w, http.StatusConflict, "NO_CANDIDATE", err.Error())
 				return
+			case errors.Is(err, ErrAuthorReviewer):
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+				return
+			case errors.Is(err, ErrReviewerInactive):
+				res.Error(w, http.StatusConflict, "REVIEWER_INACTIVE", err.Error())
+				return
+			case errors.Is(err, ErrAlreadyAssigned):
+				res.Error(w, http.StatusConflict, "ALREADY_ASSIGNED", err.Error())
+				return
 			default:
 				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
 				return
@@ -134,3 +322,94 @@ func (h *Handler) Reassign() http.HandlerFunc {
 		res.JSON(w, http.StatusOK, resp)
 	}
 }
+
+func (h *Handler) ChangeReviewer(apply func(context.Context, string, string) (*model.PullRequest, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+
+		reqBody, err := req.HandleBody[ReviewerRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+
+		if reqBody.PRID == "" || reqBody.UserID == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "fields required")
+			return
+		}
+
+		updatedPR, err := apply(ctx, reqBody.PRID, reqBody.UserID)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrPRNotFound), errors.Is(err, ErrReviewerNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			case errors.Is(err, ErrAuthorReviewer):
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+				return
+			case errors.Is(err, ErrPRMerged):
+				res.Error(w, http.StatusConflict, "PR_MERGED", err.Error())
+				return
+			case errors.Is(err, ErrPRNotOpen):
+				res.Error(w, http.StatusConflict, "PR_NOT_OPEN", err.Error())
+				return
+			case errors.Is(err, ErrNotAssigned):
+				res.Error(w, http.StatusConflict, "NOT_ASSIGNED", err.Error())
+				return
+			case errors.Is(err, ErrReviewerInactive):
+				res.Error(w, http.StatusConflict, "REVIEWER_INACTIVE", err.Error())
+				return
+			case errors.Is(err, ErrAlreadyAssigned):
+				res.Error(w, http.StatusConflict, "ALREADY_ASSIGNED", err.Error())
+				return
+			case errors.Is(err, ErrReviewerLimit):
+				res.Error(w, http.StatusConflict, "REVIEWER_LIMIT", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		resp := ToResponse(updatedPR)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) Reshuffle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+
+		reqBody, err := req.HandleBody[ChangeStatusRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+
+		if reqBody.PRID == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "pull_request_id is required")
+			return
+		}
+
+		updatedPR, err := h.prService.Reshuffle(ctx, reqBody.PRID)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrPRNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			case errors.Is(err, ErrPRMerged):
+				res.Error(w, http.StatusConflict, "PR_MERGED", err.Error())
+				return
+			case errors.Is(err, ErrPRNotOpen):
+				res.Error(w, http.StatusConflict, "PR_NOT_OPEN", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		resp := ToResponse(updatedPR)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
@@ -2,25 +2,58 @@ package pullrequest
 
 import (
 	"context"
+	"time"
 
 	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
 )
 
 type UserProvider interface {
 	GetByID(ctx context.Context, id string) (*model.User, error)
-	GetReviewCandidates(ctx context.Context, teamName string, excludeUserID string) ([]model.User, error)
-	GetReplacementCandidate(ctx context.Context, teamName string, excludeUserIDs []string) (*model.User, error)
+	GetReviewCandidates(ctx context.Context, teamName string, excludeUserIDs []string) ([]model.User, error)
+	GetActiveUsers(ctx context.Context, userIDs []string, excludeUserIDs []string) ([]model.User, error)
+}
+
+type TeamProvider interface {
+	GetSettings(ctx context.Context, teamName string) (*model.Team, error)
+	GetSubTeams(ctx context.Context, parent string) ([]model.Team, error)
+}
+
+type ReviewerSelector interface {
+	Select(ctx context.Context, teamName string, candidates []model.User, count int) ([]model.User, error)
+}
+
+type OwnerResolver interface {
+	ResolveOwners(ctx context.Context, files []string) (map[string][]model.User, error)
+}
+
+// NotificationQueue accepts notifications without waiting for them to be delivered.
+type NotificationQueue interface {
+	Notify(context.Context, notify.Notification)
 }
 
 type PRStorer interface {
-	Create(context.Context, *model.PullRequest) error
+	Create(context.Context, *model.PullRequest, []model.PREvent) error
 	GetByID(context.Context, string) (*model.PullRequest, error)
-	Update(context.Context, *model.PullRequest) error
-	UpdateReviewers(context.Context, *model.PullRequest) error
+	Locked(ctx context.Context, prID string, fn func(context.Context) error) error
+	Update(context.Context, *model.PullRequest, []model.PREvent) error
+	SetVerdict(ctx context.Context, prID, userID, verdict string, at time.Time, events []model.PREvent) error
+	GetEvents(ctx context.Context, prID string) ([]model.PREvent, error)
+	List(ctx context.Context, filter ListFilter, after *PageCursor, limit int) ([]model.PullRequest, error)
 }
 
 type PRProvider interface {
+	Get(context.Context, string) (*model.PullRequest, error)
+	History(context.Context, string) ([]model.PREvent, error)
+	List(context.Context, ListFilter) (*Page, error)
 	Create(context.Context, model.PullRequest) (*model.PullRequest, error)
 	Merge(context.Context, string) (*model.PullRequest, error)
-	ReassignReviewer(context.Context, string, string) (*model.PullRequest, *model.User, error)
+	Close(context.Context, string) (*model.PullRequest, error)
+	Reopen(context.Context, string) (*model.PullRequest, error)
+	MarkReady(context.Context, string) (*model.PullRequest, error)
+	ReassignReviewer(ctx context.Context, prID, oldUserID, newUserID string) (*model.PullRequest, *model.User, error)
+	AddReviewer(ctx context.Context, prID, userID string) (*model.PullRequest, error)
+	RemoveReviewer(ctx context.Context, prID, userID string) (*model.PullRequest, error)
+	Reshuffle(context.Context, string) (*model.PullRequest, error)
+	SubmitReview(ctx context.Context, prID, userID, verdict string) (*model.PullRequest, error)
 }
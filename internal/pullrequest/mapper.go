@@ -1,13 +1,27 @@
 package pullrequest
 
-import "github.com/SeeXWH/pr-reviewer-service/internal/model"
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
 
 func ToDomain(req CreatePRRequestDTO) model.PullRequest {
-	return model.PullRequest{
-		ID:       req.PRID,
-		Name:     req.Name,
-		AuthorID: req.AuthorID,
+	pr := model.PullRequest{
+		ID:           req.PRID,
+		Name:         req.Name,
+		AuthorID:     req.AuthorID,
+		TeamName:     req.TeamName,
+		ChangedFiles: req.ChangedFiles,
+		Labels:       req.Labels,
+	}
+	if req.Draft {
+		pr.Status = DraftStatus
 	}
+	return pr
 }
 
 func ToResponse(pr *model.PullRequest) PRResponseWrapper {
@@ -15,20 +29,38 @@ func ToResponse(pr *model.PullRequest) PRResponseWrapper {
 		return PRResponseWrapper{}
 	}
 
+	verdicts := make(map[string]model.PRReviewer, len(pr.Reviews))
+	for _, v := range pr.Reviews {
+		verdicts[v.UserID] = v
+	}
+
 	reviewerIDs := make([]string, 0, len(pr.Reviewers))
+	reviews := make([]ReviewDTO, 0, len(pr.Reviewers))
 	for _, r := range pr.Reviewers {
 		reviewerIDs = append(reviewerIDs, r.ID)
+		review := ReviewDTO{UserID: r.ID, Verdict: VerdictPending}
+		if v, ok := verdicts[r.ID]; ok && v.Verdict != "" {
+			review.Verdict = v.Verdict
+			review.VerdictAt = v.VerdictAt
+		}
+		reviews = append(reviews, review)
 	}
 
 	return PRResponseWrapper{
 		PR: PRInfoDTO{
-			PRID:      pr.ID,
-			Name:      pr.Name,
-			AuthorID:  pr.AuthorID,
-			Status:    pr.Status,
-			Reviewers: reviewerIDs,
-			CreatedAt: pr.CreatedAt,
-			MergedAt:  pr.MergedAt,
+			PRID:          pr.ID,
+			Name:          pr.Name,
+			AuthorID:      pr.AuthorID,
+			TeamName:      pr.TeamName,
+			Status:        pr.Status,
+			Reviewers:     reviewerIDs,
+			Reviews:       reviews,
+			ChangedFiles:  pr.ChangedFiles,
+			Labels:        pr.Labels,
+			FallbackLevel: pr.FallbackLevel,
+			CreatedAt:     pr.CreatedAt,
+			MergedAt:      pr.MergedAt,
+			ClosedAt:      pr.ClosedAt,
 		},
 	}
 }
@@ -40,3 +72,85 @@ func ToReassignResponse(pr *model.PullRequest, newUserID string) ReassignRespons
 		ReplacedBy: newUserID,
 	}
 }
+
+func ToListResponse(page *Page) ListResponseDTO {
+	items := make([]PRInfoDTO, 0, len(page.PullRequests))
+	for i := range page.PullRequests {
+		items = append(items, ToResponse(&page.PullRequests[i]).PR)
+	}
+	return ListResponseDTO{
+		PullRequests: items,
+		NextCursor:   page.NextCursor,
+	}
+}
+
+func ToHistoryResponse(prID string, events []model.PREvent) HistoryResponseDTO {
+	items := make([]EventDTO, 0, len(events))
+	for _, e := range events {
+		items = append(items, EventDTO{
+			ID:             e.ID,
+			Type:           e.Type,
+			UserID:         e.UserID,
+			PreviousUserID: e.PreviousUserID,
+			FromStatus:     e.FromStatus,
+			ToStatus:       e.ToStatus,
+			Details:        e.Details,
+			CreatedAt:      e.CreatedAt,
+		})
+	}
+	return HistoryResponseDTO{
+		PRID:   prID,
+		Events: items,
+	}
+}
+
+func ToListFilter(query url.Values) (ListFilter, error) {
+	filter := ListFilter{
+		Status:     query.Get("status"),
+		AuthorID:   query.Get("author_id"),
+		ReviewerID: query.Get("reviewer_id"),
+		TeamName:   query.Get("team_name"),
+		Cursor:     query.Get("cursor"),
+	}
+	switch filter.Status {
+	case "", DraftStatus, OpenStatus, ClosedStatus, MergeStatus:
+	default:
+		return ListFilter{}, fmt.Errorf("%w: unknown status %q", ErrBadFilter, filter.Status)
+	}
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return ListFilter{}, fmt.Errorf("%w: limit must be a positive integer", ErrBadFilter)
+		}
+		filter.Limit = limit
+	}
+
+	var err error
+	ranges := []struct {
+		param string
+		dst   **time.Time
+	}{
+		{"created_from", &filter.CreatedFrom},
+		{"created_to", &filter.CreatedTo},
+		{"merged_from", &filter.MergedFrom},
+		{"merged_to", &filter.MergedTo},
+	}
+	for _, r := range ranges {
+		if *r.dst, err = parseTime(query.Get(r.param)); err != nil {
+			return ListFilter{}, fmt.Errorf("%w: %s must be RFC3339", ErrBadFilter, r.param)
+		}
+	}
+	return filter, nil
+}
+
+func parseTime(raw string) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,30 @@
+package pullrequest
+
+import (
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+type ListFilter struct {
+	Status      string
+	AuthorID    string
+	ReviewerID  string
+	TeamName    string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MergedFrom  *time.Time
+	MergedTo    *time.Time
+	Cursor      string
+	Limit       int
+}
+
+type Page struct {
+	PullRequests []model.PullRequest
+	NextCursor   string
+}
@@ -2,9 +2,14 @@ package pullrequest
 
 import (
 	"context"
+	"time"
 
+	"github.com/SeeXWH/pr-reviewer-service/internal/event"
 	"github.com/SeeXWH/pr-reviewer-service/internal/model"
 	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository struct {
@@ -15,14 +20,38 @@ func NewRepository(db *db.PostgresDB) *Repository {
 	return &Repository{db: db}
 }
 
-func (r *Repository) Create(ctx context.Context, pr *model.PullRequest) error {
-	return r.db.PostgresDB.WithContext(ctx).Create(pr).Error
+func (r *Repository) Create(ctx context.Context, pr *model.PullRequest, events []model.PREvent) error {
+	return r.db.Conn(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(pr).Error; err != nil {
+			return err
+		}
+		return event.Append(tx, events...)
+	})
+}
+
+// Locked runs fn in a transaction that holds the PR row FOR UPDATE. Repository calls made with the
+// context passed to fn join the transaction, so a read-modify-write in fn cannot interleave with
+// another change to the same PR. A missing PR is left for fn to report.
+func (r *Repository) Locked(ctx context.Context, prID string, fn func(context.Context) error) error {
+	return r.db.Transaction(ctx, func(ctx context.Context) error {
+		var ids []string
+		err := r.db.Conn(ctx).
+			Model(&model.PullRequest{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("pull_request_id = ?", prID).
+			Pluck("pull_request_id", &ids).Error
+		if err != nil {
+			return err
+		}
+		return fn(ctx)
+	})
 }
 
 func (r *Repository) GetByID(ctx context.Context, id string) (*model.PullRequest, error) {
 	var pr model.PullRequest
-	err := r.db.PostgresDB.WithContext(ctx).
+	err := r.db.Conn(ctx).
 		Preload("Reviewers").
+		Preload("Reviews").
 		First(&pr, "pull_request_id = ?", id).Error
 	if err != nil {
 		return nil, err
@@ -30,10 +59,99 @@ func (r *Repository) GetByID(ctx context.Context, id string) (*model.PullRequest
 	return &pr, nil
 }
 
-func (r *Repository) Update(ctx context.Context, pr *model.PullRequest) error {
-	return r.db.PostgresDB.WithContext(ctx).Save(pr).Error
+// Update saves the PR columns and replaces its reviewer set.
+func (r *Repository) Update(ctx context.Context, pr *model.PullRequest, events []model.PREvent) error {
+	return r.db.Conn(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(pr).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(pr).Association("Reviewers").Replace(pr.Reviewers); err != nil {
+			return err
+		}
+		return event.Append(tx, events...)
+	})
+}
+
+func (r *Repository) SetVerdict(
+	ctx context.Context,
+	prID, userID, verdict string,
+	at time.Time,
+	events []model.PREvent,
+) error {
+	return r.db.Conn(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.PRReviewer{}).
+			Where("pull_request_id = ? AND user_id = ?", prID, userID).
+			Updates(map[string]any{"verdict": verdict, "verdict_at": at}).Error
+		if err != nil {
+			return err
+		}
+		return event.Append(tx, events...)
+	})
+}
+
+func (r *Repository) GetEvents(ctx context.Context, prID string) ([]model.PREvent, error) {
+	var events []model.PREvent
+	err := r.db.PostgresDB.WithContext(ctx).
+		Where("pull_request_id = ?", prID).
+		Order("id").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
 }
 
-func (r *Repository) UpdateReviewers(ctx context.Context, pr *model.PullRequest) error {
-	return r.db.PostgresDB.WithContext(ctx).Model(pr).Association("Reviewers").Replace(pr.Reviewers)
+func (r *Repository) List(
+	ctx context.Context,
+	filter ListFilter,
+	after *PageCursor,
+	limit int,
+) ([]model.PullRequest, error) {
+	conn := r.db.Conn(ctx)
+	query := conn.
+		Model(&model.PullRequest{}).
+		Preload("Reviewers").
+		Preload("Reviews")
+
+	if filter.Status != "" {
+		query = query.Where("pull_requests.status = ?", filter.Status)
+	}
+	if filter.AuthorID != "" {
+		query = query.Where("pull_requests.author_id = ?", filter.AuthorID)
+	}
+	if filter.ReviewerID != "" {
+		query = query.Where(
+			"pull_requests.pull_request_id IN (?)",
+			conn.Table("pr_reviewers").Select("pull_request_id").Where("user_id = ?", filter.ReviewerID),
+		)
+	}
+	if filter.TeamName != "" {
+		query = query.Where("pull_requests.team_name = ?", filter.TeamName)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("pull_requests.created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("pull_requests.created_at < ?", *filter.CreatedTo)
+	}
+	if filter.MergedFrom != nil {
+		query = query.Where("pull_requests.merged_at >= ?", *filter.MergedFrom)
+	}
+	if filter.MergedTo != nil {
+		query = query.Where("pull_requests.merged_at < ?", *filter.MergedTo)
+	}
+	if after != nil {
+		query = query.Where("(pull_requests.created_at, pull_requests.pull_request_id) < (?, ?)", after.CreatedAt, after.ID)
+	}
+
+	var prs []model.PullRequest
+	err := query.
+		Order("pull_requests.created_at DESC").
+		Order("pull_requests.pull_request_id DESC").
+		Limit(limit).
+		Find(&prs).Error
+	if err != nil {
+		return nil, err
+	}
+	return prs, nil
 }
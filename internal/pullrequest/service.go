@@ -3,30 +3,55 @@ package pullrequest
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/event"
 	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
 
 	"gorm.io/gorm"
 )
 
-const (
-	MergeStatus = "MERGED"
-	OpenStatus  = "OPEN"
-)
-
 type Service struct {
 	repo         PRStorer
 	userProvider UserProvider
+	teamProvider TeamProvider
+	selector     ReviewerSelector
+	owners       OwnerResolver
+	notifier     NotificationQueue
+	globalPool   []string
+	maxReviewers int
 	log          *slog.Logger
+	// defaultApprovals applies to teams that never set required_approvals.
+	defaultApprovals int
 }
 
-func NewService(userProvider UserProvider, repo PRStorer, log *slog.Logger) *Service {
+func NewService(
+	userProvider UserProvider,
+	teamProvider TeamProvider,
+	selector ReviewerSelector,
+	owners OwnerResolver,
+	repo PRStorer,
+	notifier NotificationQueue,
+	conf configs.Reviewers,
+	log *slog.Logger,
+) *Service {
 	return &Service{
 		repo:         repo,
 		userProvider: userProvider,
+		teamProvider: teamProvider,
+		selector:     selector,
+		owners:       owners,
+		notifier:     notifier,
+		globalPool:   conf.GlobalPool,
+		maxReviewers: conf.MaxPerPR,
 		log:          log.With("component", "prService"),
+		// Teams that set required_approvals, 0 included, override this.
+		defaultApprovals: conf.DefaultApprovals,
 	}
 }
 
@@ -42,22 +67,31 @@ func (s *Service) Create(ctx context.Context, pr model.PullRequest) (*model.Pull
 		log.ErrorContext(ctx, "failed to fetch author", "error", err)
 		return nil, err
 	}
-
-	candidates, err := s.userProvider.GetReviewCandidates(ctx, author.TeamName, author.ID)
-	if err != nil {
-		log.ErrorContext(ctx, "failed to fetch review candidates", "error", err)
+	if pr.TeamName == "" {
+		pr.TeamName = author.TeamName
+	} else if _, err = s.teamProvider.GetSettings(ctx, pr.TeamName); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.WarnContext(ctx, "failed to create pr: team not found", "team_name", pr.TeamName)
+			return nil, ErrTeamNotFound
+		}
+		log.ErrorContext(ctx, "failed to fetch team", "error", err)
 		return nil, err
 	}
 
-	pr.Status = OpenStatus
-	pr.CreatedAt = time.Now()
-
-	pr.Reviewers = make([]*model.User, len(candidates))
-	for i := range candidates {
-		pr.Reviewers[i] = &candidates[i]
+	if pr.Status == DraftStatus {
+		pr.Reviewers = nil
+	} else {
+		pr.Status = OpenStatus
+		pr.Reviewers, pr.FallbackLevel, err = s.pickReviewers(ctx, &pr)
+		if err != nil {
+			return nil, err
+		}
 	}
+	pr.CreatedAt = time.Now()
+	pr.Labels = model.NormalizeTags(pr.Labels)
 
-	err = s.repo.Create(ctx, &pr)
+	events := append([]model.PREvent{event.Created(pr.ID, pr.AuthorID, pr.Status)}, assignedEvents(pr.ID, pr.Reviewers)...)
+	err = s.repo.Create(ctx, &pr, events)
 	if err != nil {
 		if errors.Is(err, gorm.ErrDuplicatedKey) {
 			log.WarnContext(ctx, "pr already exists")
@@ -67,40 +101,113 @@ func (s *Service) Create(ctx context.Context, pr model.PullRequest) (*model.Pull
 		return nil, err
 	}
 
-	log.InfoContext(ctx, "pr created", "pr_id", pr.ID, "reviewers_count", len(pr.Reviewers))
+	s.sendNotification(ctx, &pr, notify.EventAssigned, reviewerIDs(pr.Reviewers), "")
+	log.InfoContext(ctx, "pr created", "pr_id", pr.ID, "status", pr.Status, "reviewers_count", len(pr.Reviewers))
 	return &pr, nil
 }
 
-func (s *Service) Merge(ctx context.Context, prID string) (*model.PullRequest, error) {
-	log := s.log.With("op", "Merge", "pr_id", prID)
-
+func (s *Service) Get(ctx context.Context, prID string) (*model.PullRequest, error) {
 	pr, err := s.repo.GetByID(ctx, prID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrPRNotFound
 		}
-		log.ErrorContext(ctx, "failed to fetch pr", "error", err)
+		s.log.ErrorContext(ctx, "failed to fetch pr", "op", "Get", "pr_id", prID, "error", err)
 		return nil, err
 	}
-	if pr.Status == MergeStatus {
-		return pr, nil
+	return pr, nil
+}
+
+func (s *Service) History(ctx context.Context, prID string) ([]model.PREvent, error) {
+	if _, err := s.Get(ctx, prID); err != nil {
+		return nil, err
 	}
-	pr.Status = MergeStatus
-	now := time.Now()
-	pr.MergedAt = &now
-	if err = s.repo.Update(ctx, pr); err != nil {
-		log.ErrorContext(ctx, "failed to update pr status", "error", err)
+	events, err := s.repo.GetEvents(ctx, prID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to fetch pr events", "op", "History", "pr_id", prID, "error", err)
 		return nil, err
 	}
+	return events, nil
+}
 
-	log.InfoContext(ctx, "pr merged")
-	return pr, nil
+func (s *Service) List(ctx context.Context, filter ListFilter) (*Page, error) {
+	var after *PageCursor
+	if filter.Cursor != "" {
+		cursor, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = cursor
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	limit = min(limit, MaxPageSize)
+
+	prs, err := s.repo.List(ctx, filter, after, limit+1)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to list prs", "op", "List", "error", err)
+		return nil, err
+	}
+
+	page := &Page{PullRequests: prs}
+	if len(prs) > limit {
+		page.PullRequests = prs[:limit]
+		last := page.PullRequests[limit-1]
+		page.NextCursor = encodeCursor(PageCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	return page, nil
+}
+
+func (s *Service) Merge(ctx context.Context, prID string) (*model.PullRequest, error) {
+	return s.changeStatus(ctx, "Merge", prID, MergeStatus, OpenStatus)
+}
+
+// MergeExternal records a merge that already happened on the code host. The approval quorum is not
+// enforced: when it was not met the merge is still recorded, together with an APPROVAL_BYPASSED event.
+func (s *Service) MergeExternal(ctx context.Context, prID string) (*model.PullRequest, error) {
+	return s.setStatus(ctx, "MergeExternal", prID, MergeStatus, true, OpenStatus, DraftStatus, ClosedStatus)
+}
+
+func (s *Service) Close(ctx context.Context, prID string) (*model.PullRequest, error) {
+	return s.changeStatus(ctx, "Close", prID, ClosedStatus, OpenStatus, DraftStatus)
+}
+
+func (s *Service) Reopen(ctx context.Context, prID string) (*model.PullRequest, error) {
+	return s.changeStatus(ctx, "Reopen", prID, OpenStatus, ClosedStatus)
 }
 
+func (s *Service) MarkReady(ctx context.Context, prID string) (*model.PullRequest, error) {
+	return s.changeStatus(ctx, "MarkReady", prID, OpenStatus, DraftStatus)
+}
+
+// ReassignReviewer hands oldUserID's review over to newUserID, or to a reviewer picked
+// through the fallback chain when newUserID is empty.
 func (s *Service) ReassignReviewer(
 	ctx context.Context,
 	prID string,
 	oldUserID string,
+	newUserID string,
+) (*model.PullRequest, *model.User, error) {
+	var pr *model.PullRequest
+	var newReviewer *model.User
+	err := s.repo.Locked(ctx, prID, func(ctx context.Context) error {
+		var err error
+		pr, newReviewer, err = s.reassignReviewer(ctx, prID, oldUserID, newUserID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return pr, newReviewer, nil
+}
+
+func (s *Service) reassignReviewer(
+	ctx context.Context,
+	prID string,
+	oldUserID string,
+	newUserID string,
 ) (*model.PullRequest, *model.User, error) {
 	log := s.log.With("op", "ReassignReviewer", "pr_id", prID, "old_user_id", oldUserID)
 
@@ -113,25 +220,523 @@ func (s *Service) ReassignReviewer(
 		log.WarnContext(ctx, "validation failed", "error", err)
 		return nil, nil, err
 	}
-	author, err := s.userProvider.GetByID(ctx, pr.AuthorID)
+	if newUserID != "" {
+		return s.handOver(ctx, pr, oldUserID, newUserID)
+	}
+	settings, err := s.prSettings(ctx, pr)
 	if err != nil {
-		log.ErrorContext(ctx, "failed to fetch author details", "error", err)
 		return nil, nil, err
 	}
-	newReviewer, err := s.findReplacement(ctx, author.TeamName, excludeIDs)
+	count := max(1, settings.RequiredReviewers-(len(pr.Reviewers)-1))
+	newReviewers, level, err := s.findReplacements(ctx, settings, excludeIDs, pr.Labels, count)
 	if err != nil {
 		return nil, nil, err
 	}
+	pr.FallbackLevel = level
+	newReviewer := &newReviewers[0]
 	pr.Reviewers = replaceReviewerInSlice(pr.Reviewers, oldUserID, newReviewer)
-	if err = s.repo.UpdateReviewers(ctx, pr); err != nil {
+	events := []model.PREvent{event.Reassigned(pr.ID, oldUserID, newReviewer.ID)}
+	for i := 1; i < len(newReviewers); i++ {
+		pr.Reviewers = append(pr.Reviewers, &newReviewers[i])
+		events = append(events, event.Assigned(pr.ID, newReviewers[i].ID))
+	}
+	if err = s.repo.Update(ctx, pr, events); err != nil {
+		log.ErrorContext(ctx, "failed to update reviewers list", "error", err)
+		return nil, nil, err
+	}
+
+	s.sendNotification(ctx, pr, notify.EventUnassigned, []string{oldUserID}, reasonReassigned)
+	addedIDs := make([]string, 0, len(newReviewers))
+	for _, r := range newReviewers {
+		addedIDs = append(addedIDs, r.ID)
+	}
+	s.sendNotification(ctx, pr, notify.EventAssigned, addedIDs, "")
+	log.InfoContext(ctx, "reviewer reassigned",
+		"new_user_id", newReviewer.ID,
+		"added_count", len(newReviewers)-1,
+		"fallback_level", level,
+	)
+	return pr, newReviewer, nil
+}
+
+func (s *Service) handOver(
+	ctx context.Context,
+	pr *model.PullRequest,
+	oldUserID string,
+	newUserID string,
+) (*model.PullRequest, *model.User, error) {
+	log := s.log.With("op", "ReassignReviewer", "pr_id", pr.ID, "old_user_id", oldUserID, "new_user_id", newUserID)
+
+	newReviewer, err := s.validateNewReviewer(ctx, pr, newUserID)
+	if err != nil {
+		log.WarnContext(ctx, "handover rejected", "error", err)
+		return nil, nil, err
+	}
+	pr.Reviewers = replaceReviewerInSlice(pr.Reviewers, oldUserID, newReviewer)
+	events := []model.PREvent{event.Reassigned(pr.ID, oldUserID, newReviewer.ID)}
+	if err = s.repo.Update(ctx, pr, events); err != nil {
 		log.ErrorContext(ctx, "failed to update reviewers list", "error", err)
 		return nil, nil, err
 	}
 
-	log.InfoContext(ctx, "reviewer reassigned", "new_user_id", newReviewer.ID)
+	s.sendNotification(ctx, pr, notify.EventUnassigned, []string{oldUserID}, reasonReassigned)
+	s.sendNotification(ctx, pr, notify.EventAssigned, []string{newReviewer.ID}, "")
+	log.InfoContext(ctx, "reviewer handed over")
 	return pr, newReviewer, nil
 }
 
+func (s *Service) AddReviewer(ctx context.Context, prID, userID string) (*model.PullRequest, error) {
+	return s.locked(ctx, prID, func(ctx context.Context) (*model.PullRequest, error) {
+		return s.addReviewer(ctx, prID, userID)
+	})
+}
+
+func (s *Service) addReviewer(ctx context.Context, prID, userID string) (*model.PullRequest, error) {
+	log := s.log.With("op", "AddReviewer", "pr_id", prID, "user_id", userID)
+
+	pr, err := s.getAndValidatePR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	reviewer, err := s.validateNewReviewer(ctx, pr, userID)
+	if err != nil {
+		log.WarnContext(ctx, "reviewer rejected", "error", err)
+		return nil, err
+	}
+	settings, err := s.prSettings(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+	if limit := max(s.maxReviewers, settings.RequiredReviewers); len(pr.Reviewers) >= limit {
+		log.WarnContext(ctx, "reviewer rejected: limit reached", "limit", limit)
+		return nil, fmt.Errorf("%w: %d", ErrReviewerLimit, limit)
+	}
+
+	pr.Reviewers = append(pr.Reviewers, reviewer)
+	if err = s.repo.Update(ctx, pr, []model.PREvent{event.Assigned(pr.ID, reviewer.ID)}); err != nil {
+		log.ErrorContext(ctx, "failed to update reviewers list", "error", err)
+		return nil, err
+	}
+
+	s.sendNotification(ctx, pr, notify.EventAssigned, []string{reviewer.ID}, "")
+	log.InfoContext(ctx, "reviewer added", "reviewers_count", len(pr.Reviewers))
+	return pr, nil
+}
+
+func (s *Service) RemoveReviewer(ctx context.Context, prID, userID string) (*model.PullRequest, error) {
+	return s.locked(ctx, prID, func(ctx context.Context) (*model.PullRequest, error) {
+		return s.removeReviewer(ctx, prID, userID)
+	})
+}
+
+func (s *Service) removeReviewer(ctx context.Context, prID, userID string) (*model.PullRequest, error) {
+	log := s.log.With("op", "RemoveReviewer", "pr_id", prID, "user_id", userID)
+
+	pr, err := s.getAndValidatePR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAssigned(pr, userID) {
+		log.WarnContext(ctx, "reviewer is not assigned")
+		return nil, ErrNotAssigned
+	}
+
+	pr.Reviewers = slices.DeleteFunc(pr.Reviewers, func(r *model.User) bool { return r.ID == userID })
+	pr.Reviews = slices.DeleteFunc(pr.Reviews, func(r model.PRReviewer) bool { return r.UserID == userID })
+	events := []model.PREvent{event.Unassigned(pr.ID, userID, reasonRemoved)}
+	if err = s.repo.Update(ctx, pr, events); err != nil {
+		log.ErrorContext(ctx, "failed to update reviewers list", "error", err)
+		return nil, err
+	}
+
+	s.sendNotification(ctx, pr, notify.EventUnassigned, []string{userID}, reasonRemoved)
+	log.InfoContext(ctx, "reviewer removed", "reviewers_count", len(pr.Reviewers))
+	return pr, nil
+}
+
+// Reshuffle replaces the reviewer set with people picked through the fallback chain.
+// Current reviewers are only kept when there are not enough other candidates.
+func (s *Service) Reshuffle(ctx context.Context, prID string) (*model.PullRequest, error) {
+	return s.locked(ctx, prID, func(ctx context.Context) (*model.PullRequest, error) {
+		return s.reshuffle(ctx, prID)
+	})
+}
+
+func (s *Service) reshuffle(ctx context.Context, prID string) (*model.PullRequest, error) {
+	log := s.log.With("op", "Reshuffle", "pr_id", prID)
+
+	pr, err := s.getAndValidatePR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.prSettings(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	excludeIDs := []string{pr.AuthorID}
+	for _, r := range pr.Reviewers {
+		excludeIDs = append(excludeIDs, r.ID)
+	}
+	picks, level, err := s.selectWithFallback(ctx, settings, excludeIDs, pr.Labels, settings.RequiredReviewers)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers := make([]*model.User, 0, settings.RequiredReviewers)
+	for i := range picks {
+		reviewers = append(reviewers, &picks[i])
+	}
+	var events []model.PREvent
+	var removedIDs []string
+	for _, old := range pr.Reviewers {
+		if len(reviewers) < settings.RequiredReviewers {
+			reviewers = append(reviewers, old)
+			continue
+		}
+		events = append(events, event.Unassigned(pr.ID, old.ID, reasonReshuffled))
+		removedIDs = append(removedIDs, old.ID)
+	}
+	events = append(events, assignedEvents(pr.ID, reviewers[:len(picks)])...)
+	if len(events) == 0 {
+		log.InfoContext(ctx, "reshuffle found no other candidates")
+		pr.FallbackLevel = level
+		return pr, nil
+	}
+
+	kept := reviewers[len(picks):]
+	pr.Reviews = slices.DeleteFunc(pr.Reviews, func(r model.PRReviewer) bool {
+		return !slices.ContainsFunc(kept, func(u *model.User) bool { return u.ID == r.UserID })
+	})
+	pr.Reviewers = reviewers
+	pr.FallbackLevel = level
+	if err = s.repo.Update(ctx, pr, events); err != nil {
+		log.ErrorContext(ctx, "failed to update reviewers list", "error", err)
+		return nil, err
+	}
+
+	s.sendNotification(ctx, pr, notify.EventUnassigned, removedIDs, reasonReshuffled)
+	s.sendNotification(ctx, pr, notify.EventAssigned, reviewerIDs(reviewers[:len(picks)]), "")
+	log.InfoContext(ctx, "reviewers reshuffled", "new_count", len(picks), "kept_count", len(kept), "fallback_level", level)
+	return pr, nil
+}
+
+// validateNewReviewer checks that userID may be assigned to pr by hand.
+func (s *Service) validateNewReviewer(ctx context.Context, pr *model.PullRequest, userID string) (*model.User, error) {
+	if userID == pr.AuthorID {
+		return nil, ErrAuthorReviewer
+	}
+	if isAssigned(pr, userID) {
+		return nil, ErrAlreadyAssigned
+	}
+	reviewer, err := s.userProvider.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewerNotFound
+		}
+		s.log.ErrorContext(ctx, "failed to fetch reviewer", "op", "validateNewReviewer", "user_id", userID, "error", err)
+		return nil, err
+	}
+	if !reviewer.IsActive {
+		return nil, ErrReviewerInactive
+	}
+	return reviewer, nil
+}
+
+func (s *Service) SubmitReview(ctx context.Context, prID, userID, verdict string) (*model.PullRequest, error) {
+	return s.locked(ctx, prID, func(ctx context.Context) (*model.PullRequest, error) {
+		return s.submitReview(ctx, prID, userID, verdict)
+	})
+}
+
+func (s *Service) submitReview(ctx context.Context, prID, userID, verdict string) (*model.PullRequest, error) {
+	log := s.log.With("op", "SubmitReview", "pr_id", prID, "user_id", userID)
+
+	if verdict != VerdictApproved && verdict != VerdictChangesRequested {
+		return nil, ErrInvalidVerdict
+	}
+	pr, err := s.getAndValidatePR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAssigned(pr, userID) {
+		log.WarnContext(ctx, "review rejected: user is not assigned")
+		return nil, ErrNotAssigned
+	}
+
+	now := time.Now()
+	events := []model.PREvent{event.ReviewSubmitted(prID, userID, verdict)}
+	if err = s.repo.SetVerdict(ctx, prID, userID, verdict, now, events); err != nil {
+		log.ErrorContext(ctx, "failed to save verdict", "error", err)
+		return nil, err
+	}
+	pr.Reviews = setVerdict(pr.Reviews, model.PRReviewer{
+		PullRequestID: prID,
+		UserID:        userID,
+		Verdict:       verdict,
+		VerdictAt:     &now,
+	})
+
+	log.InfoContext(ctx, "review submitted", "verdict", verdict)
+	return pr, nil
+}
+
+func (s *Service) changeStatus(
+	ctx context.Context,
+	op string,
+	prID string,
+	target string,
+	allowedFrom ...string,
+) (*model.PullRequest, error) {
+	return s.setStatus(ctx, op, prID, target, false, allowedFrom...)
+}
+
+// setStatus moves the PR to target. Merges need the approval quorum unless bypassApprovals is set,
+// in which case a missing quorum is only recorded as an event.
+func (s *Service) setStatus(
+	ctx context.Context,
+	op string,
+	prID string,
+	target string,
+	bypassApprovals bool,
+	allowedFrom ...string,
+) (*model.PullRequest, error) {
+	return s.locked(ctx, prID, func(ctx context.Context) (*model.PullRequest, error) {
+		return s.applyStatus(ctx, op, prID, target, bypassApprovals, allowedFrom...)
+	})
+}
+
+func (s *Service) applyStatus(
+	ctx context.Context,
+	op string,
+	prID string,
+	target string,
+	bypassApprovals bool,
+	allowedFrom ...string,
+) (*model.PullRequest, error) {
+	log := s.log.With("op", op, "pr_id", prID)
+
+	pr, err := s.repo.GetByID(ctx, prID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPRNotFound
+		}
+		log.ErrorContext(ctx, "failed to fetch pr", "error", err)
+		return nil, err
+	}
+	if pr.Status == target {
+		return pr, nil
+	}
+	from := pr.Status
+	if !slices.Contains(allowedFrom, from) {
+		log.WarnContext(ctx, "invalid status transition", "from", from, "to", target)
+		return nil, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, target)
+	}
+
+	var bypassed []model.PREvent
+	if target == MergeStatus {
+		err = s.checkApprovals(ctx, pr)
+		switch {
+		case err == nil:
+		case bypassApprovals && errors.Is(err, ErrNotApproved):
+			log.WarnContext(ctx, "merged without required approvals", "error", err)
+			bypassed = append(bypassed, event.ApprovalBypassed(pr.ID, err.Error()))
+		default:
+			log.WarnContext(ctx, "merge rejected", "error", err)
+			return nil, err
+		}
+	}
+
+	assign := target == OpenStatus && len(pr.Reviewers) == 0
+	if assign {
+		if err = s.resolveTeam(ctx, pr); err != nil {
+			return nil, err
+		}
+		if pr.Reviewers, pr.FallbackLevel, err = s.pickReviewers(ctx, pr); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	switch target {
+	case MergeStatus:
+		pr.MergedAt = &now
+	case ClosedStatus:
+		pr.ClosedAt = &now
+	case OpenStatus:
+		pr.ClosedAt = nil
+	}
+	pr.Status = target
+	events := append([]model.PREvent{event.StatusChanged(pr.ID, from, target)}, bypassed...)
+	if assign {
+		events = append(events, assignedEvents(pr.ID, pr.Reviewers)...)
+	}
+	if err = s.repo.Update(ctx, pr, events); err != nil {
+		log.ErrorContext(ctx, "failed to update pr status", "error", err)
+		return nil, err
+	}
+
+	if target == MergeStatus {
+		s.sendNotification(ctx, pr, notify.EventMerged, append(reviewerIDs(pr.Reviewers), pr.AuthorID), "")
+	}
+	if assign {
+		s.sendNotification(ctx, pr, notify.EventAssigned, reviewerIDs(pr.Reviewers), "")
+	}
+	log.InfoContext(ctx, "pr status changed", "from", from, "to", target, "reviewers_count", len(pr.Reviewers))
+	return pr, nil
+}
+
+// pickReviewers assigns one owner for every changed path covered by CODEOWNERS rules
+// and fills the remaining slots by walking the fallback chain of the PR's team.
+func (s *Service) pickReviewers(ctx context.Context, pr *model.PullRequest) ([]*model.User, string, error) {
+	settings, err := s.teamSettings(ctx, pr.TeamName)
+	if err != nil {
+		return nil, "", err
+	}
+	selected, err := s.pickOwners(ctx, pr)
+	if err != nil {
+		return nil, "", err
+	}
+
+	labels := pr.Labels
+	level := LevelTeam
+	if remaining := settings.RequiredReviewers - len(selected); remaining > 0 {
+		excludeIDs := []string{pr.AuthorID}
+		for _, u := range selected {
+			excludeIDs = append(excludeIDs, u.ID)
+		}
+		var picks []model.User
+		picks, level, err = s.selectWithFallback(ctx, settings, excludeIDs, labels, remaining)
+		if err != nil {
+			return nil, "", err
+		}
+		if level == LevelNone && len(selected) > 0 {
+			level = LevelTeam
+		}
+		selected = append(selected, picks...)
+	}
+
+	reviewers := make([]*model.User, len(selected))
+	for i := range selected {
+		reviewers[i] = &selected[i]
+	}
+	return reviewers, level, nil
+}
+
+func (s *Service) pickOwners(ctx context.Context, pr *model.PullRequest) ([]model.User, error) {
+	if len(pr.ChangedFiles) == 0 {
+		return nil, nil
+	}
+	log := s.log.With("op", "pickOwners", "author_id", pr.AuthorID)
+
+	owners, err := s.owners.ResolveOwners(ctx, pr.ChangedFiles)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to resolve path owners", "error", err)
+		return nil, err
+	}
+
+	var picked []model.User
+	isPicked := func(u model.User) bool {
+		return slices.ContainsFunc(picked, func(p model.User) bool { return p.ID == u.ID })
+	}
+	for _, file := range pr.ChangedFiles {
+		candidates := slices.DeleteFunc(slices.Clone(owners[file]), func(u model.User) bool {
+			return u.ID == pr.AuthorID
+		})
+		if len(candidates) == 0 || slices.ContainsFunc(candidates, isPicked) {
+			continue
+		}
+		owner, err := s.selectPreferred(ctx, pr.TeamName, candidates, pr.Labels, 1)
+		if err != nil {
+			log.ErrorContext(ctx, "failed to select path owner", "path", file, "error", err)
+			return nil, err
+		}
+		picked = append(picked, owner...)
+	}
+	return picked, nil
+}
+
+func (s *Service) teamSettings(ctx context.Context, teamName string) (*model.Team, error) {
+	team, err := s.teamProvider.GetSettings(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &model.Team{Name: teamName, RequiredReviewers: model.DefaultRequiredReviewers}, nil
+		}
+		s.log.ErrorContext(ctx, "failed to fetch team settings", "op", "teamSettings", "team", teamName, "error", err)
+		return nil, err
+	}
+	if team.RequiredReviewers <= 0 {
+		team.RequiredReviewers = model.DefaultRequiredReviewers
+	}
+	return team, nil
+}
+
+// resolveTeam fills in the team of PRs created before PRs stored one: the author's primary team.
+func (s *Service) resolveTeam(ctx context.Context, pr *model.PullRequest) error {
+	if pr.TeamName != "" {
+		return nil
+	}
+	author, err := s.userProvider.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to fetch author details", "op", "resolveTeam", "pr_id", pr.ID, "error", err)
+		return err
+	}
+	pr.TeamName = author.TeamName
+	return nil
+}
+
+// prSettings returns the settings of the team the PR picks reviewers from.
+func (s *Service) prSettings(ctx context.Context, pr *model.PullRequest) (*model.Team, error) {
+	if err := s.resolveTeam(ctx, pr); err != nil {
+		return nil, err
+	}
+	return s.teamSettings(ctx, pr.TeamName)
+}
+
+func (s *Service) checkApprovals(ctx context.Context, pr *model.PullRequest) error {
+	approvals := 0
+	for _, review := range pr.Reviews {
+		switch review.Verdict {
+		case VerdictChangesRequested:
+			return fmt.Errorf("%w: changes requested by %s", ErrNotApproved, review.UserID)
+		case VerdictApproved:
+			approvals++
+		}
+	}
+
+	settings, err := s.prSettings(ctx, pr)
+	if err != nil {
+		return err
+	}
+	required := s.defaultApprovals
+	if settings.RequiredApprovals != nil {
+		required = *settings.RequiredApprovals
+	}
+	if approvals < required {
+		return fmt.Errorf("%w: %d of %d", ErrNotApproved, approvals, required)
+	}
+	return nil
+}
+
+// locked runs change under the PR's row lock, so concurrent changes to one PR apply one after another
+// and each of them sees the reviewers, verdicts and status the previous one left.
+func (s *Service) locked(
+	ctx context.Context,
+	prID string,
+	change func(context.Context) (*model.PullRequest, error),
+) (*model.PullRequest, error) {
+	var pr *model.PullRequest
+	err := s.repo.Locked(ctx, prID, func(ctx context.Context) error {
+		var err error
+		pr, err = change(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
 func (s *Service) getAndValidatePR(ctx context.Context, prID string) (*model.PullRequest, error) {
 	pr, err := s.repo.GetByID(ctx, prID)
 	if err != nil {
@@ -144,6 +749,9 @@ func (s *Service) getAndValidatePR(ctx context.Context, prID string) (*model.Pul
 	if pr.Status == MergeStatus {
 		return nil, ErrPRMerged
 	}
+	if pr.Status != OpenStatus {
+		return nil, ErrPRNotOpen
+	}
 	return pr, nil
 }
 
@@ -162,17 +770,26 @@ func (s *Service) validateAssignmentAndGetExclusions(pr *model.PullRequest, oldU
 	return excludeIDs, nil
 }
 
-func (s *Service) findReplacement(ctx context.Context, teamName string, excludeIDs []string) (*model.User, error) {
-	newReviewer, err := s.userProvider.GetReplacementCandidate(ctx, teamName, excludeIDs)
+func (s *Service) findReplacements(
+	ctx context.Context,
+	team *model.Team,
+	excludeIDs []string,
+	labels []string,
+	count int,
+) ([]model.User, string, error) {
+	selected, level, err := s.selectWithFallback(ctx, team, excludeIDs, labels, count)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			s.log.WarnContext(ctx, "no replacement candidate available", "op", "findReplacement", "team", teamName)
-			return nil, ErrNoCandidate
-		}
-		s.log.ErrorContext(ctx, "failed to find replacement candidate", "op", "findReplacement", "error", err)
-		return nil, err
+		return nil, "", err
+	}
+	if len(selected) == 0 {
+		s.log.WarnContext(ctx, "no replacement candidate available", "op", "findReplacements", "team", team.Name)
+		return nil, "", ErrNoCandidate
 	}
-	return newReviewer, nil
+	return selected, level, nil
+}
+
+func isAssigned(pr *model.PullRequest, userID string) bool {
+	return slices.ContainsFunc(pr.Reviewers, func(r *model.User) bool { return r.ID == userID })
 }
 
 func replaceReviewerInSlice(currentReviewers []*model.User, oldUserID string, newReviewer *model.User) []*model.User {
@@ -186,3 +803,43 @@ func replaceReviewerInSlice(currentReviewers []*model.User, oldUserID string, ne
 	}
 	return updatedList
 }
+
+func setVerdict(reviews []model.PRReviewer, review model.PRReviewer) []model.PRReviewer {
+	for i := range reviews {
+		if reviews[i].UserID == review.UserID {
+			reviews[i] = review
+			return reviews
+		}
+	}
+	return append(reviews, review)
+}
+
+func reviewerIDs(reviewers []*model.User) []string {
+	ids := make([]string, 0, len(reviewers))
+	for _, r := range reviewers {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+// sendNotification queues a notification for userIDs; delivery happens in the background.
+func (s *Service) sendNotification(ctx context.Context, pr *model.PullRequest, kind string, userIDs []string, reason string) {
+	if len(userIDs) == 0 {
+		return
+	}
+	s.notifier.Notify(ctx, notify.Notification{
+		Event:           kind,
+		PullRequestID:   pr.ID,
+		PullRequestName: pr.Name,
+		RecipientIDs:    userIDs,
+		Reason:          reason,
+	})
+}
+
+func assignedEvents(prID string, reviewers []*model.User) []model.PREvent {
+	events := make([]model.PREvent, 0, len(reviewers))
+	for _, r := range reviewers {
+		events = append(events, event.Assigned(prID, r.ID))
+	}
+	return events
+}
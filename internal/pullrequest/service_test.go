@@ -2,12 +2,16 @@ package pullrequest
 
 import (
 	"context"
+	"errors"
 	"io"
 	"log/slog"
 	"testing"
 	"time"
 
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/event"
 	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -30,22 +34,71 @@ func (m *MockUserProvider) GetByID(ctx context.Context, id string) (*model.User,
 func (m *MockUserProvider) GetReviewCandidates(
 	ctx context.Context,
 	teamName string,
-	excludeUserID string,
+	excludeUserIDs []string,
 ) ([]model.User, error) {
-	args := m.Called(ctx, teamName, excludeUserID)
+	args := m.Called(ctx, teamName, excludeUserIDs)
 	if val, ok := args.Get(0).([]model.User); ok {
 		return val, args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-func (m *MockUserProvider) GetReplacementCandidate(
+func (m *MockUserProvider) GetActiveUsers(
 	ctx context.Context,
-	teamName string,
+	userIDs []string,
 	excludeUserIDs []string,
-) (*model.User, error) {
-	args := m.Called(ctx, teamName, excludeUserIDs)
-	if val, ok := args.Get(0).(*model.User); ok {
+) ([]model.User, error) {
+	args := m.Called(ctx, userIDs, excludeUserIDs)
+	if val, ok := args.Get(0).([]model.User); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockTeamProvider struct {
+	mock.Mock
+}
+
+func (m *MockTeamProvider) GetSettings(ctx context.Context, teamName string) (*model.Team, error) {
+	args := m.Called(ctx, teamName)
+	if val, ok := args.Get(0).(*model.Team); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTeamProvider) GetSubTeams(ctx context.Context, parent string) ([]model.Team, error) {
+	args := m.Called(ctx, parent)
+	if val, ok := args.Get(0).([]model.Team); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockSelector struct {
+	mock.Mock
+}
+
+func (m *MockSelector) Select(
+	ctx context.Context,
+	teamName string,
+	candidates []model.User,
+	count int,
+) ([]model.User, error) {
+	args := m.Called(ctx, teamName, candidates, count)
+	if val, ok := args.Get(0).([]model.User); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockOwnerResolver struct {
+	mock.Mock
+}
+
+func (m *MockOwnerResolver) ResolveOwners(ctx context.Context, files []string) (map[string][]model.User, error) {
+	args := m.Called(ctx, files)
+	if val, ok := args.Get(0).(map[string][]model.User); ok {
 		return val, args.Error(1)
 	}
 	return nil, args.Error(1)
@@ -53,10 +106,12 @@ func (m *MockUserProvider) GetReplacementCandidate(
 
 type MockPRStorer struct {
 	mock.Mock
+	// locked lists the PRs Locked was called for, in order.
+	locked []string
 }
 
-func (m *MockPRStorer) Create(ctx context.Context, pr *model.PullRequest) error {
-	args := m.Called(ctx, pr)
+func (m *MockPRStorer) Create(ctx context.Context, pr *model.PullRequest, events []model.PREvent) error {
+	args := m.Called(ctx, pr, events)
 	return args.Error(0)
 }
 
@@ -68,46 +123,424 @@ func (m *MockPRStorer) GetByID(ctx context.Context, id string) (*model.PullReque
 	return nil, args.Error(1)
 }
 
-func (m *MockPRStorer) Update(ctx context.Context, pr *model.PullRequest) error {
-	args := m.Called(ctx, pr)
+func (m *MockPRStorer) Locked(ctx context.Context, prID string, fn func(context.Context) error) error {
+	m.locked = append(m.locked, prID)
+	return fn(ctx)
+}
+
+func (m *MockPRStorer) Update(ctx context.Context, pr *model.PullRequest, events []model.PREvent) error {
+	args := m.Called(ctx, pr, events)
 	return args.Error(0)
 }
 
-func (m *MockPRStorer) UpdateReviewers(ctx context.Context, pr *model.PullRequest) error {
-	args := m.Called(ctx, pr)
+func (m *MockPRStorer) SetVerdict(
+	ctx context.Context,
+	prID, userID, verdict string,
+	at time.Time,
+	events []model.PREvent,
+) error {
+	args := m.Called(ctx, prID, userID, verdict, at, events)
 	return args.Error(0)
 }
 
+func (m *MockPRStorer) GetEvents(ctx context.Context, prID string) ([]model.PREvent, error) {
+	args := m.Called(ctx, prID)
+	if val, ok := args.Get(0).([]model.PREvent); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPRStorer) List(
+	ctx context.Context,
+	filter ListFilter,
+	after *PageCursor,
+	limit int,
+) ([]model.PullRequest, error) {
+	args := m.Called(ctx, filter, after, limit)
+	if val, ok := args.Get(0).([]model.PullRequest); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type recordingQueue struct {
+	sent []notify.Notification
+}
+
+func (q *recordingQueue) Notify(_ context.Context, n notify.Notification) {
+	q.sent = append(q.sent, n)
+}
+
+type serviceMocks struct {
+	user     *MockUserProvider
+	team     *MockTeamProvider
+	selector *MockSelector
+	owners   *MockOwnerResolver
+	repo     *MockPRStorer
+	notifier *recordingQueue
+}
+
 func setupService() (*Service, *MockUserProvider, *MockPRStorer) {
-	mockUser := new(MockUserProvider)
-	mockRepo := new(MockPRStorer)
+	svc, mocks := setupServiceMocks()
+	return svc, mocks.user, mocks.repo
+}
+
+func setupServiceMocks() (*Service, *serviceMocks) {
+	mocks := &serviceMocks{
+		user:     new(MockUserProvider),
+		team:     new(MockTeamProvider),
+		selector: new(MockSelector),
+		owners:   new(MockOwnerResolver),
+		repo:     new(MockPRStorer),
+		notifier: new(recordingQueue),
+	}
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
-	svc := NewService(mockUser, mockRepo, logger)
-	return svc, mockUser, mockRepo
+	svc := NewService(mocks.user, mocks.team, mocks.selector, mocks.owners, mocks.repo, mocks.notifier, configs.Reviewers{}, logger)
+	return svc, mocks
 }
 
 func TestService_Create(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("success", func(t *testing.T) {
-		svc, mockUser, mockRepo := setupService()
+		svc, m := setupServiceMocks()
 		inputPR := model.PullRequest{AuthorID: "u1", Name: "Feature"}
 		author := &model.User{ID: "u1", TeamName: "Alpha"}
-		candidates := []model.User{{ID: "r1"}, {ID: "r2"}}
+		candidates := []model.User{{ID: "r1"}, {ID: "r2"}, {ID: "r3"}}
+		selected := []model.User{{ID: "r3"}, {ID: "r1"}}
 
-		mockUser.On("GetByID", ctx, "u1").Return(author, nil)
-		mockUser.On("GetReviewCandidates", ctx, "Alpha", "u1").Return(candidates, nil)
-		mockRepo.On("Create", ctx, mock.MatchedBy(func(pr *model.PullRequest) bool {
+		m.user.On("GetByID", ctx, "u1").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Alpha").Return(&model.Team{Name: "Alpha", RequiredReviewers: 2}, nil)
+		m.user.On("GetReviewCandidates", ctx, "Alpha", []string{"u1"}).Return(candidates, nil)
+		m.selector.On("Select", ctx, "Alpha", candidates, 2).Return(selected, nil)
+		m.repo.On("Create", ctx, mock.MatchedBy(func(pr *model.PullRequest) bool {
 			return pr.Status == "OPEN" && len(pr.Reviewers) == 2 && pr.CreatedAt.After(time.Time{})
+		}), mock.MatchedBy(func(events []model.PREvent) bool {
+			return len(events) == 3 &&
+				events[0].Type == event.TypeCreated &&
+				events[1].Type == event.TypeAssigned &&
+				events[2].Type == event.TypeAssigned
 		})).Return(nil)
 
 		res, err := svc.Create(ctx, inputPR)
 
 		require.NoError(t, err)
 		assert.Equal(t, "OPEN", res.Status)
-		mockUser.AssertExpectations(t)
-		mockRepo.AssertExpectations(t)
+		assert.Equal(t, "r3", res.Reviewers[0].ID)
+		require.Len(t, m.notifier.sent, 1)
+		assert.Equal(t, notify.EventAssigned, m.notifier.sent[0].Event)
+		assert.Equal(t, "Feature", m.notifier.sent[0].PullRequestName)
+		assert.Equal(t, []string{"r3", "r1"}, m.notifier.sent[0].RecipientIDs)
+		m.user.AssertExpectations(t)
+		m.selector.AssertExpectations(t)
+		m.repo.AssertExpectations(t)
+	})
+
+	t.Run("picks from the requested team", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		inputPR := model.PullRequest{AuthorID: "u1", TeamName: "Platform"}
+		author := &model.User{ID: "u1", TeamName: "Alpha"}
+		candidates := []model.User{{ID: "p1"}}
+
+		m.user.On("GetByID", ctx, "u1").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Platform").Return(&model.Team{Name: "Platform", RequiredReviewers: 1}, nil)
+		m.user.On("GetReviewCandidates", ctx, "Platform", []string{"u1"}).Return(candidates, nil)
+		m.selector.On("Select", ctx, "Platform", candidates, 1).Return(candidates, nil)
+		m.repo.On("Create", ctx, mock.MatchedBy(func(pr *model.PullRequest) bool {
+			return pr.TeamName == "Platform"
+		}), mock.Anything).Return(nil)
+
+		res, err := svc.Create(ctx, inputPR)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Platform", res.TeamName)
+		assert.Equal(t, "p1", res.Reviewers[0].ID)
+		m.user.AssertNotCalled(t, "GetReviewCandidates", ctx, "Alpha", mock.Anything)
+	})
+
+	t.Run("requested team not found", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		inputPR := model.PullRequest{AuthorID: "u1", TeamName: "Ghost"}
+
+		m.user.On("GetByID", ctx, "u1").Return(&model.User{ID: "u1", TeamName: "Alpha"}, nil)
+		m.team.On("GetSettings", ctx, "Ghost").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.Create(ctx, inputPR)
+
+		require.ErrorIs(t, err, ErrTeamNotFound)
+		m.repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("uses team required reviewers", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		inputPR := model.PullRequest{AuthorID: "u1"}
+		author := &model.User{ID: "u1", TeamName: "Security"}
+		candidates := []model.User{{ID: "r1"}, {ID: "r2"}, {ID: "r3"}, {ID: "r4"}}
+
+		m.user.On("GetByID", ctx, "u1").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Security").Return(&model.Team{Name: "Security", RequiredReviewers: 3}, nil)
+		m.user.On("GetReviewCandidates", ctx, "Security", []string{"u1"}).Return(candidates, nil)
+		m.selector.On("Select", ctx, "Security", candidates, 3).Return(candidates[:3], nil)
+		m.repo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil)
+
+		res, err := svc.Create(ctx, inputPR)
+
+		require.NoError(t, err)
+		assert.Len(t, res.Reviewers, 3)
+	})
+
+	t.Run("team without settings falls back to default", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		inputPR := model.PullRequest{AuthorID: "u1"}
+		author := &model.User{ID: "u1", TeamName: "Ghost"}
+
+		m.user.On("GetByID", ctx, "u1").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Ghost").Return(nil, gorm.ErrRecordNotFound)
+		candidates := []model.User{{ID: "r1"}}
+		m.user.On("GetReviewCandidates", ctx, "Ghost", []string{"u1"}).Return(candidates, nil)
+		m.selector.On("Select", ctx, "Ghost", candidates, model.DefaultRequiredReviewers).Return(candidates, nil)
+		m.repo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil)
+
+		_, err := svc.Create(ctx, inputPR)
+
+		require.NoError(t, err)
+		m.selector.AssertExpectations(t)
+	})
+
+	t.Run("selector error", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		inputPR := model.PullRequest{AuthorID: "u1"}
+		author := &model.User{ID: "u1", TeamName: "Alpha"}
+		candidates := []model.User{{ID: "r1"}}
+		selectErr := errors.New("cursor unavailable")
+
+		m.user.On("GetByID", ctx, "u1").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Alpha").Return(&model.Team{Name: "Alpha", RequiredReviewers: 2}, nil)
+		m.user.On("GetReviewCandidates", ctx, "Alpha", []string{"u1"}).Return(candidates, nil)
+		m.selector.On("Select", ctx, "Alpha", candidates, 2).Return(nil, selectErr)
+
+		_, err := svc.Create(ctx, inputPR)
+
+		require.ErrorIs(t, err, selectErr)
+		m.repo.AssertNotCalled(t, "Create", ctx, mock.Anything, mock.Anything)
+	})
+
+	t.Run("draft skips reviewer assignment", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		inputPR := model.PullRequest{AuthorID: "u1", Status: DraftStatus}
+		author := &model.User{ID: "u1", TeamName: "Alpha"}
+
+		m.user.On("GetByID", ctx, "u1").Return(author, nil)
+		m.repo.On("Create", ctx, mock.MatchedBy(func(pr *model.PullRequest) bool {
+			return pr.Status == DraftStatus && len(pr.Reviewers) == 0
+		}), mock.Anything).Return(nil)
+
+		res, err := svc.Create(ctx, inputPR)
+
+		require.NoError(t, err)
+		assert.Equal(t, DraftStatus, res.Status)
+		m.selector.AssertNotCalled(t, "Select", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		m.repo.AssertExpectations(t)
+	})
+
+	t.Run("assigns path owners before team members", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		files := []string{"api/handler.go", "docs/README.md", "api/routes.go", "Makefile"}
+		inputPR := model.PullRequest{AuthorID: "u1", ChangedFiles: files}
+		author := &model.User{ID: "u1", TeamName: "Alpha"}
+		apiOwners := []model.User{{ID: "o1"}, {ID: "o2"}}
+		docsOwners := []model.User{{ID: "u1"}, {ID: "o3"}}
+		candidates := []model.User{{ID: "r1"}, {ID: "r2"}}
+
+		m.user.On("GetByID", ctx, "u1").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Alpha").Return(&model.Team{Name: "Alpha", RequiredReviewers: 3}, nil)
+		m.owners.On("ResolveOwners", ctx, files).Return(map[string][]model.User{
+			"api/handler.go": apiOwners,
+			"api/routes.go":  apiOwners,
+			"docs/README.md": docsOwners,
+		}, nil)
+		m.selector.On("Select", ctx, "Alpha", apiOwners, 1).Return(apiOwners[1:], nil).Once()
+		m.selector.On("Select", ctx, "Alpha", []model.User{{ID: "o3"}}, 1).Return([]model.User{{ID: "o3"}}, nil).Once()
+		m.user.On("GetReviewCandidates", ctx, "Alpha", []string{"u1", "o2", "o3"}).Return(candidates, nil)
+		m.selector.On("Select", ctx, "Alpha", candidates, 1).Return(candidates[:1], nil).Once()
+		m.repo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil)
+
+		res, err := svc.Create(ctx, inputPR)
+
+		require.NoError(t, err)
+		require.Len(t, res.Reviewers, 3)
+		assert.Equal(t, "o2", res.Reviewers[0].ID)
+		assert.Equal(t, "o3", res.Reviewers[1].ID)
+		assert.Equal(t, "r1", res.Reviewers[2].ID)
+		m.selector.AssertExpectations(t)
+	})
+
+	t.Run("owners may exceed required reviewers", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		files := []string{"a.go", "b.sql"}
+		inputPR := model.PullRequest{AuthorID: "u1", ChangedFiles: files}
+		author := &model.User{ID: "u1", TeamName: "Alpha"}
+
+		m.user.On("GetByID", ctx, "u1").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Alpha").Return(&model.Team{Name: "Alpha", RequiredReviewers: 1}, nil)
+		m.owners.On("ResolveOwners", ctx, files).Return(map[string][]model.User{
+			"a.go":  {{ID: "o1"}},
+			"b.sql": {{ID: "o2"}},
+		}, nil)
+		m.selector.On("Select", ctx, "Alpha", []model.User{{ID: "o1"}}, 1).Return([]model.User{{ID: "o1"}}, nil)
+		m.selector.On("Select", ctx, "Alpha", []model.User{{ID: "o2"}}, 1).Return([]model.User{{ID: "o2"}}, nil)
+		m.repo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil)
+
+		res, err := svc.Create(ctx, inputPR)
+
+		require.NoError(t, err)
+		assert.Len(t, res.Reviewers, 2)
+		m.user.AssertNotCalled(t, "GetReviewCandidates", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("owner resolution error", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		inputPR := model.PullRequest{AuthorID: "u1", ChangedFiles: []string{"a.go"}}
+		dbErr := errors.New("db down")
+
+		m.user.On("GetByID", ctx, "u1").Return(&model.User{ID: "u1", TeamName: "Alpha"}, nil)
+		m.team.On("GetSettings", ctx, "Alpha").Return(&model.Team{Name: "Alpha", RequiredReviewers: 2}, nil)
+		m.owners.On("ResolveOwners", ctx, []string{"a.go"}).Return(nil, dbErr)
+
+		_, err := svc.Create(ctx, inputPR)
+
+		require.ErrorIs(t, err, dbErr)
+		m.repo.AssertNotCalled(t, "Create", ctx, mock.Anything, mock.Anything)
+	})
+
+	t.Run("walks fallback chain when team is short", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		inputPR := model.PullRequest{AuthorID: "u1"}
+		author := &model.User{ID: "u1", TeamName: "Solo"}
+		settings := &model.Team{Name: "Solo", RequiredReviewers: 2, PartnerTeam: "Platform", LeadUserID: "lead"}
+		partners := []model.User{{ID: "p1"}}
+		lead := []model.User{{ID: "lead"}}
+
+		m.user.On("GetByID", ctx, "u1").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Solo").Return(settings, nil)
+		m.user.On("GetReviewCandidates", ctx, "Solo", []string{"u1"}).Return([]model.User{}, nil)
+		m.user.On("GetReviewCandidates", ctx, "Platform", []string{"u1"}).Return(partners, nil)
+		m.selector.On("Select", ctx, "Platform", partners, 2).Return(partners, nil)
+		m.user.On("GetActiveUsers", ctx, []string{"lead"}, []string{"u1", "p1"}).Return(lead, nil)
+		m.repo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil)
+
+		res, err := svc.Create(ctx, inputPR)
+
+		require.NoError(t, err)
+		require.Len(t, res.Reviewers, 2)
+		assert.Equal(t, "p1", res.Reviewers[0].ID)
+		assert.Equal(t, "lead", res.Reviewers[1].ID)
+		assert.Equal(t, LevelTeamLead, res.FallbackLevel)
+	})
+
+	t.Run("escalates to sibling and parent teams", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		inputPR := model.PullRequest{AuthorID: "u1"}
+		author := &model.User{ID: "u1", TeamName: "Cards"}
+		settings := &model.Team{Name: "Cards", RequiredReviewers: 3, ParentTeam: "Payments"}
+		siblings := []model.Team{{Name: "Cards"}, {Name: "Billing"}, {Name: "Fraud"}}
+		billing := []model.User{{ID: "b1"}, {ID: "shared"}}
+		fraud := []model.User{{ID: "shared"}, {ID: "f1"}}
+		pooled := []model.User{{ID: "b1"}, {ID: "shared"}, {ID: "f1"}}
+		picked := []model.User{{ID: "b1"}, {ID: "f1"}}
+		parent := []model.User{{ID: "head"}}
+
+		m.user.On("GetByID", ctx, "u1").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Cards").Return(settings, nil)
+		m.user.On("GetReviewCandidates", ctx, "Cards", []string{"u1"}).Return([]model.User{}, nil)
+		m.team.On("GetSubTeams", ctx, "Payments").Return(siblings, nil)
+		m.user.On("GetReviewCandidates", ctx, "Billing", []string{"u1"}).Return(billing, nil)
+		m.user.On("GetReviewCandidates", ctx, "Fraud", []string{"u1"}).Return(fraud, nil)
+		m.selector.On("Select", ctx, "Payments", pooled, 3).Return(picked, nil)
+		m.user.On("GetReviewCandidates", ctx, "Payments", []string{"u1", "b1", "f1"}).Return(parent, nil)
+		m.selector.On("Select", ctx, "Payments", parent, 1).Return(parent, nil)
+		m.repo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil)
+
+		res, err := svc.Create(ctx, inputPR)
+
+		require.NoError(t, err)
+		require.Len(t, res.Reviewers, 3)
+		assert.Equal(t, "b1", res.Reviewers[0].ID)
+		assert.Equal(t, "f1", res.Reviewers[1].ID)
+		assert.Equal(t, "head", res.Reviewers[2].ID)
+		assert.Equal(t, LevelParentTeam, res.FallbackLevel)
+	})
+
+	t.Run("reports none when chain is exhausted", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		inputPR := model.PullRequest{AuthorID: "u1"}
+		author := &model.User{ID: "u1", TeamName: "Solo"}
+
+		m.user.On("GetByID", ctx, "u1").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Solo").Return(&model.Team{Name: "Solo", RequiredReviewers: 2}, nil)
+		m.user.On("GetReviewCandidates", ctx, "Solo", []string{"u1"}).Return([]model.User{}, nil)
+		m.repo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil)
+
+		res, err := svc.Create(ctx, inputPR)
+
+		require.NoError(t, err)
+		assert.Empty(t, res.Reviewers)
+		assert.Equal(t, LevelNone, res.FallbackLevel)
+		m.selector.AssertNotCalled(t, "Select", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("prefers teammates within working hours", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		inputPR := model.PullRequest{AuthorID: "u1"}
+		author := &model.User{ID: "u1", TeamName: "Alpha"}
+		candidates := []model.User{{ID: "r1"}, {ID: "r2", OnDuty: true}, {ID: "r3"}}
+		onDuty := []model.User{{ID: "r2", OnDuty: true}}
+		offDuty := []model.User{{ID: "r1"}, {ID: "r3"}}
+
+		m.user.On("GetByID", ctx, "u1").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Alpha").Return(&model.Team{Name: "Alpha", RequiredReviewers: 2}, nil)
+		m.user.On("GetReviewCandidates", ctx, "Alpha", []string{"u1"}).Return(candidates, nil)
+		m.selector.On("Select", ctx, "Alpha", onDuty, 2).Return(onDuty, nil)
+		m.selector.On("Select", ctx, "Alpha", offDuty, 1).Return(offDuty[1:], nil)
+		m.repo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil)
+
+		res, err := svc.Create(ctx, inputPR)
+
+		require.NoError(t, err)
+		require.Len(t, res.Reviewers, 2)
+		assert.Equal(t, "r2", res.Reviewers[0].ID)
+		assert.Equal(t, "r3", res.Reviewers[1].ID)
+		assert.Equal(t, LevelTeam, res.FallbackLevel)
+		m.selector.AssertExpectations(t)
+	})
+
+	t.Run("prefers candidates whose tags match labels", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		inputPR := model.PullRequest{AuthorID: "u1", Labels: []string{"Database", "database"}}
+		author := &model.User{ID: "u1", TeamName: "Alpha"}
+		dba := model.User{ID: "r1", Tags: []string{"database"}}
+		infra := model.User{ID: "r2", Tags: []string{"infra"}, OnDuty: true}
+		frontend := model.User{ID: "r3", Tags: []string{"frontend"}}
+		candidates := []model.User{infra, dba, frontend}
+
+		m.user.On("GetByID", ctx, "u1").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Alpha").Return(&model.Team{Name: "Alpha", RequiredReviewers: 2}, nil)
+		m.user.On("GetReviewCandidates", ctx, "Alpha", []string{"u1"}).Return(candidates, nil)
+		m.selector.On("Select", ctx, "Alpha", []model.User{dba}, 2).Return([]model.User{dba}, nil)
+		m.selector.On("Select", ctx, "Alpha", []model.User{infra}, 1).Return([]model.User{infra}, nil)
+		m.repo.On("Create", ctx, mock.MatchedBy(func(pr *model.PullRequest) bool {
+			return assert.Equal(t, []string{"database"}, pr.Labels)
+		}), mock.Anything).Return(nil)
+
+		res, err := svc.Create(ctx, inputPR)
+
+		require.NoError(t, err)
+		require.Len(t, res.Reviewers, 2)
+		assert.Equal(t, "r1", res.Reviewers[0].ID)
+		assert.Equal(t, "r2", res.Reviewers[1].ID)
+		m.selector.AssertExpectations(t)
 	})
 
 	t.Run("author not found", func(t *testing.T) {
@@ -122,13 +555,15 @@ func TestService_Create(t *testing.T) {
 	})
 
 	t.Run("pr already exists", func(t *testing.T) {
-		svc, mockUser, mockRepo := setupService()
+		svc, m := setupServiceMocks()
 		inputPR := model.PullRequest{AuthorID: "u1"}
 		author := &model.User{ID: "u1", TeamName: "Alpha"}
 
-		mockUser.On("GetByID", ctx, "u1").Return(author, nil)
-		mockUser.On("GetReviewCandidates", ctx, "Alpha", "u1").Return([]model.User{}, nil)
-		mockRepo.On("Create", ctx, mock.Anything).Return(gorm.ErrDuplicatedKey)
+		m.user.On("GetByID", ctx, "u1").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Alpha").Return(&model.Team{Name: "Alpha", RequiredReviewers: 2}, nil)
+		m.user.On("GetReviewCandidates", ctx, "Alpha", []string{"u1"}).Return([]model.User{}, nil)
+		m.selector.On("Select", ctx, "Alpha", []model.User{}, 2).Return([]model.User{}, nil)
+		m.repo.On("Create", ctx, mock.Anything, mock.Anything).Return(gorm.ErrDuplicatedKey)
 
 		_, err := svc.Create(ctx, inputPR)
 
@@ -139,131 +574,893 @@ func TestService_Create(t *testing.T) {
 func TestService_Merge(t *testing.T) {
 	ctx := context.Background()
 
+	author := &model.User{ID: "u1", TeamName: "backend"}
+	zero, two := 0, 2
+
 	t.Run("success merge", func(t *testing.T) {
-		svc, _, mockRepo := setupService()
-		pr := &model.PullRequest{ID: "pr-1", Status: "OPEN"}
+		svc, mocks := setupServiceMocks()
+		pr := &model.PullRequest{ID: "pr-1", AuthorID: "u1", Status: "OPEN", Reviewers: []*model.User{{ID: "r1"}}}
 
-		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
-		mockRepo.On("Update", ctx, mock.MatchedBy(func(updated *model.PullRequest) bool {
+		mocks.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		mocks.user.On("GetByID", ctx, "u1").Return(author, nil)
+		mocks.team.On("GetSettings", ctx, "backend").Return(&model.Team{Name: "backend"}, nil)
+		mocks.repo.On("Update", ctx, mock.MatchedBy(func(updated *model.PullRequest) bool {
 			return updated.Status == MergeStatus && updated.MergedAt != nil
-		})).Return(nil)
+		}), mock.Anything).Return(nil)
 
 		res, err := svc.Merge(ctx, "pr-1")
 
 		require.NoError(t, err)
 		assert.Equal(t, MergeStatus, res.Status)
 		assert.NotNil(t, res.MergedAt)
+		require.Len(t, mocks.notifier.sent, 1)
+		assert.Equal(t, notify.EventMerged, mocks.notifier.sent[0].Event)
+		assert.Equal(t, []string{"r1", "u1"}, mocks.notifier.sent[0].RecipientIDs)
 	})
 
-	t.Run("already merged", func(t *testing.T) {
-		svc, _, mockRepo := setupService()
-		pr := &model.PullRequest{ID: "pr-1", Status: MergeStatus}
+	t.Run("approval quorum met", func(t *testing.T) {
+		svc, mocks := setupServiceMocks()
+		pr := &model.PullRequest{
+			ID:       "pr-1",
+			AuthorID: "u1",
+			Status:   OpenStatus,
+			Reviews: []model.PRReviewer{
+				{UserID: "u2", Verdict: VerdictApproved},
+				{UserID: "u3", Verdict: VerdictApproved},
+			},
+		}
 
-		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		mocks.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		mocks.user.On("GetByID", ctx, "u1").Return(author, nil)
+		mocks.team.On("GetSettings", ctx, "backend").Return(&model.Team{Name: "backend", RequiredApprovals: &two}, nil)
+		mocks.repo.On("Update", ctx, mock.Anything, mock.Anything).Return(nil)
 
 		res, err := svc.Merge(ctx, "pr-1")
 
 		require.NoError(t, err)
 		assert.Equal(t, MergeStatus, res.Status)
-		mockRepo.AssertNotCalled(t, "Update", ctx, mock.Anything)
+		assert.Equal(t, []string{"pr-1"}, mocks.repo.locked)
 	})
 
-	t.Run("pr not found", func(t *testing.T) {
-		svc, _, mockRepo := setupService()
+	t.Run("not enough approvals", func(t *testing.T) {
+		svc, mocks := setupServiceMocks()
+		pr := &model.PullRequest{
+			ID:       "pr-1",
+			AuthorID: "u1",
+			Status:   OpenStatus,
+			Reviews: []model.PRReviewer{
+				{UserID: "u2", Verdict: VerdictApproved},
+				{UserID: "u3", Verdict: VerdictPending},
+			},
+		}
 
-		mockRepo.On("GetByID", ctx, "missing").Return(nil, gorm.ErrRecordNotFound)
+		mocks.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		mocks.user.On("GetByID", ctx, "u1").Return(author, nil)
+		mocks.team.On("GetSettings", ctx, "backend").Return(&model.Team{Name: "backend", RequiredApprovals: &two}, nil)
 
-		_, err := svc.Merge(ctx, "missing")
+		_, err := svc.Merge(ctx, "pr-1")
 
-		assert.ErrorIs(t, err, ErrPRNotFound)
+		require.ErrorIs(t, err, ErrNotApproved)
+		mocks.repo.AssertNotCalled(t, "Update", ctx, mock.Anything, mock.Anything)
 	})
-}
 
-func TestService_ReassignReviewer(t *testing.T) {
-	ctx := context.Background()
+	t.Run("team without quorum uses the global default", func(t *testing.T) {
+		svc, mocks := setupServiceMocks()
+		svc.defaultApprovals = 1
+		pr := &model.PullRequest{
+			ID:       "pr-1",
+			AuthorID: "u1",
+			Status:   OpenStatus,
+			Reviews:  []model.PRReviewer{{UserID: "u2", Verdict: VerdictPending}},
+		}
 
-	t.Run("success reassign", func(t *testing.T) {
-		svc, mockUser, mockRepo := setupService()
+		mocks.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		mocks.user.On("GetByID", ctx, "u1").Return(author, nil)
+		mocks.team.On("GetSettings", ctx, "backend").Return(&model.Team{Name: "backend"}, nil)
 
-		oldRev := &model.User{ID: "old"}
-		stayRev := &model.User{ID: "stay"}
-		newRev := &model.User{ID: "new"}
-		author := &model.User{ID: "author", TeamName: "Devs"}
+		_, err := svc.Merge(ctx, "pr-1")
+
+		require.ErrorIs(t, err, ErrNotApproved)
+		assert.ErrorContains(t, err, "0 of 1")
+		mocks.repo.AssertNotCalled(t, "Update", ctx, mock.Anything, mock.Anything)
+	})
 
+	t.Run("explicit zero quorum overrides the global default", func(t *testing.T) {
+		svc, mocks := setupServiceMocks()
+		svc.defaultApprovals = 1
 		pr := &model.PullRequest{
-			ID:        "pr-1",
-			AuthorID:  "author",
-			Status:    "OPEN",
-			Reviewers: []*model.User{oldRev, stayRev},
+			ID:       "pr-1",
+			AuthorID: "u1",
+			Status:   OpenStatus,
+			Reviews:  []model.PRReviewer{{UserID: "u2", Verdict: VerdictPending}},
 		}
 
-		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
-		mockUser.On("GetByID", ctx, "author").Return(author, nil)
-		expectedExcludes := []string{"author", "old", "stay"}
-		mockUser.On("GetReplacementCandidate", ctx, "Devs", expectedExcludes).Return(newRev, nil)
-
-		mockRepo.On("UpdateReviewers", ctx, mock.MatchedBy(func(updated *model.PullRequest) bool {
-			ids := make([]string, 0, len(updated.Reviewers))
-			for _, r := range updated.Reviewers {
-				ids = append(ids, r.ID)
-			}
-			return assert.Contains(t, ids, "new") &&
-				assert.Contains(t, ids, "stay") &&
-				assert.NotContains(t, ids, "old")
-		})).Return(nil)
+		mocks.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		mocks.user.On("GetByID", ctx, "u1").Return(author, nil)
+		mocks.team.On("GetSettings", ctx, "backend").Return(&model.Team{Name: "backend", RequiredApprovals: &zero}, nil)
+		mocks.repo.On("Update", ctx, mock.Anything, mock.Anything).Return(nil)
 
-		resPR, resUser, err := svc.ReassignReviewer(ctx, "pr-1", "old")
+		res, err := svc.Merge(ctx, "pr-1")
 
 		require.NoError(t, err)
-		assert.Equal(t, "new", resUser.ID)
-		assert.Len(t, resPR.Reviewers, 2)
+		assert.Equal(t, MergeStatus, res.Status)
 	})
 
-	t.Run("pr not found", func(t *testing.T) {
-		svc, _, mockRepo := setupService()
-		mockRepo.On("GetByID", ctx, "pr-1").Return(nil, gorm.ErrRecordNotFound)
+	t.Run("changes requested blocks merge", func(t *testing.T) {
+		svc, mocks := setupServiceMocks()
+		pr := &model.PullRequest{
+			ID:       "pr-1",
+			AuthorID: "u1",
+			Status:   OpenStatus,
+			Reviews: []model.PRReviewer{
+				{UserID: "u2", Verdict: VerdictApproved},
+				{UserID: "u3", Verdict: VerdictChangesRequested},
+			},
+		}
 
-		_, _, err := svc.ReassignReviewer(ctx, "pr-1", "any")
-		assert.ErrorIs(t, err, ErrPRNotFound)
+		mocks.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+
+		_, err := svc.Merge(ctx, "pr-1")
+
+		require.ErrorIs(t, err, ErrNotApproved)
+		mocks.repo.AssertNotCalled(t, "Update", ctx, mock.Anything, mock.Anything)
 	})
 
-	t.Run("pr is merged", func(t *testing.T) {
+	t.Run("already merged", func(t *testing.T) {
 		svc, _, mockRepo := setupService()
 		pr := &model.PullRequest{ID: "pr-1", Status: MergeStatus}
+
 		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
 
-		_, _, err := svc.ReassignReviewer(ctx, "pr-1", "any")
-		assert.ErrorIs(t, err, ErrPRMerged)
+		res, err := svc.Merge(ctx, "pr-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, MergeStatus, res.Status)
+		mockRepo.AssertNotCalled(t, "Update", ctx, mock.Anything, mock.Anything)
 	})
 
-	t.Run("reviewer not assigned", func(t *testing.T) {
+	t.Run("pr not found", func(t *testing.T) {
 		svc, _, mockRepo := setupService()
-		pr := &model.PullRequest{
-			ID:        "pr-1",
-			Status:    "OPEN",
-			Reviewers: []*model.User{{ID: "other"}},
-		}
-		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
 
-		_, _, err := svc.ReassignReviewer(ctx, "pr-1", "not-assigned-id")
-		assert.ErrorIs(t, err, ErrNotAssigned)
+		mockRepo.On("GetByID", ctx, "missing").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.Merge(ctx, "missing")
+
+		assert.ErrorIs(t, err, ErrPRNotFound)
 	})
 
-	t.Run("no replacement candidate", func(t *testing.T) {
-		svc, mockUser, mockRepo := setupService()
-		pr := &model.PullRequest{
-			ID:        "pr-1",
+	t.Run("draft cannot be merged", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		pr := &model.PullRequest{ID: "pr-1", Status: DraftStatus}
+
+		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+
+		_, err := svc.Merge(ctx, "pr-1")
+
+		require.ErrorIs(t, err, ErrInvalidTransition)
+		mockRepo.AssertNotCalled(t, "Update", ctx, mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_MergeExternal(t *testing.T) {
+	ctx := context.Background()
+
+	author := &model.User{ID: "u1", TeamName: "backend"}
+	one := 1
+
+	t.Run("records merge without approvals", func(t *testing.T) {
+		svc, mocks := setupServiceMocks()
+		pr := &model.PullRequest{
+			ID:       "pr-1",
+			AuthorID: "u1",
+			Status:   OpenStatus,
+			Reviews:  []model.PRReviewer{{UserID: "u2", Verdict: VerdictChangesRequested}},
+		}
+
+		mocks.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		mocks.repo.On("Update", ctx, mock.Anything, mock.MatchedBy(func(events []model.PREvent) bool {
+			return len(events) == 2 && events[1].Type == event.TypeApprovalBypassed
+		})).Return(nil)
+
+		res, err := svc.MergeExternal(ctx, "pr-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, MergeStatus, res.Status)
+		mocks.repo.AssertExpectations(t)
+	})
+
+	t.Run("quorum met adds no bypass event", func(t *testing.T) {
+		svc, mocks := setupServiceMocks()
+		pr := &model.PullRequest{
+			ID:       "pr-1",
+			AuthorID: "u1",
+			Status:   OpenStatus,
+			Reviews:  []model.PRReviewer{{UserID: "u2", Verdict: VerdictApproved}},
+		}
+
+		mocks.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		mocks.user.On("GetByID", ctx, "u1").Return(author, nil)
+		mocks.team.On("GetSettings", ctx, "backend").Return(&model.Team{Name: "backend", RequiredApprovals: &one}, nil)
+		mocks.repo.On("Update", ctx, mock.Anything, mock.MatchedBy(func(events []model.PREvent) bool {
+			return len(events) == 1
+		})).Return(nil)
+
+		_, err := svc.MergeExternal(ctx, "pr-1")
+
+		require.NoError(t, err)
+		mocks.repo.AssertExpectations(t)
+	})
+
+	t.Run("settings failure still fails", func(t *testing.T) {
+		svc, mocks := setupServiceMocks()
+		pr := &model.PullRequest{ID: "pr-1", AuthorID: "u1", Status: OpenStatus}
+		dbErr := errors.New("db down")
+
+		mocks.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		mocks.user.On("GetByID", ctx, "u1").Return(author, nil)
+		mocks.team.On("GetSettings", ctx, "backend").Return(nil, dbErr)
+
+		_, err := svc.MergeExternal(ctx, "pr-1")
+
+		require.ErrorIs(t, err, dbErr)
+		mocks.repo.AssertNotCalled(t, "Update", ctx, mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_Close(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("closes open pr", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		pr := &model.PullRequest{ID: "pr-1", Status: OpenStatus}
+
+		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		mockRepo.On("Update", ctx, mock.MatchedBy(func(updated *model.PullRequest) bool {
+			return updated.Status == ClosedStatus && updated.ClosedAt != nil
+		}), mock.Anything).Return(nil)
+
+		res, err := svc.Close(ctx, "pr-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, ClosedStatus, res.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("merged pr cannot be closed", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		pr := &model.PullRequest{ID: "pr-1", Status: MergeStatus}
+
+		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+
+		_, err := svc.Close(ctx, "pr-1")
+
+		require.ErrorIs(t, err, ErrInvalidTransition)
+	})
+}
+
+func TestService_Reopen(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("keeps existing reviewers", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		closedAt := time.Now()
+		pr := &model.PullRequest{
+			ID:        "pr-1",
+			Status:    ClosedStatus,
+			ClosedAt:  &closedAt,
+			Reviewers: []*model.User{{ID: "r1"}},
+		}
+
+		m.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		m.repo.On("Update", ctx, mock.MatchedBy(func(updated *model.PullRequest) bool {
+			return updated.Status == OpenStatus && updated.ClosedAt == nil
+		}), mock.Anything).Return(nil)
+
+		res, err := svc.Reopen(ctx, "pr-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, OpenStatus, res.Status)
+		m.selector.AssertNotCalled(t, "Select", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("draft cannot be reopened", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		pr := &model.PullRequest{ID: "pr-1", Status: DraftStatus}
+
+		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+
+		_, err := svc.Reopen(ctx, "pr-1")
+
+		require.ErrorIs(t, err, ErrInvalidTransition)
+	})
+}
+
+func TestService_MarkReady(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("assigns reviewers when leaving draft", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		pr := &model.PullRequest{ID: "pr-1", AuthorID: "u1", Status: DraftStatus}
+		author := &model.User{ID: "u1", TeamName: "Alpha"}
+		candidates := []model.User{{ID: "r1"}, {ID: "r2"}}
+
+		m.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		m.user.On("GetByID", ctx, "u1").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Alpha").Return(&model.Team{Name: "Alpha", RequiredReviewers: 2}, nil)
+		m.user.On("GetReviewCandidates", ctx, "Alpha", []string{"u1"}).Return(candidates, nil)
+		m.selector.On("Select", ctx, "Alpha", candidates, 2).Return(candidates, nil)
+		m.repo.On("Update", ctx, mock.MatchedBy(func(updated *model.PullRequest) bool {
+			return len(updated.Reviewers) == 2
+		}), mock.MatchedBy(func(events []model.PREvent) bool {
+			return len(events) == 3 && events[0].Type == event.TypeStatusChanged
+		}), mock.Anything).Return(nil)
+
+		res, err := svc.MarkReady(ctx, "pr-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, OpenStatus, res.Status)
+		assert.Len(t, res.Reviewers, 2)
+		m.repo.AssertExpectations(t)
+	})
+
+	t.Run("already open is a no-op", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		pr := &model.PullRequest{ID: "pr-1", Status: OpenStatus}
+
+		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+
+		res, err := svc.MarkReady(ctx, "pr-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, OpenStatus, res.Status)
+		mockRepo.AssertNotCalled(t, "Update", ctx, mock.Anything, mock.Anything)
+	})
+
+	t.Run("closed pr cannot be marked ready", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		pr := &model.PullRequest{ID: "pr-1", Status: ClosedStatus}
+
+		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+
+		_, err := svc.MarkReady(ctx, "pr-1")
+
+		require.ErrorIs(t, err, ErrInvalidTransition)
+	})
+}
+
+func TestService_ReassignReviewer(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success reassign", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+
+		oldRev := &model.User{ID: "old"}
+		stayRev := &model.User{ID: "stay"}
+		candidates := []model.User{{ID: "new"}, {ID: "other"}}
+		author := &model.User{ID: "author", TeamName: "Devs"}
+
+		pr := &model.PullRequest{
+			ID:        "pr-1",
+			AuthorID:  "author",
+			Status:    "OPEN",
+			Reviewers: []*model.User{oldRev, stayRev},
+		}
+
+		m.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		m.user.On("GetByID", ctx, "author").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Devs").Return(&model.Team{Name: "Devs", RequiredReviewers: 2}, nil)
+		expectedExcludes := []string{"author", "old", "stay"}
+		m.user.On("GetReviewCandidates", ctx, "Devs", expectedExcludes).Return(candidates, nil)
+		m.selector.On("Select", ctx, "Devs", candidates, 1).Return([]model.User{{ID: "new"}}, nil)
+
+		m.repo.On("Update", ctx, mock.MatchedBy(func(updated *model.PullRequest) bool {
+			ids := make([]string, 0, len(updated.Reviewers))
+			for _, r := range updated.Reviewers {
+				ids = append(ids, r.ID)
+			}
+			return assert.Contains(t, ids, "new") &&
+				assert.Contains(t, ids, "stay") &&
+				assert.NotContains(t, ids, "old")
+		}), mock.MatchedBy(func(events []model.PREvent) bool {
+			return len(events) == 1 &&
+				events[0].Type == event.TypeReassigned &&
+				events[0].UserID == "new" &&
+				events[0].PreviousUserID == "old"
+		})).Return(nil)
+
+		resPR, resUser, err := svc.ReassignReviewer(ctx, "pr-1", "old", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, "new", resUser.ID)
+		assert.Len(t, resPR.Reviewers, 2)
+		assert.Equal(t, []string{"pr-1"}, m.repo.locked)
+		require.Len(t, m.notifier.sent, 2)
+		assert.Equal(t, notify.EventUnassigned, m.notifier.sent[0].Event)
+		assert.Equal(t, []string{"old"}, m.notifier.sent[0].RecipientIDs)
+		assert.Equal(t, notify.EventAssigned, m.notifier.sent[1].Event)
+		assert.Equal(t, []string{"new"}, m.notifier.sent[1].RecipientIDs)
+	})
+
+	t.Run("tops up to required reviewers", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		author := &model.User{ID: "author", TeamName: "Security"}
+		pr := &model.PullRequest{
+			ID:        "pr-1",
 			AuthorID:  "author",
 			Status:    "OPEN",
 			Reviewers: []*model.User{{ID: "old"}},
 		}
-		author := &model.User{ID: "author", TeamName: "Devs"}
+		candidates := []model.User{{ID: "a"}, {ID: "b"}, {ID: "c"}}
 
+		m.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		m.user.On("GetByID", ctx, "author").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Security").Return(&model.Team{Name: "Security", RequiredReviewers: 3}, nil)
+		m.user.On("GetReviewCandidates", ctx, "Security", []string{"author", "old"}).Return(candidates, nil)
+		m.selector.On("Select", ctx, "Security", candidates, 3).Return(candidates, nil)
+		m.repo.On("Update", ctx, mock.Anything, mock.Anything).Return(nil)
+
+		resPR, resUser, err := svc.ReassignReviewer(ctx, "pr-1", "old", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, "a", resUser.ID)
+		assert.Len(t, resPR.Reviewers, 3)
+	})
+
+	t.Run("pr not found", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		mockRepo.On("GetByID", ctx, "pr-1").Return(nil, gorm.ErrRecordNotFound)
+
+		_, _, err := svc.ReassignReviewer(ctx, "pr-1", "any", "")
+		assert.ErrorIs(t, err, ErrPRNotFound)
+	})
+
+	t.Run("pr is merged", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		pr := &model.PullRequest{ID: "pr-1", Status: MergeStatus}
+		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+
+		_, _, err := svc.ReassignReviewer(ctx, "pr-1", "any", "")
+		assert.ErrorIs(t, err, ErrPRMerged)
+	})
+
+	t.Run("pr is closed", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		pr := &model.PullRequest{ID: "pr-1", Status: ClosedStatus}
 		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
-		mockUser.On("GetByID", ctx, "author").Return(author, nil)
-		mockUser.On("GetReplacementCandidate", ctx, "Devs", mock.Anything).Return(nil, gorm.ErrRecordNotFound)
 
-		_, _, err := svc.ReassignReviewer(ctx, "pr-1", "old")
+		_, _, err := svc.ReassignReviewer(ctx, "pr-1", "any", "")
+		assert.ErrorIs(t, err, ErrPRNotOpen)
+	})
+
+	t.Run("reviewer not assigned", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		pr := &model.PullRequest{
+			ID:        "pr-1",
+			Status:    "OPEN",
+			Reviewers: []*model.User{{ID: "other"}},
+		}
+		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+
+		_, _, err := svc.ReassignReviewer(ctx, "pr-1", "not-assigned-id", "")
+		assert.ErrorIs(t, err, ErrNotAssigned)
+	})
+
+	t.Run("falls back to global pool", func(t *testing.T) {
+		m := &serviceMocks{
+			user:     new(MockUserProvider),
+			team:     new(MockTeamProvider),
+			selector: new(MockSelector),
+			owners:   new(MockOwnerResolver),
+			repo:     new(MockPRStorer),
+			notifier: new(recordingQueue),
+		}
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		svc := NewService(m.user, m.team, m.selector, m.owners, m.repo, m.notifier, configs.Reviewers{GlobalPool: []string{"g1", "g2"}}, logger)
+		pr := &model.PullRequest{
+			ID:        "pr-1",
+			AuthorID:  "author",
+			Status:    "OPEN",
+			Reviewers: []*model.User{{ID: "old"}},
+		}
+		pool := []model.User{{ID: "g2"}}
+
+		m.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		m.user.On("GetByID", ctx, "author").Return(&model.User{ID: "author", TeamName: "Devs"}, nil)
+		m.team.On("GetSettings", ctx, "Devs").Return(&model.Team{Name: "Devs", RequiredReviewers: 1}, nil)
+		m.user.On("GetReviewCandidates", ctx, "Devs", []string{"author", "old"}).Return([]model.User{}, nil)
+		m.user.On("GetActiveUsers", ctx, []string{"g1", "g2"}, []string{"author", "old"}).Return(pool, nil)
+		m.selector.On("Select", ctx, globalPoolKey, pool, 1).Return(pool, nil)
+		m.repo.On("Update", ctx, pr, mock.Anything).Return(nil)
+
+		res, newReviewer, err := svc.ReassignReviewer(ctx, "pr-1", "old", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, "g2", newReviewer.ID)
+		assert.Equal(t, LevelGlobalPool, res.FallbackLevel)
+	})
+
+	t.Run("no replacement candidate", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		pr := &model.PullRequest{
+			ID:        "pr-1",
+			AuthorID:  "author",
+			Status:    "OPEN",
+			Reviewers: []*model.User{{ID: "old"}},
+		}
+		author := &model.User{ID: "author", TeamName: "Devs"}
+
+		m.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		m.user.On("GetByID", ctx, "author").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Devs").Return(&model.Team{Name: "Devs", RequiredReviewers: 1}, nil)
+		m.user.On("GetReviewCandidates", ctx, "Devs", mock.Anything).Return([]model.User{}, nil)
+
+		_, _, err := svc.ReassignReviewer(ctx, "pr-1", "old", "")
 		assert.ErrorIs(t, err, ErrNoCandidate)
 	})
 }
+
+func TestService_ReassignReviewer_Targeted(t *testing.T) {
+	ctx := context.Background()
+	newPR := func() *model.PullRequest {
+		return &model.PullRequest{
+			ID:        "pr-1",
+			AuthorID:  "author",
+			Status:    "OPEN",
+			Reviewers: []*model.User{{ID: "old"}, {ID: "stay"}},
+		}
+	}
+
+	t.Run("hands over to the chosen user", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+
+		m.repo.On("GetByID", ctx, "pr-1").Return(newPR(), nil)
+		m.user.On("GetByID", ctx, "target").Return(&model.User{ID: "target", IsActive: true}, nil)
+		m.repo.On("Update", ctx, mock.Anything, mock.MatchedBy(func(events []model.PREvent) bool {
+			return len(events) == 1 && events[0].Type == event.TypeReassigned && events[0].UserID == "target"
+		})).Return(nil)
+
+		res, newReviewer, err := svc.ReassignReviewer(ctx, "pr-1", "old", "target")
+
+		require.NoError(t, err)
+		assert.Equal(t, "target", newReviewer.ID)
+		assert.Equal(t, "target", res.Reviewers[0].ID)
+		m.selector.AssertNotCalled(t, "Select", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects author, assigned and inactive users", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+
+		m.repo.On("GetByID", ctx, "pr-1").Return(newPR(), nil)
+		m.user.On("GetByID", ctx, "sleepy").Return(&model.User{ID: "sleepy", IsActive: false}, nil)
+		m.user.On("GetByID", ctx, "ghost").Return(nil, gorm.ErrRecordNotFound)
+
+		_, _, err := svc.ReassignReviewer(ctx, "pr-1", "old", "author")
+		assert.ErrorIs(t, err, ErrAuthorReviewer)
+		_, _, err = svc.ReassignReviewer(ctx, "pr-1", "old", "stay")
+		assert.ErrorIs(t, err, ErrAlreadyAssigned)
+		_, _, err = svc.ReassignReviewer(ctx, "pr-1", "old", "sleepy")
+		assert.ErrorIs(t, err, ErrReviewerInactive)
+		_, _, err = svc.ReassignReviewer(ctx, "pr-1", "old", "ghost")
+		assert.ErrorIs(t, err, ErrReviewerNotFound)
+		m.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_AddReviewer(t *testing.T) {
+	ctx := context.Background()
+	author := &model.User{ID: "author", TeamName: "Devs"}
+
+	t.Run("adds active user", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		pr := &model.PullRequest{ID: "pr-1", AuthorID: "author", Status: "OPEN", Reviewers: []*model.User{{ID: "r1"}}}
+
+		m.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		m.user.On("GetByID", ctx, "r2").Return(&model.User{ID: "r2", IsActive: true}, nil)
+		m.user.On("GetByID", ctx, "author").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Devs").Return(&model.Team{Name: "Devs", RequiredReviewers: 2}, nil)
+		m.repo.On("Update", ctx, mock.Anything, mock.MatchedBy(func(events []model.PREvent) bool {
+			return len(events) == 1 && events[0].Type == event.TypeAssigned && events[0].UserID == "r2"
+		})).Return(nil)
+
+		res, err := svc.AddReviewer(ctx, "pr-1", "r2")
+
+		require.NoError(t, err)
+		assert.Len(t, res.Reviewers, 2)
+	})
+
+	t.Run("limit reached", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		pr := &model.PullRequest{ID: "pr-1", AuthorID: "author", Status: "OPEN", Reviewers: []*model.User{{ID: "r1"}, {ID: "r2"}}}
+
+		m.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		m.user.On("GetByID", ctx, "r3").Return(&model.User{ID: "r3", IsActive: true}, nil)
+		m.user.On("GetByID", ctx, "author").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Devs").Return(&model.Team{Name: "Devs", RequiredReviewers: 2}, nil)
+
+		_, err := svc.AddReviewer(ctx, "pr-1", "r3")
+
+		assert.ErrorIs(t, err, ErrReviewerLimit)
+		m.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("author cannot be added", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		pr := &model.PullRequest{ID: "pr-1", AuthorID: "author", Status: "OPEN"}
+
+		m.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+
+		_, err := svc.AddReviewer(ctx, "pr-1", "author")
+
+		assert.ErrorIs(t, err, ErrAuthorReviewer)
+	})
+}
+
+func TestService_RemoveReviewer(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("removes reviewer and verdict", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		pr := &model.PullRequest{
+			ID:        "pr-1",
+			AuthorID:  "author",
+			Status:    "OPEN",
+			Reviewers: []*model.User{{ID: "r1"}, {ID: "r2"}},
+			Reviews:   []model.PRReviewer{{UserID: "r1", Verdict: VerdictApproved}},
+		}
+
+		m.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		m.repo.On("Update", ctx, mock.Anything, mock.MatchedBy(func(events []model.PREvent) bool {
+			return len(events) == 1 && events[0].Type == event.TypeUnassigned && events[0].UserID == "r1"
+		})).Return(nil)
+
+		res, err := svc.RemoveReviewer(ctx, "pr-1", "r1")
+
+		require.NoError(t, err)
+		require.Len(t, res.Reviewers, 1)
+		assert.Equal(t, "r2", res.Reviewers[0].ID)
+		assert.Empty(t, res.Reviews)
+	})
+
+	t.Run("not assigned", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		pr := &model.PullRequest{ID: "pr-1", AuthorID: "author", Status: "OPEN"}
+
+		m.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+
+		_, err := svc.RemoveReviewer(ctx, "pr-1", "r1")
+
+		assert.ErrorIs(t, err, ErrNotAssigned)
+	})
+}
+
+func TestService_Reshuffle(t *testing.T) {
+	ctx := context.Background()
+	author := &model.User{ID: "author", TeamName: "Devs"}
+
+	t.Run("replaces reviewers and keeps old ones only to fill the gap", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		pr := &model.PullRequest{
+			ID:        "pr-1",
+			AuthorID:  "author",
+			Status:    "OPEN",
+			Reviewers: []*model.User{{ID: "r1"}, {ID: "r2"}},
+			Reviews:   []model.PRReviewer{{UserID: "r1", Verdict: VerdictApproved}, {UserID: "r2", Verdict: VerdictApproved}},
+		}
+		candidates := []model.User{{ID: "r3"}}
+
+		m.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		m.user.On("GetByID", ctx, "author").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Devs").Return(&model.Team{Name: "Devs", RequiredReviewers: 2}, nil)
+		m.user.On("GetReviewCandidates", ctx, "Devs", []string{"author", "r1", "r2"}).Return(candidates, nil)
+		m.selector.On("Select", ctx, "Devs", candidates, 2).Return(candidates, nil)
+		m.repo.On("Update", ctx, mock.Anything, mock.MatchedBy(func(events []model.PREvent) bool {
+			return len(events) == 2 &&
+				events[0].Type == event.TypeUnassigned && events[0].UserID == "r2" &&
+				events[1].Type == event.TypeAssigned && events[1].UserID == "r3"
+		})).Return(nil)
+
+		res, err := svc.Reshuffle(ctx, "pr-1")
+
+		require.NoError(t, err)
+		require.Len(t, res.Reviewers, 2)
+		assert.Equal(t, "r3", res.Reviewers[0].ID)
+		assert.Equal(t, "r1", res.Reviewers[1].ID)
+		require.Len(t, res.Reviews, 1)
+		assert.Equal(t, "r1", res.Reviews[0].UserID)
+	})
+
+	t.Run("no other candidates is a no-op", func(t *testing.T) {
+		svc, m := setupServiceMocks()
+		pr := &model.PullRequest{ID: "pr-1", AuthorID: "author", Status: "OPEN", Reviewers: []*model.User{{ID: "r1"}}}
+
+		m.repo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		m.user.On("GetByID", ctx, "author").Return(author, nil)
+		m.team.On("GetSettings", ctx, "Devs").Return(&model.Team{Name: "Devs", RequiredReviewers: 2}, nil)
+		m.user.On("GetReviewCandidates", ctx, "Devs", []string{"author", "r1"}).Return([]model.User{}, nil)
+
+		res, err := svc.Reshuffle(ctx, "pr-1")
+
+		require.NoError(t, err)
+		assert.Len(t, res.Reviewers, 1)
+		m.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_SubmitReview(t *testing.T) {
+	ctx := context.Background()
+	u2 := &model.User{ID: "u2"}
+
+	t.Run("records verdict", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		pr := &model.PullRequest{
+			ID:        "pr-1",
+			Status:    OpenStatus,
+			Reviewers: []*model.User{u2},
+			Reviews:   []model.PRReviewer{{PullRequestID: "pr-1", UserID: "u2", Verdict: VerdictPending}},
+		}
+
+		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+		mockRepo.On("SetVerdict", ctx, "pr-1", "u2", VerdictApproved, mock.Anything, mock.MatchedBy(func(events []model.PREvent) bool {
+			return len(events) == 1 && events[0].Type == event.TypeReviewSubmitted && events[0].Details == VerdictApproved
+		})).Return(nil)
+
+		res, err := svc.SubmitReview(ctx, "pr-1", "u2", VerdictApproved)
+
+		require.NoError(t, err)
+		require.Len(t, res.Reviews, 1)
+		assert.Equal(t, VerdictApproved, res.Reviews[0].Verdict)
+		assert.NotNil(t, res.Reviews[0].VerdictAt)
+		assert.Equal(t, []string{"pr-1"}, mockRepo.locked)
+	})
+
+	t.Run("invalid verdict", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+
+		_, err := svc.SubmitReview(ctx, "pr-1", "u2", "LGTM")
+
+		require.ErrorIs(t, err, ErrInvalidVerdict)
+		mockRepo.AssertNotCalled(t, "GetByID", ctx, mock.Anything)
+	})
+
+	t.Run("user not assigned", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		pr := &model.PullRequest{ID: "pr-1", Status: OpenStatus, Reviewers: []*model.User{u2}}
+
+		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+
+		_, err := svc.SubmitReview(ctx, "pr-1", "u9", VerdictApproved)
+
+		require.ErrorIs(t, err, ErrNotAssigned)
+		mockRepo.AssertNotCalled(t, "SetVerdict", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("pr not open", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		pr := &model.PullRequest{ID: "pr-1", Status: DraftStatus}
+
+		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+
+		_, err := svc.SubmitReview(ctx, "pr-1", "u2", VerdictChangesRequested)
+
+		require.ErrorIs(t, err, ErrPRNotOpen)
+	})
+}
+
+func TestService_Get(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("found", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		pr := &model.PullRequest{ID: "pr-1", Status: OpenStatus}
+
+		mockRepo.On("GetByID", ctx, "pr-1").Return(pr, nil)
+
+		res, err := svc.Get(ctx, "pr-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "pr-1", res.ID)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+
+		mockRepo.On("GetByID", ctx, "missing").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.Get(ctx, "missing")
+
+		assert.ErrorIs(t, err, ErrPRNotFound)
+	})
+}
+
+func TestService_List(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	prs := []model.PullRequest{
+		{ID: "pr-3", CreatedAt: now},
+		{ID: "pr-2", CreatedAt: now.Add(-time.Minute)},
+		{ID: "pr-1", CreatedAt: now.Add(-2 * time.Minute)},
+	}
+
+	t.Run("returns next cursor when more rows exist", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		filter := ListFilter{Status: OpenStatus, Limit: 2}
+
+		mockRepo.On("List", ctx, filter, (*PageCursor)(nil), 3).Return(prs, nil)
+
+		page, err := svc.List(ctx, filter)
+
+		require.NoError(t, err)
+		require.Len(t, page.PullRequests, 2)
+		require.NotEmpty(t, page.NextCursor)
+
+		cursor, err := decodeCursor(page.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, "pr-2", cursor.ID)
+		assert.True(t, cursor.CreatedAt.Equal(prs[1].CreatedAt))
+	})
+
+	t.Run("passes decoded cursor and stops on last page", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		after := PageCursor{CreatedAt: prs[1].CreatedAt, ID: "pr-2"}
+		filter := ListFilter{Cursor: encodeCursor(after)}
+
+		mockRepo.On("List", ctx, filter, mock.MatchedBy(func(c *PageCursor) bool {
+			return c != nil && c.ID == "pr-2" && c.CreatedAt.Equal(after.CreatedAt)
+		}), DefaultPageSize+1).Return(prs[2:], nil)
+
+		page, err := svc.List(ctx, filter)
+
+		require.NoError(t, err)
+		assert.Len(t, page.PullRequests, 1)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("clamps limit", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		filter := ListFilter{Limit: 1000}
+
+		mockRepo.On("List", ctx, filter, (*PageCursor)(nil), MaxPageSize+1).Return([]model.PullRequest{}, nil)
+
+		_, err := svc.List(ctx, filter)
+
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+
+		_, err := svc.List(ctx, ListFilter{Cursor: "%%%"})
+
+		require.ErrorIs(t, err, ErrBadCursor)
+		mockRepo.AssertNotCalled(t, "List", ctx, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_History(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns events", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+		events := []model.PREvent{{ID: 1, Type: event.TypeCreated}, {ID: 2, Type: event.TypeAssigned}}
+
+		mockRepo.On("GetByID", ctx, "pr-1").Return(&model.PullRequest{ID: "pr-1"}, nil)
+		mockRepo.On("GetEvents", ctx, "pr-1").Return(events, nil)
+
+		res, err := svc.History(ctx, "pr-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, events, res)
+	})
+
+	t.Run("pr not found", func(t *testing.T) {
+		svc, _, mockRepo := setupService()
+
+		mockRepo.On("GetByID", ctx, "missing").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.History(ctx, "missing")
+
+		require.ErrorIs(t, err, ErrPRNotFound)
+		mockRepo.AssertNotCalled(t, "GetEvents", ctx, mock.Anything)
+	})
+}
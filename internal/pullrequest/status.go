@@ -0,0 +1,21 @@
+package pullrequest
+
+const (
+	DraftStatus  = "DRAFT"
+	OpenStatus   = "OPEN"
+	ClosedStatus = "CLOSED"
+	MergeStatus  = "MERGED"
+)
+
+const (
+	VerdictPending          = "PENDING"
+	VerdictApproved         = "APPROVED"
+	VerdictChangesRequested = "CHANGES_REQUESTED"
+)
+
+// Details recorded on UNASSIGNED events for manual reviewer changes.
+const (
+	reasonRemoved    = "removed manually"
+	reasonReshuffled = "reshuffled"
+	reasonReassigned = "reassigned"
+)
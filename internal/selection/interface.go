@@ -0,0 +1,16 @@
+package selection
+
+import (
+	"context"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+type Strategy interface {
+	Pick(context.Context, string, []model.User, int) ([]model.User, error)
+}
+
+type Storer interface {
+	AdvanceCursor(context.Context, string, func(string) string) error
+	CountOpenReviews(context.Context, []string) (map[string]int, error)
+}
@@ -0,0 +1,6 @@
+package selection
+
+type reviewLoad struct {
+	UserID string `gorm:"column:user_id"`
+	Count  int    `gorm:"column:count"`
+}
@@ -0,0 +1,70 @@
+package selection
+
+import (
+	"context"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository struct {
+	db *db.PostgresDB
+}
+
+func NewRepository(db *db.PostgresDB) *Repository {
+	return &Repository{db: db}
+}
+
+// AdvanceCursor locks the cursor row of key, passes its last user ID to next ("" for a new cursor)
+// and stores the ID next returns. Concurrent picks for one key queue on the row lock, so each
+// of them sees the cursor the previous one left.
+func (r *Repository) AdvanceCursor(ctx context.Context, key string, next func(string) string) error {
+	return r.db.PostgresDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		cursor := model.ReviewerCursor{TeamName: key, UpdatedAt: time.Now()}
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&cursor).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&cursor, "team_name = ?", key).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&cursor).Updates(map[string]any{
+			"last_user_id": next(cursor.LastUserID),
+			"updated_at":   time.Now(),
+		}).Error
+	})
+}
+
+func (r *Repository) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	return OpenReviewLoads(r.db.PostgresDB.WithContext(ctx), userIDs)
+}
+
+// OpenReviewLoads counts the reviews each of userIDs holds on OPEN pull requests; users without any
+// are reported as 0. It takes db so that callers inside a transaction see their own changes.
+func OpenReviewLoads(db *gorm.DB, userIDs []string) (map[string]int, error) {
+	loads := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return loads, nil
+	}
+
+	var rows []reviewLoad
+	err := db.Table("pr_reviewers").
+		Select("pr_reviewers.user_id, count(*) as count").
+		Joins("JOIN pull_requests ON pull_requests.pull_request_id = pr_reviewers.pull_request_id").
+		Where("pr_reviewers.user_id IN ? AND pull_requests.status = ?", userIDs, "OPEN").
+		Group("pr_reviewers.user_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		loads[row.UserID] = row.Count
+	}
+	return loads, nil
+}
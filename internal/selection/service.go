@@ -0,0 +1,70 @@
+package selection
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+type Service struct {
+	strategies     map[string]Strategy
+	defaultName    string
+	teamStrategies map[string]string
+	log            *slog.Logger
+}
+
+func NewService(repo Storer, conf configs.Reviewers, log *slog.Logger) *Service {
+	s := &Service{
+		strategies: map[string]Strategy{
+			StrategyRandom:      RandomStrategy{},
+			StrategyRoundRobin:  NewRoundRobinStrategy(repo),
+			StrategyLeastLoaded: NewLeastLoadedStrategy(repo),
+		},
+		// least_loaded replaced random as the default; REVIEWER_STRATEGY=random restores the old behaviour.
+		defaultName:    StrategyLeastLoaded,
+		teamStrategies: make(map[string]string, len(conf.TeamStrategies)),
+		log:            log.With("component", "selectionService"),
+	}
+
+	if conf.Strategy != "" {
+		if _, ok := s.strategies[conf.Strategy]; ok {
+			s.defaultName = conf.Strategy
+		} else {
+			s.log.Warn("unknown reviewer strategy, using default", "strategy", conf.Strategy, "default", s.defaultName)
+		}
+	}
+	for team, name := range conf.TeamStrategies {
+		if _, ok := s.strategies[name]; !ok {
+			s.log.Warn("unknown reviewer strategy for team, using default", "team", team, "strategy", name)
+			continue
+		}
+		s.teamStrategies[team] = name
+	}
+	return s
+}
+
+func (s *Service) Select(
+	ctx context.Context,
+	teamName string,
+	candidates []model.User,
+	count int,
+) ([]model.User, error) {
+	name := s.StrategyFor(teamName)
+	log := s.log.With("op", "Select", "team", teamName, "strategy", name)
+
+	picked, err := s.strategies[name].Pick(ctx, teamName, candidates, count)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to pick reviewers", "error", err)
+		return nil, err
+	}
+	return picked, nil
+}
+
+func (s *Service) StrategyFor(teamName string) string {
+	if name, ok := s.teamStrategies[teamName]; ok {
+		return name
+	}
+	return s.defaultName
+}
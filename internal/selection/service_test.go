@@ -0,0 +1,179 @@
+package selection
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockStorer struct {
+	mock.Mock
+	saved string
+}
+
+// AdvanceCursor hands the stubbed cursor to next and records what it returns in saved.
+func (m *MockStorer) AdvanceCursor(ctx context.Context, key string, next func(string) string) error {
+	args := m.Called(ctx, key)
+	if err := args.Error(1); err != nil {
+		return err
+	}
+	m.saved = next(args.String(0))
+	return nil
+}
+
+func (m *MockStorer) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	args := m.Called(ctx, userIDs)
+	if val, ok := args.Get(0).(map[string]int); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func setupService(conf configs.Reviewers) (*Service, *MockStorer) {
+	mockRepo := new(MockStorer)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	svc := NewService(mockRepo, conf, logger)
+	return svc, mockRepo
+}
+
+func ids(users []model.User) []string {
+	result := make([]string, 0, len(users))
+	for _, u := range users {
+		result = append(result, u.ID)
+	}
+	return result
+}
+
+func TestService_StrategyFor(t *testing.T) {
+	t.Run("defaults to least loaded", func(t *testing.T) {
+		svc, _ := setupService(configs.Reviewers{})
+
+		assert.Equal(t, StrategyLeastLoaded, svc.StrategyFor("any"))
+	})
+
+	t.Run("global and team overrides", func(t *testing.T) {
+		svc, _ := setupService(configs.Reviewers{
+			Strategy:       StrategyLeastLoaded,
+			TeamStrategies: map[string]string{"backend": StrategyRoundRobin},
+		})
+
+		assert.Equal(t, StrategyLeastLoaded, svc.StrategyFor("frontend"))
+		assert.Equal(t, StrategyRoundRobin, svc.StrategyFor("backend"))
+	})
+
+	t.Run("unknown names are ignored", func(t *testing.T) {
+		svc, _ := setupService(configs.Reviewers{
+			Strategy:       "lottery",
+			TeamStrategies: map[string]string{"backend": "coin_flip"},
+		})
+
+		assert.Equal(t, StrategyLeastLoaded, svc.StrategyFor("backend"))
+	})
+}
+
+func TestService_Select(t *testing.T) {
+	ctx := context.Background()
+	candidates := []model.User{{ID: "u3"}, {ID: "u1"}, {ID: "u2"}}
+
+	t.Run("random picks requested count", func(t *testing.T) {
+		svc, mockRepo := setupService(configs.Reviewers{Strategy: StrategyRandom})
+
+		picked, err := svc.Select(ctx, "backend", candidates, 2)
+
+		require.NoError(t, err)
+		assert.Len(t, picked, 2)
+		assert.Subset(t, ids(candidates), ids(picked))
+		mockRepo.AssertNotCalled(t, "AdvanceCursor", mock.Anything, mock.Anything)
+	})
+
+	t.Run("random with fewer candidates than requested", func(t *testing.T) {
+		svc, _ := setupService(configs.Reviewers{Strategy: StrategyRandom})
+
+		picked, err := svc.Select(ctx, "backend", candidates[:1], 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u3"}, ids(picked))
+	})
+
+	t.Run("round robin continues after cursor and wraps", func(t *testing.T) {
+		svc, mockRepo := setupService(configs.Reviewers{Strategy: StrategyRoundRobin})
+
+		mockRepo.On("AdvanceCursor", ctx, "backend").Return("u2", nil)
+
+		picked, err := svc.Select(ctx, "backend", candidates, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u3", "u1"}, ids(picked))
+		assert.Equal(t, "u1", mockRepo.saved)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("round robin without cursor starts from the beginning", func(t *testing.T) {
+		svc, mockRepo := setupService(configs.Reviewers{Strategy: StrategyRoundRobin})
+
+		mockRepo.On("AdvanceCursor", ctx, "backend").Return("", nil)
+
+		picked, err := svc.Select(ctx, "backend", candidates, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u1", "u2"}, ids(picked))
+		assert.Equal(t, "u2", mockRepo.saved)
+	})
+
+	t.Run("round robin cursor error", func(t *testing.T) {
+		svc, mockRepo := setupService(configs.Reviewers{Strategy: StrategyRoundRobin})
+		dbErr := errors.New("db down")
+
+		mockRepo.On("AdvanceCursor", ctx, "backend").Return("", dbErr)
+
+		_, err := svc.Select(ctx, "backend", candidates, 2)
+
+		require.ErrorIs(t, err, dbErr)
+	})
+
+	t.Run("least loaded breaks ties randomly", func(t *testing.T) {
+		svc, mockRepo := setupService(configs.Reviewers{})
+		mockRepo.On("CountOpenReviews", ctx, mock.Anything).Return(map[string]int{"u2": 3}, nil)
+
+		seen := make(map[string]bool)
+		for range 50 {
+			picked, err := svc.Select(ctx, "backend", candidates, 1)
+			require.NoError(t, err)
+			seen[picked[0].ID] = true
+		}
+
+		assert.Equal(t, map[string]bool{"u1": true, "u3": true}, seen)
+	})
+
+	t.Run("least loaded prefers idle reviewers", func(t *testing.T) {
+		svc, mockRepo := setupService(configs.Reviewers{Strategy: StrategyLeastLoaded})
+
+		mockRepo.On("CountOpenReviews", ctx, []string{"u3", "u1", "u2"}).
+			Return(map[string]int{"u3": 4, "u1": 1}, nil)
+
+		picked, err := svc.Select(ctx, "backend", candidates, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u2", "u1"}, ids(picked))
+	})
+
+	t.Run("empty candidates", func(t *testing.T) {
+		svc, mockRepo := setupService(configs.Reviewers{Strategy: StrategyLeastLoaded})
+
+		picked, err := svc.Select(ctx, "backend", nil, 2)
+
+		require.NoError(t, err)
+		assert.Empty(t, picked)
+		mockRepo.AssertNotCalled(t, "CountOpenReviews", mock.Anything, mock.Anything)
+	})
+}
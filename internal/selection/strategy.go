@@ -0,0 +1,115 @@
+package selection
+
+import (
+	"cmp"
+	"context"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+const (
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round_robin"
+	StrategyLeastLoaded = "least_loaded"
+)
+
+type RandomStrategy struct{}
+
+func (RandomStrategy) Pick(_ context.Context, _ string, candidates []model.User, count int) ([]model.User, error) {
+	return firstN(shuffled(candidates), count), nil
+}
+
+type RoundRobinStrategy struct {
+	repo Storer
+}
+
+func NewRoundRobinStrategy(repo Storer) *RoundRobinStrategy {
+	return &RoundRobinStrategy{repo: repo}
+}
+
+func (s *RoundRobinStrategy) Pick(
+	ctx context.Context,
+	teamName string,
+	candidates []model.User,
+	count int,
+) ([]model.User, error) {
+	if len(candidates) == 0 || count <= 0 {
+		return nil, nil
+	}
+
+	ordered := slices.Clone(candidates)
+	slices.SortFunc(ordered, func(a, b model.User) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	var picked []model.User
+	err := s.repo.AdvanceCursor(ctx, teamName, func(cursor string) string {
+		start := slices.IndexFunc(ordered, func(u model.User) bool {
+			return u.ID > cursor
+		})
+		if start < 0 {
+			start = 0
+		}
+
+		picked = make([]model.User, 0, min(count, len(ordered)))
+		for i := range min(count, len(ordered)) {
+			picked = append(picked, ordered[(start+i)%len(ordered)])
+		}
+		return picked[len(picked)-1].ID
+	})
+	if err != nil {
+		return nil, err
+	}
+	return picked, nil
+}
+
+type LeastLoadedStrategy struct {
+	repo Storer
+}
+
+func NewLeastLoadedStrategy(repo Storer) *LeastLoadedStrategy {
+	return &LeastLoadedStrategy{repo: repo}
+}
+
+func (s *LeastLoadedStrategy) Pick(
+	ctx context.Context,
+	_ string,
+	candidates []model.User,
+	count int,
+) ([]model.User, error) {
+	if len(candidates) == 0 || count <= 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.ID)
+	}
+	loads, err := s.repo.CountOpenReviews(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := shuffled(candidates)
+	slices.SortStableFunc(ordered, func(a, b model.User) int {
+		return cmp.Compare(loads[a.ID], loads[b.ID])
+	})
+	return firstN(ordered, count), nil
+}
+
+func shuffled(candidates []model.User) []model.User {
+	result := slices.Clone(candidates)
+	rand.Shuffle(len(result), func(i, j int) {
+		result[i], result[j] = result[j], result[i]
+	})
+	return result
+}
+
+func firstN(candidates []model.User, count int) []model.User {
+	if count <= 0 {
+		return nil
+	}
+	return candidates[:min(count, len(candidates))]
+}
@@ -0,0 +1,19 @@
+package sla
+
+import "time"
+
+type EscalationDTO struct {
+	ID            uint64    `json:"id"`
+	PullRequestID string    `json:"pull_request_id"`
+	UserID        string    `json:"user_id"`
+	TeamName      string    `json:"team_name"`
+	Action        string    `json:"action"`
+	NewUserID     string    `json:"new_user_id,omitempty"`
+	Details       string    `json:"details,omitempty"`
+	AssignedAt    time.Time `json:"assignedAt"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+type ListResponseDTO struct {
+	Escalations []EscalationDTO `json:"escalations"`
+}
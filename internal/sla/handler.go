@@ -0,0 +1,37 @@
+package sla
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/res"
+)
+
+type Handler struct {
+	slaService Provider
+	conf       *configs.Config
+}
+
+func NewHandler(router *http.ServeMux, slaService Provider, conf *configs.Config) {
+	handler := &Handler{
+		slaService: slaService,
+		conf:       conf,
+	}
+	router.HandleFunc("GET /sla/escalations", handler.List())
+}
+
+func (h *Handler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+
+		escalations, err := h.slaService.List(ctx, ToListFilter(r.URL.Query()))
+		if err != nil {
+			res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+			return
+		}
+		resp := ToListResponse(escalations)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
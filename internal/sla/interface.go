@@ -0,0 +1,22 @@
+package sla
+
+import (
+	"context"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+type Storer interface {
+	PendingReviews(context.Context) ([]PendingReview, error)
+	Holidays(ctx context.Context, teamNames []string) (map[string]map[string]bool, error)
+	Record(context.Context, *model.Escalation) error
+	List(context.Context, ListFilter) ([]model.Escalation, error)
+}
+
+type Reassigner interface {
+	ReassignReviewer(ctx context.Context, prID, oldUserID, newUserID string) (*model.PullRequest, *model.User, error)
+}
+
+type Provider interface {
+	List(context.Context, ListFilter) ([]model.Escalation, error)
+}
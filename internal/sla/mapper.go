@@ -0,0 +1,33 @@
+package sla
+
+import (
+	"net/url"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+func ToListFilter(query url.Values) ListFilter {
+	return ListFilter{
+		TeamName:      query.Get("team_name"),
+		PullRequestID: query.Get("pull_request_id"),
+		UserID:        query.Get("user_id"),
+	}
+}
+
+func ToListResponse(escalations []model.Escalation) ListResponseDTO {
+	items := make([]EscalationDTO, 0, len(escalations))
+	for _, e := range escalations {
+		items = append(items, EscalationDTO{
+			ID:            e.ID,
+			PullRequestID: e.PullRequestID,
+			UserID:        e.UserID,
+			TeamName:      e.TeamName,
+			Action:        e.Action,
+			NewUserID:     e.NewUserID,
+			Details:       e.Details,
+			AssignedAt:    e.AssignedAt,
+			CreatedAt:     e.CreatedAt,
+		})
+	}
+	return ListResponseDTO{Escalations: items}
+}
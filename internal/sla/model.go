@@ -0,0 +1,44 @@
+package sla
+
+import (
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+// Escalation actions.
+const (
+	ActionFlagged    = "flagged"
+	ActionReassigned = "reassigned"
+)
+
+// PendingReview is an assignment on an OPEN PR that has no verdict yet, together with
+// the SLA of the author's team and the reviewer's working-hours settings.
+type PendingReview struct {
+	PullRequestID string
+	TeamName      string
+	SLAHours      int
+	SLAPolicy     string
+	AssignedAt    time.Time
+	Reviewer      model.User
+}
+
+type pendingRow struct {
+	PullRequestID string    `gorm:"column:pull_request_id"`
+	UserID        string    `gorm:"column:user_id"`
+	AssignedAt    time.Time `gorm:"column:assigned_at"`
+	TeamName      string    `gorm:"column:team_name"`
+	SLAHours      int       `gorm:"column:sla_hours"`
+	SLAPolicy     string    `gorm:"column:sla_policy"`
+	ReviewerTeam  string    `gorm:"column:reviewer_team"`
+	Timezone      string    `gorm:"column:timezone"`
+	WorkStart     string    `gorm:"column:work_start"`
+	WorkEnd       string    `gorm:"column:work_end"`
+	WorkDays      []string  `gorm:"column:work_days;serializer:json"`
+}
+
+type ListFilter struct {
+	TeamName      string
+	PullRequestID string
+	UserID        string
+}
@@ -0,0 +1,104 @@
+package sla
+
+import (
+	"context"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
+
+	"gorm.io/gorm/clause"
+)
+
+type Repository struct {
+	db *db.PostgresDB
+}
+
+func NewRepository(db *db.PostgresDB) *Repository {
+	return &Repository{db: db}
+}
+
+// PendingReviews returns unanswered assignments on OPEN PRs whose team tracks an SLA
+// and that have not been escalated yet.
+func (r *Repository) PendingReviews(ctx context.Context) ([]PendingReview, error) {
+	var rows []pendingRow
+	err := r.db.PostgresDB.WithContext(ctx).
+		Table("pr_reviewers").
+		Select("pr_reviewers.pull_request_id, pr_reviewers.user_id, pr_reviewers.assigned_at, "+
+			"teams.team_name, teams.sla_hours, teams.sla_policy, "+
+			"COALESCE(reviewer_teams.team_name, '') AS reviewer_team, "+
+			"reviewers.timezone, reviewers.work_start, reviewers.work_end, reviewers.work_days").
+		Joins("JOIN pull_requests ON pull_requests.pull_request_id = pr_reviewers.pull_request_id").
+		Joins("JOIN teams ON teams.team_name = pull_requests.team_name").
+		Joins("JOIN users reviewers ON reviewers.user_id = pr_reviewers.user_id").
+		Joins("LEFT JOIN team_members reviewer_teams ON reviewer_teams.user_id = reviewers.user_id AND reviewer_teams.is_primary").
+		Where("pull_requests.status = ? AND pr_reviewers.verdict = ? AND teams.sla_hours > 0", "OPEN", "PENDING").
+		Where("NOT EXISTS (SELECT 1 FROM escalations WHERE escalations.pull_request_id = pr_reviewers.pull_request_id" +
+			" AND escalations.user_id = pr_reviewers.user_id AND escalations.assigned_at = pr_reviewers.assigned_at)").
+		Order("pr_reviewers.assigned_at").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]PendingReview, 0, len(rows))
+	for _, row := range rows {
+		pending = append(pending, PendingReview{
+			PullRequestID: row.PullRequestID,
+			TeamName:      row.TeamName,
+			SLAHours:      row.SLAHours,
+			SLAPolicy:     row.SLAPolicy,
+			AssignedAt:    row.AssignedAt,
+			Reviewer: model.User{
+				ID:        row.UserID,
+				TeamName:  row.ReviewerTeam,
+				Timezone:  row.Timezone,
+				WorkStart: row.WorkStart,
+				WorkEnd:   row.WorkEnd,
+				WorkDays:  row.WorkDays,
+			},
+		})
+	}
+	return pending, nil
+}
+
+func (r *Repository) Holidays(ctx context.Context, teamNames []string) (map[string]map[string]bool, error) {
+	holidays := make(map[string]map[string]bool)
+	if len(teamNames) == 0 {
+		return holidays, nil
+	}
+	var rows []model.TeamHoliday
+	err := r.db.PostgresDB.WithContext(ctx).Where("team_name IN ?", teamNames).Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range rows {
+		if holidays[h.TeamName] == nil {
+			holidays[h.TeamName] = make(map[string]bool)
+		}
+		holidays[h.TeamName][h.Day] = true
+	}
+	return holidays, nil
+}
+
+// Record stores an escalation; an assignment is escalated at most once.
+func (r *Repository) Record(ctx context.Context, escalation *model.Escalation) error {
+	return r.db.PostgresDB.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(escalation).Error
+}
+
+func (r *Repository) List(ctx context.Context, filter ListFilter) ([]model.Escalation, error) {
+	query := r.db.PostgresDB.WithContext(ctx).Model(&model.Escalation{})
+	if filter.TeamName != "" {
+		query = query.Where("team_name = ?", filter.TeamName)
+	}
+	if filter.PullRequestID != "" {
+		query = query.Where("pull_request_id = ?", filter.PullRequestID)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	var escalations []model.Escalation
+	err := query.Order("created_at DESC, id DESC").Find(&escalations).Error
+	return escalations, err
+}
@@ -0,0 +1,143 @@
+package sla
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+	"github.com/SeeXWH/pr-reviewer-service/internal/workhours"
+)
+
+// Service finds reviewers who missed their team's first-response SLA and, depending on
+// the team policy, reassigns the review or only flags it. Every escalation is recorded.
+type Service struct {
+	repo       Storer
+	reassigner Reassigner
+	conf       configs.SLA
+	log        *slog.Logger
+}
+
+func NewService(repo Storer, reassigner Reassigner, conf configs.SLA, log *slog.Logger) *Service {
+	return &Service{
+		repo:       repo,
+		reassigner: reassigner,
+		conf:       conf,
+		log:        log.With("component", "slaService"),
+	}
+}
+
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.conf.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.EscalateOnce(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			s.log.ErrorContext(ctx, "sla escalation failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// EscalateOnce handles every breached assignment and returns how many were escalated.
+func (s *Service) EscalateOnce(ctx context.Context, now time.Time) (int, error) {
+	pending, err := s.repo.PendingReviews(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+	teams := make([]string, 0, len(pending))
+	for _, p := range pending {
+		teams = append(teams, p.Reviewer.TeamName)
+	}
+	holidays, err := s.repo.Holidays(ctx, teams)
+	if err != nil {
+		return 0, err
+	}
+
+	schedules := make(map[string]workhours.Schedule)
+	escalated := 0
+	for _, p := range pending {
+		schedule, ok := schedules[p.Reviewer.ID]
+		if !ok {
+			schedule = workhours.For(&p.Reviewer)
+			schedules[p.Reviewer.ID] = schedule
+		}
+		limit := time.Duration(p.SLAHours) * time.Hour
+		spent := schedule.Between(p.AssignedAt, now, holidays[p.Reviewer.TeamName])
+		if spent < limit {
+			continue
+		}
+		if err = s.escalate(ctx, p, spent); err != nil {
+			return escalated, err
+		}
+		escalated++
+	}
+	if escalated > 0 {
+		s.log.InfoContext(ctx, "sla breaches escalated", "count", escalated)
+	}
+	return escalated, nil
+}
+
+func (s *Service) escalate(ctx context.Context, p PendingReview, spent time.Duration) error {
+	log := s.log.With("op", "escalate", "pr_id", p.PullRequestID, "user_id", p.Reviewer.ID, "policy", p.SLAPolicy)
+
+	escalation := &model.Escalation{
+		PullRequestID: p.PullRequestID,
+		UserID:        p.Reviewer.ID,
+		AssignedAt:    p.AssignedAt,
+		TeamName:      p.TeamName,
+		Action:        ActionFlagged,
+		Details:       "no response after " + spent.Round(time.Minute).String() + " of working time",
+		CreatedAt:     time.Now(),
+	}
+	if p.SLAPolicy == model.SLAPolicyReassign {
+		_, newReviewer, err := s.reassigner.ReassignReviewer(ctx, p.PullRequestID, p.Reviewer.ID, "")
+		switch {
+		case err == nil:
+			escalation.Action = ActionReassigned
+			escalation.NewUserID = newReviewer.ID
+		case ctx.Err() != nil || !isExpected(err):
+			log.ErrorContext(ctx, "failed to reassign stale review", "error", err)
+			return err
+		default:
+			log.WarnContext(ctx, "stale review flagged instead of reassigned", "error", err)
+			escalation.Details += "; reassignment failed: " + err.Error()
+		}
+	}
+	if err := s.repo.Record(ctx, escalation); err != nil {
+		log.ErrorContext(ctx, "failed to record escalation", "error", err)
+		return err
+	}
+
+	log.InfoContext(ctx, "review escalated", "action", escalation.Action, "new_user_id", escalation.NewUserID)
+	return nil
+}
+
+func (s *Service) List(ctx context.Context, filter ListFilter) ([]model.Escalation, error) {
+	escalations, err := s.repo.List(ctx, filter)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to list escalations", "op", "List", "error", err)
+		return nil, err
+	}
+	return escalations, nil
+}
+
+// isExpected reports whether a reassignment error is a business outcome, such as
+// an empty candidate pool, rather than an infrastructure failure.
+func isExpected(err error) bool {
+	return errors.Is(err, pullrequest.ErrNoCandidate) ||
+		errors.Is(err, pullrequest.ErrNotAssigned) ||
+		errors.Is(err, pullrequest.ErrPRNotOpen) ||
+		errors.Is(err, pullrequest.ErrPRMerged) ||
+		errors.Is(err, pullrequest.ErrPRNotFound)
+}
@@ -0,0 +1,179 @@
+package sla
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockStorer struct {
+	mock.Mock
+}
+
+func (m *MockStorer) PendingReviews(ctx context.Context) ([]PendingReview, error) {
+	args := m.Called(ctx)
+	if val, ok := args.Get(0).([]PendingReview); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStorer) Holidays(ctx context.Context, teamNames []string) (map[string]map[string]bool, error) {
+	args := m.Called(ctx, teamNames)
+	if val, ok := args.Get(0).(map[string]map[string]bool); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStorer) Record(ctx context.Context, escalation *model.Escalation) error {
+	args := m.Called(ctx, escalation)
+	return args.Error(0)
+}
+
+func (m *MockStorer) List(ctx context.Context, filter ListFilter) ([]model.Escalation, error) {
+	args := m.Called(ctx, filter)
+	if val, ok := args.Get(0).([]model.Escalation); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockReassigner struct {
+	mock.Mock
+}
+
+func (m *MockReassigner) ReassignReviewer(
+	ctx context.Context,
+	prID, oldUserID, newUserID string,
+) (*model.PullRequest, *model.User, error) {
+	args := m.Called(ctx, prID, oldUserID, newUserID)
+	if val, ok := args.Get(1).(*model.User); ok {
+		return &model.PullRequest{ID: prID}, val, args.Error(2)
+	}
+	return nil, nil, args.Error(2)
+}
+
+func setupService() (*Service, *MockStorer, *MockReassigner) {
+	mockRepo := new(MockStorer)
+	mockReassigner := new(MockReassigner)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	svc := NewService(mockRepo, mockReassigner, configs.SLA{PollInterval: time.Minute}, logger)
+	return svc, mockRepo, mockReassigner
+}
+
+func TestService_EscalateOnce(t *testing.T) {
+	ctx := context.Background()
+	// Monday 18:00 UTC.
+	now := time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)
+	berliner := model.User{ID: "r1", TeamName: "Backend", Timezone: "Europe/Berlin", WorkStart: "09:00", WorkEnd: "17:00"}
+
+	t.Run("skips reviews within sla", func(t *testing.T) {
+		svc, repo, reassigner := setupService()
+		pending := []PendingReview{{
+			PullRequestID: "pr-1", TeamName: "Backend", SLAHours: 8, SLAPolicy: model.SLAPolicyFlag,
+			AssignedAt: now.Add(-6 * time.Hour), Reviewer: berliner,
+		}}
+
+		repo.On("PendingReviews", ctx).Return(pending, nil)
+		repo.On("Holidays", ctx, []string{"Backend"}).Return(map[string]map[string]bool{}, nil)
+
+		count, err := svc.EscalateOnce(ctx, now)
+
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		repo.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+		reassigner.AssertNotCalled(t, "ReassignReviewer", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("flags breach", func(t *testing.T) {
+		svc, repo, _ := setupService()
+		pending := []PendingReview{{
+			PullRequestID: "pr-1", TeamName: "Backend", SLAHours: 8, SLAPolicy: model.SLAPolicyFlag,
+			AssignedAt: now.Add(-4 * 24 * time.Hour), Reviewer: berliner,
+		}}
+
+		repo.On("PendingReviews", ctx).Return(pending, nil)
+		repo.On("Holidays", ctx, []string{"Backend"}).Return(map[string]map[string]bool{}, nil)
+		repo.On("Record", ctx, mock.MatchedBy(func(e *model.Escalation) bool {
+			return e.PullRequestID == "pr-1" && e.UserID == "r1" && e.Action == ActionFlagged && e.NewUserID == ""
+		})).Return(nil)
+
+		count, err := svc.EscalateOnce(ctx, now)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("reassigns breach", func(t *testing.T) {
+		svc, repo, reassigner := setupService()
+		pending := []PendingReview{{
+			PullRequestID: "pr-1", TeamName: "Backend", SLAHours: 1, SLAPolicy: model.SLAPolicyReassign,
+			AssignedAt: now.Add(-8 * time.Hour), Reviewer: model.User{ID: "r1", TeamName: "Backend"},
+		}}
+
+		repo.On("PendingReviews", ctx).Return(pending, nil)
+		repo.On("Holidays", ctx, []string{"Backend"}).Return(map[string]map[string]bool{}, nil)
+		reassigner.On("ReassignReviewer", ctx, "pr-1", "r1", "").Return(nil, &model.User{ID: "r2"}, nil)
+		repo.On("Record", ctx, mock.MatchedBy(func(e *model.Escalation) bool {
+			return e.Action == ActionReassigned && e.NewUserID == "r2"
+		})).Return(nil)
+
+		count, err := svc.EscalateOnce(ctx, now)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("falls back to flag when nobody can take over", func(t *testing.T) {
+		svc, repo, reassigner := setupService()
+		pending := []PendingReview{{
+			PullRequestID: "pr-1", TeamName: "Backend", SLAHours: 1, SLAPolicy: model.SLAPolicyReassign,
+			AssignedAt: now.Add(-8 * time.Hour), Reviewer: model.User{ID: "r1", TeamName: "Backend"},
+		}}
+
+		repo.On("PendingReviews", ctx).Return(pending, nil)
+		repo.On("Holidays", ctx, []string{"Backend"}).Return(map[string]map[string]bool{}, nil)
+		reassigner.On("ReassignReviewer", ctx, "pr-1", "r1", "").Return(nil, nil, pullrequest.ErrNoCandidate)
+		repo.On("Record", ctx, mock.MatchedBy(func(e *model.Escalation) bool {
+			return e.Action == ActionFlagged && e.NewUserID == ""
+		})).Return(nil)
+
+		count, err := svc.EscalateOnce(ctx, now)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("infrastructure error stops the run", func(t *testing.T) {
+		svc, repo, reassigner := setupService()
+		pending := []PendingReview{{
+			PullRequestID: "pr-1", TeamName: "Backend", SLAHours: 1, SLAPolicy: model.SLAPolicyReassign,
+			AssignedAt: now.Add(-8 * time.Hour), Reviewer: model.User{ID: "r1", TeamName: "Backend"},
+		}}
+		dbErr := errors.New("db down")
+
+		repo.On("PendingReviews", ctx).Return(pending, nil)
+		repo.On("Holidays", ctx, []string{"Backend"}).Return(map[string]map[string]bool{}, nil)
+		reassigner.On("ReassignReviewer", ctx, "pr-1", "r1", "").Return(nil, nil, dbErr)
+
+		_, err := svc.EscalateOnce(ctx, now)
+
+		assert.ErrorIs(t, err, dbErr)
+		repo.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+	})
+}
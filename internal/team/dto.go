@@ -1,14 +1,25 @@
 package team
 
 type CreateRequestDTO struct {
-	TeamName string                 `json:"team_name"`
-	Members  []UserCreateRequestDTO `json:"members"`
+	TeamName          string                 `json:"team_name"`
+	RequiredReviewers int                    `json:"required_reviewers,omitempty"`
+	RequiredApprovals *int                   `json:"required_approvals,omitempty"`
+	Members           []UserCreateRequestDTO `json:"members"`
+	// ParentTeam places the new team under an existing one, e.g. a squad under its department.
+	ParentTeam string `json:"parent_team,omitempty"`
 }
 
 type UserCreateRequestDTO struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	IsActive bool   `json:"is_active"`
+	Email    string `json:"email,omitempty"`
+	// Tags are areas of expertise; omitted tags keep the stored ones, an empty list clears them.
+	Tags []string `json:"tags,omitempty"`
+	// Role is member (default) or lead. IsPrimary makes this team the user's primary one;
+	// a user's first team is primary anyway.
+	Role      string `json:"role,omitempty"`
+	IsPrimary bool   `json:"is_primary,omitempty"`
 }
 
 type CreateTeamResponseDTO struct {
@@ -16,12 +27,93 @@ type CreateTeamResponseDTO struct {
 }
 
 type InfoDTO struct {
-	TeamName string      `json:"team_name"`
-	Members  []MemberDTO `json:"members"`
+	TeamName          string      `json:"team_name"`
+	RequiredReviewers int         `json:"required_reviewers"`
+	RequiredApprovals *int        `json:"required_approvals"`
+	PartnerTeam       string      `json:"partner_team,omitempty"`
+	LeadUserID        string      `json:"lead_user_id,omitempty"`
+	SLAHours          int         `json:"sla_hours,omitempty"`
+	SLAPolicy         string      `json:"sla_policy,omitempty"`
+	Members           []MemberDTO `json:"members"`
+	ParentTeam        string      `json:"parent_team,omitempty"`
+	// SubTeams is returned by /team/get with include_subteams=true, nested to any depth.
+	SubTeams []InfoDTO `json:"sub_teams,omitempty"`
 }
 
 type MemberDTO struct {
+	UserID    string   `json:"user_id"`
+	Username  string   `json:"username"`
+	IsActive  bool     `json:"is_active"`
+	Email     string   `json:"email,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Role      string   `json:"role"`
+	IsPrimary bool     `json:"is_primary"`
+}
+
+type UpdateSettingsRequestDTO struct {
+	TeamName          string `json:"team_name"`
+	RequiredReviewers *int   `json:"required_reviewers"`
+	RequiredApprovals *int   `json:"required_approvals"`
+	// PartnerTeam and LeadUserID set the fallback chain; an empty string clears the value.
+	PartnerTeam *string `json:"partner_team"`
+	LeadUserID  *string `json:"lead_user_id"`
+	// SLAHours is the first-response SLA in working hours (0 disables it), SLAPolicy is flag or reassign.
+	SLAHours  *int    `json:"sla_hours"`
+	SLAPolicy *string `json:"sla_policy"`
+	// ParentTeam moves the team under another one; an empty string makes it top-level.
+	ParentTeam *string `json:"parent_team"`
+}
+
+type HolidayDTO struct {
+	TeamName string `json:"team_name"`
+	Day      string `json:"day"`
+	Name     string `json:"name,omitempty"`
+}
+
+type HolidaysResponseDTO struct {
+	TeamName string       `json:"team_name"`
+	Holidays []HolidayDTO `json:"holidays"`
+}
+
+type DeleteHolidayRequestDTO struct {
+	TeamName string `json:"team_name"`
+	Day      string `json:"day"`
+}
+
+type AddMembersRequestDTO struct {
+	TeamName string                 `json:"team_name"`
+	Members  []UserCreateRequestDTO `json:"members"`
+}
+
+type RemoveMemberRequestDTO struct {
+	TeamName string `json:"team_name"`
 	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+}
+
+type RenameRequestDTO struct {
+	TeamName    string `json:"team_name"`
+	NewTeamName string `json:"new_team_name"`
+}
+
+type DeleteRequestDTO struct {
+	TeamName string `json:"team_name"`
+}
+
+type AssignmentDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+// ShortPRDTO is a PR left with fewer reviewers than required because nobody could be found.
+type ShortPRDTO struct {
+	PullRequestID    string `json:"pull_request_id"`
+	MissingReviewers int    `json:"missing_reviewers"`
+}
+
+type ReassignmentResponseDTO struct {
+	TeamName      string          `json:"team_name"`
+	ReassignedPRs int             `json:"reassigned_prs"`
+	Assignments   []AssignmentDTO `json:"assignments"`
+	// ShortPRs is only filled by /team/delete, ordered by PR id.
+	ShortPRs []ShortPRDTO `json:"short_prs,omitempty"`
 }
@@ -5,4 +5,14 @@ import "errors"
 var (
 	ErrTeamExists   = errors.New("team_name already exists")
 	ErrTeamNotFound = errors.New("resource not found")
+	ErrBadSettings  = errors.New("required_reviewers must be positive and required_approvals non-negative")
+	ErrBadFallback  = errors.New("invalid fallback chain")
+	ErrBadSLA       = errors.New("sla_hours must be non-negative and sla_policy flag or reassign")
+	ErrBadHoliday   = errors.New("day must be a YYYY-MM-DD date")
+	ErrNoHoliday    = errors.New("holiday not found")
+	ErrBadMembers   = errors.New("members must have user_id and username")
+	ErrNoMember     = errors.New("user is not a member of the team")
+	ErrBadName      = errors.New("new_team_name must be non-empty and differ from team_name")
+	ErrBadRole      = errors.New("role must be member or lead")
+	ErrBadParent    = errors.New("invalid parent team")
 )
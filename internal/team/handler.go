@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
 	"github.com/SeeXWH/pr-reviewer-service/pkg/req"
 	"github.com/SeeXWH/pr-reviewer-service/pkg/res"
 )
@@ -22,6 +24,14 @@ func NewHandler(router *http.ServeMux, teamService Provider, conf *configs.Confi
 	}
 	router.HandleFunc("POST /team/add", handler.Create())
 	router.HandleFunc("GET /team/get", handler.Get())
+	router.HandleFunc("POST /team/addMembers", handler.AddMembers())
+	router.HandleFunc("POST /team/removeMember", handler.RemoveMember())
+	router.HandleFunc("POST /team/rename", handler.Rename())
+	router.HandleFunc("POST /team/delete", handler.Delete())
+	router.HandleFunc("POST /team/updateSettings", handler.UpdateSettings())
+	router.HandleFunc("POST /team/addHoliday", handler.AddHoliday())
+	router.HandleFunc("GET /team/holidays", handler.ListHolidays())
+	router.HandleFunc("POST /team/deleteHoliday", handler.DeleteHoliday())
 }
 
 func (h *Handler) Create() http.HandlerFunc {
@@ -40,6 +50,9 @@ func (h *Handler) Create() http.HandlerFunc {
 			case errors.Is(err, ErrTeamExists):
 				res.Error(w, http.StatusBadRequest, "TEAM_EXISTS", err.Error())
 				return
+			case errors.Is(err, ErrBadSettings), errors.Is(err, ErrBadRole), errors.Is(err, ErrBadParent):
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+				return
 			default:
 				res.Error(w, http.StatusInternalServerError, "UNKNOWN_ERR", "unknown error")
 				return
@@ -55,13 +68,29 @@ func (h *Handler) Get() http.HandlerFunc {
 		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
 		defer cancel()
 
-		teamName := r.URL.Query().Get("team_name")
+		query := r.URL.Query()
+		teamName := query.Get("team_name")
 		if teamName == "" {
 			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
 			return
 		}
+		var includeSubTeams bool
+		if raw := query.Get("include_subteams"); raw != "" {
+			parsed, err := strconv.ParseBool(raw)
+			if err != nil {
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "include_subteams must be true or false")
+				return
+			}
+			includeSubTeams = parsed
+		}
 
-		foundTeam, err := h.teamService.GetByName(ctx, teamName)
+		var foundTeam *model.Team
+		var err error
+		if includeSubTeams {
+			foundTeam, err = h.teamService.GetWithSubTeams(ctx, teamName)
+		} else {
+			foundTeam, err = h.teamService.GetByName(ctx, teamName)
+		}
 		if err != nil {
 			switch {
 			case errors.Is(err, ErrTeamNotFound):
@@ -77,3 +106,261 @@ func (h *Handler) Get() http.HandlerFunc {
 		res.JSON(w, http.StatusOK, resp)
 	}
 }
+
+func (h *Handler) AddMembers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		reqBody, err := req.HandleBody[AddMembersRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+		if reqBody.TeamName == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+			return
+		}
+
+		updatedTeam, err := h.teamService.AddMembers(ctx, reqBody.TeamName, ToMembers(reqBody.TeamName, reqBody.Members))
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrBadMembers), errors.Is(err, ErrBadRole):
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+				return
+			case errors.Is(err, ErrTeamNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+
+		resp := ToTeamInfoDTO(updatedTeam)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) RemoveMember() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		reqBody, err := req.HandleBody[RemoveMemberRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+		if reqBody.TeamName == "" || reqBody.UserID == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "team_name and user_id are required")
+			return
+		}
+
+		result, err := h.teamService.RemoveMember(ctx, reqBody.TeamName, reqBody.UserID)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrNoMember):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+
+		resp := ToReassignmentResponse(reqBody.TeamName, result)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) Rename() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		reqBody, err := req.HandleBody[RenameRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+		if reqBody.TeamName == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+			return
+		}
+
+		renamedTeam, err := h.teamService.Rename(ctx, reqBody.TeamName, reqBody.NewTeamName)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrBadName):
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+				return
+			case errors.Is(err, ErrTeamExists):
+				res.Error(w, http.StatusBadRequest, "TEAM_EXISTS", err.Error())
+				return
+			case errors.Is(err, ErrTeamNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+
+		resp := ToTeamInfoDTO(renamedTeam)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		reqBody, err := req.HandleBody[DeleteRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+		if reqBody.TeamName == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+			return
+		}
+
+		result, err := h.teamService.Delete(ctx, reqBody.TeamName)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrTeamNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+
+		resp := ToReassignmentResponse(reqBody.TeamName, result)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) UpdateSettings() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		reqBody, err := req.HandleBody[UpdateSettingsRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+		if reqBody.TeamName == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+			return
+		}
+
+		updatedTeam, err := h.teamService.UpdateSettings(ctx, reqBody.TeamName, ToSettings(*reqBody))
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrTeamNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			case errors.Is(err, ErrBadSettings), errors.Is(err, ErrBadFallback), errors.Is(err, ErrBadSLA),
+				errors.Is(err, ErrBadParent):
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+
+		resp := ToTeamInfoDTO(updatedTeam)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) AddHoliday() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		reqBody, err := req.HandleBody[HolidayDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+		if reqBody.TeamName == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+			return
+		}
+
+		holiday, err := h.teamService.AddHoliday(ctx, ToHoliday(*reqBody))
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrBadHoliday):
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+				return
+			case errors.Is(err, ErrTeamNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+
+		resp := ToHolidayDTO(holiday)
+		res.JSON(w, http.StatusCreated, resp)
+	}
+}
+
+func (h *Handler) ListHolidays() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+
+		teamName := r.URL.Query().Get("team_name")
+		if teamName == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+			return
+		}
+
+		holidays, err := h.teamService.ListHolidays(ctx, teamName)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrTeamNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+
+		resp := ToHolidaysResponse(teamName, holidays)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) DeleteHoliday() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		reqBody, err := req.HandleBody[DeleteHolidayRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+		if reqBody.TeamName == "" || reqBody.Day == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "team_name and day are required")
+			return
+		}
+
+		if err = h.teamService.DeleteHoliday(ctx, reqBody.TeamName, reqBody.Day); err != nil {
+			switch {
+			case errors.Is(err, ErrNoHoliday):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -4,14 +4,55 @@ import (
 	"context"
 
 	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
+	"github.com/SeeXWH/pr-reviewer-service/internal/user"
+
+	"gorm.io/gorm"
 )
 
 type Provider interface {
 	Create(context.Context, *model.Team) (*model.Team, error)
 	GetByName(context.Context, string) (*model.Team, error)
+	GetWithSubTeams(context.Context, string) (*model.Team, error)
+	AddMembers(context.Context, string, []model.TeamMember) (*model.Team, error)
+	RemoveMember(context.Context, string, string) (Reassignment, error)
+	Rename(context.Context, string, string) (*model.Team, error)
+	Delete(context.Context, string) (Reassignment, error)
+	UpdateSettings(context.Context, string, Settings) (*model.Team, error)
+	AddHoliday(context.Context, model.TeamHoliday) (*model.TeamHoliday, error)
+	ListHolidays(context.Context, string) ([]model.TeamHoliday, error)
+	DeleteHoliday(context.Context, string, string) error
 }
 
 type Storer interface {
 	Create(context.Context, *model.Team) error
 	GetByName(context.Context, string) (*model.Team, error)
+	GetSettings(context.Context, string) (*model.Team, error)
+	GetSubTeams(context.Context, string) ([]model.Team, error)
+	GetDescendants(context.Context, string) ([]model.Team, error)
+	AddMembers(context.Context, string, []model.TeamMember) error
+	RemoveMember(context.Context, string, string) (Reassignment, error)
+	Rename(context.Context, string, string) error
+	Delete(context.Context, string, []string) (Reassignment, error)
+	UpdateSettings(context.Context, string, Settings) (*model.Team, error)
+	UserExists(context.Context, string) (bool, error)
+	SaveHoliday(context.Context, *model.TeamHoliday) error
+	ListHolidays(context.Context, string) ([]model.TeamHoliday, error)
+	DeleteHoliday(context.Context, string, string) error
+}
+
+// Reassigner hands the open reviews of departing members to other reviewers inside tx.
+type Reassigner interface {
+	ReassignReviewsTx(tx *gorm.DB, teamName string, userIDs []string, reason string) ([]user.Assignment, int, error)
+	ReassignPRReviewsTx(
+		tx *gorm.DB,
+		prIDs []string,
+		userIDs []string,
+		fallback []user.FallbackLevel,
+		reason string,
+	) (user.ReviewReassignment, error)
+}
+
+type NotificationQueue interface {
+	Notify(context.Context, notify.Notification)
 }
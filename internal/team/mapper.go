@@ -1,45 +1,64 @@
 package team
 
-import "github.com/SeeXWH/pr-reviewer-service/internal/model"
+import (
+	"maps"
+	"slices"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
 
 func ToDomain(req CreateRequestDTO) model.Team {
-	members := make([]model.User, len(req.Members))
+	return model.Team{
+		Name:              req.TeamName,
+		RequiredReviewers: req.RequiredReviewers,
+		RequiredApprovals: req.RequiredApprovals,
+		Members:           ToMembers(req.TeamName, req.Members),
+		ParentTeam:        req.ParentTeam,
+	}
+}
 
-	for i, m := range req.Members {
-		members[i] = model.User{
-			ID:       m.UserID,
-			Username: m.Username,
-			IsActive: m.IsActive,
-			TeamName: req.TeamName,
+func ToMembers(teamName string, reqMembers []UserCreateRequestDTO) []model.TeamMember {
+	members := make([]model.TeamMember, len(reqMembers))
+	for i, m := range reqMembers {
+		members[i] = model.TeamMember{
+			TeamName:  teamName,
+			UserID:    m.UserID,
+			Role:      m.Role,
+			IsPrimary: m.IsPrimary,
+			User: model.User{
+				ID:       m.UserID,
+				Username: m.Username,
+				IsActive: m.IsActive,
+				Email:    m.Email,
+				Tags:     m.Tags,
+			},
 		}
 	}
+	return members
+}
 
-	return model.Team{
-		Name:    req.TeamName,
-		Members: members,
+func toMemberDTOs(members []model.TeamMember) []MemberDTO {
+	dtos := make([]MemberDTO, len(members))
+	for i, m := range members {
+		dtos[i] = MemberDTO{
+			UserID:    m.UserID,
+			Username:  m.User.Username,
+			IsActive:  m.User.IsActive,
+			Email:     m.User.Email,
+			Tags:      m.User.Tags,
+			Role:      m.Role,
+			IsPrimary: m.IsPrimary,
+		}
 	}
+	return dtos
 }
 func ToResponse(t *model.Team) CreateTeamResponseDTO {
 	if t == nil {
 		return CreateTeamResponseDTO{}
 	}
 
-	members := make([]MemberDTO, len(t.Members))
-	for i, m := range t.Members {
-		members[i] = MemberDTO{
-			UserID:   m.ID,
-			Username: m.Username,
-			IsActive: m.IsActive,
-		}
-	}
-
-	teamInfo := InfoDTO{
-		TeamName: t.Name,
-		Members:  members,
-	}
-
 	return CreateTeamResponseDTO{
-		Team: teamInfo,
+		Team: ToTeamInfoDTO(t),
 	}
 }
 
@@ -48,17 +67,75 @@ func ToTeamInfoDTO(t *model.Team) InfoDTO {
 		return InfoDTO{}
 	}
 
-	members := make([]MemberDTO, len(t.Members))
-	for i, m := range t.Members {
-		members[i] = MemberDTO{
-			UserID:   m.ID,
-			Username: m.Username,
-			IsActive: m.IsActive,
-		}
+	info := InfoDTO{
+		TeamName:          t.Name,
+		RequiredReviewers: t.RequiredReviewers,
+		RequiredApprovals: t.RequiredApprovals,
+		PartnerTeam:       t.PartnerTeam,
+		LeadUserID:        t.LeadUserID,
+		SLAHours:          t.SLAHours,
+		SLAPolicy:         t.SLAPolicy,
+		Members:           toMemberDTOs(t.Members),
+		ParentTeam:        t.ParentTeam,
+	}
+	for i := range t.SubTeams {
+		info.SubTeams = append(info.SubTeams, ToTeamInfoDTO(&t.SubTeams[i]))
+	}
+	return info
+}
+
+func ToSettings(req UpdateSettingsRequestDTO) Settings {
+	return Settings{
+		RequiredReviewers: req.RequiredReviewers,
+		RequiredApprovals: req.RequiredApprovals,
+		PartnerTeam:       req.PartnerTeam,
+		LeadUserID:        req.LeadUserID,
+		SLAHours:          req.SLAHours,
+		SLAPolicy:         req.SLAPolicy,
+		ParentTeam:        req.ParentTeam,
+	}
+}
+
+func ToHoliday(req HolidayDTO) model.TeamHoliday {
+	return model.TeamHoliday{
+		TeamName: req.TeamName,
+		Day:      req.Day,
+		Name:     req.Name,
 	}
+}
 
-	return InfoDTO{
-		TeamName: t.Name,
-		Members:  members,
+func ToHolidayDTO(h *model.TeamHoliday) HolidayDTO {
+	return HolidayDTO{
+		TeamName: h.TeamName,
+		Day:      h.Day,
+		Name:     h.Name,
+	}
+}
+
+func ToHolidaysResponse(teamName string, holidays []model.TeamHoliday) HolidaysResponseDTO {
+	items := make([]HolidayDTO, 0, len(holidays))
+	for i := range holidays {
+		items = append(items, ToHolidayDTO(&holidays[i]))
+	}
+	return HolidaysResponseDTO{
+		TeamName: teamName,
+		Holidays: items,
+	}
+}
+
+func ToReassignmentResponse(teamName string, result Reassignment) ReassignmentResponseDTO {
+	assignments := make([]AssignmentDTO, len(result.Assignments))
+	for i, a := range result.Assignments {
+		assignments[i] = AssignmentDTO{PullRequestID: a.PullRequestID, UserID: a.UserID}
+	}
+	var short []ShortPRDTO
+	for _, id := range slices.Sorted(maps.Keys(result.Short)) {
+		short = append(short, ShortPRDTO{PullRequestID: id, MissingReviewers: result.Short[id]})
+	}
+	return ReassignmentResponseDTO{
+		TeamName:      teamName,
+		ReassignedPRs: result.ReassignedCount,
+		Assignments:   assignments,
+		ShortPRs:      short,
 	}
 }
@@ -0,0 +1,27 @@
+package team
+
+import "github.com/SeeXWH/pr-reviewer-service/internal/user"
+
+type Settings struct {
+	RequiredReviewers *int
+	RequiredApprovals *int
+	PartnerTeam       *string
+	LeadUserID        *string
+	SLAHours          *int
+	SLAPolicy         *string
+	ParentTeam        *string
+}
+
+// Reassignment is the outcome of handing the open reviews of departing members to other reviewers.
+type Reassignment struct {
+	ReassignedCount int
+	Assignments     []user.Assignment
+	// Short maps PRs nobody could be found for to the number of reviewers they still lack.
+	Short map[string]int
+}
+
+// Details recorded on UNASSIGNED events when membership changes take reviews away.
+const (
+	reasonRemoved     = "removed from team"
+	reasonTeamDeleted = "team deleted"
+)
@@ -2,47 +2,360 @@ package team
 
 import (
 	"context"
+	"slices"
 
 	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/user"
 	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+// descendantsSQL selects the names of every team below the given one, at any depth.
+// UNION rather than UNION ALL stops the recursion should the stored hierarchy contain a cycle.
+const descendantsSQL = `WITH RECURSIVE subtree AS (
+	SELECT team_name FROM teams WHERE parent_team = ?
+	UNION
+	SELECT teams.team_name FROM teams JOIN subtree ON teams.parent_team = subtree.team_name
+) SELECT team_name FROM subtree`
+
+// InSubtree limits a query to rows whose column is teamName or the name of one of its sub-teams.
+func InSubtree(column, teamName string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(column+" = ? OR "+column+" IN (?)", teamName, gorm.Expr(descendantsSQL, teamName))
+	}
+}
+
 type Repository struct {
-	db *db.PostgresDB
+	db         *db.PostgresDB
+	reassigner Reassigner
 }
 
-func NewRepository(db *db.PostgresDB) *Repository {
+func NewRepository(db *db.PostgresDB, reassigner Reassigner) *Repository {
 	return &Repository{
-		db: db,
+		db:         db,
+		reassigner: reassigner,
 	}
 }
 
 func (r *Repository) Create(ctx context.Context, team *model.Team) error {
 	return r.db.PostgresDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Create(&model.Team{Name: team.Name}).Error; err != nil {
+		settings := settingsRow(team)
+		if err := tx.Create(&settings).Error; err != nil {
+			return err
+		}
+		return upsertMembers(tx, team.Name, team.Members)
+	})
+}
+
+// settingsRow is the teams row stored for a new team: everything but its members.
+func settingsRow(team *model.Team) model.Team {
+	return model.Team{
+		Name:              team.Name,
+		RequiredReviewers: team.RequiredReviewers,
+		RequiredApprovals: team.RequiredApprovals,
+		ParentTeam:        team.ParentTeam,
+	}
+}
+
+// AddMembers puts users into an existing team, creating the ones that are new.
+// Existing members get their profile and role updated.
+func (r *Repository) AddMembers(ctx context.Context, teamName string, members []model.TeamMember) error {
+	return r.db.PostgresDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockTeam(tx, teamName, &model.Team{}); err != nil {
+			return err
+		}
+		return upsertMembers(tx, teamName, members)
+	})
+}
+
+// RemoveMember ends the user's membership and hands their open reviews on the team's PRs to the
+// remaining members. The user is kept: PRs, history and other memberships still reference them.
+func (r *Repository) RemoveMember(ctx context.Context, teamName, userID string) (Reassignment, error) {
+	var result Reassignment
+
+	err := r.db.PostgresDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("team_name = ? AND user_id = ?", teamName, userID).Delete(&model.TeamMember{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := EnsurePrimary(tx, []string{userID}); err != nil {
 			return err
 		}
-		if len(team.Members) > 0 {
-			err := tx.Clauses(clause.OnConflict{
-				Columns:   []clause.Column{{Name: "user_id"}},
-				DoUpdates: clause.AssignmentColumns([]string{"username", "is_active", "team_name"}),
-			}).Create(&team.Members).Error
+		return r.reassign(tx, &result, teamName, []string{userID}, reasonRemoved)
+	})
+
+	return result, err
+}
 
-			if err != nil {
+// Rename changes the team name everywhere it is stored, including partner links of other teams.
+// Memberships reference the team row, so a copy is created under the new name before the old one is removed.
+func (r *Repository) Rename(ctx context.Context, teamName, newName string) error {
+	return r.db.PostgresDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var team model.Team
+		if err := lockTeam(tx, teamName, &team); err != nil {
+			return err
+		}
+		renamed := team
+		renamed.Name = newName
+		if err := tx.Create(&renamed).Error; err != nil {
+			return err
+		}
+		refs := []any{
+			&model.TeamMember{}, &model.PullRequest{}, &model.TeamHoliday{},
+			&model.OwnershipRule{}, &model.ReviewerCursor{}, &model.Escalation{},
+		}
+		for _, m := range refs {
+			if err := tx.Model(m).Where("team_name = ?", teamName).Update("team_name", newName).Error; err != nil {
 				return err
 			}
 		}
-		return nil
+		if err := tx.Model(&model.Team{}).Where("partner_team = ?", teamName).Update("partner_team", newName).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Team{}).Where("parent_team = ?", teamName).Update("parent_team", newName).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&team).Error
 	})
 }
 
+// Delete removes the team, its settings and memberships. Its open PRs move to the partner team when
+// one is set, otherwise to each author's primary team or the parent team. The former members'
+// reviews on them are reassigned through the same fallback chain PR creation uses, ending with
+// globalPool. Sub-teams move up to the deleted team's parent. Escalations are kept as history.
+func (r *Repository) Delete(ctx context.Context, teamName string, globalPool []string) (Reassignment, error) {
+	var result Reassignment
+
+	err := r.db.PostgresDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var team model.Team
+		if err := lockTeam(tx, teamName, &team); err != nil {
+			return err
+		}
+		var memberIDs []string
+		err := tx.Model(&model.TeamMember{}).Where("team_name = ?", teamName).Pluck("user_id", &memberIDs).Error
+		if err != nil {
+			return err
+		}
+		if err = tx.Where("team_name = ?", teamName).Delete(&model.TeamMember{}).Error; err != nil {
+			return err
+		}
+		if err = EnsurePrimary(tx, memberIDs); err != nil {
+			return err
+		}
+		fallback, err := fallbackLevels(tx, &team, globalPool)
+		if err != nil {
+			return err
+		}
+		if err = r.retargetPRs(tx, &result, &team, memberIDs, fallback); err != nil {
+			return err
+		}
+
+		for _, m := range []any{&model.TeamHoliday{}, &model.OwnershipRule{}, &model.ReviewerCursor{}} {
+			if err = tx.Where("team_name = ?", teamName).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+		err = tx.Model(&model.Team{}).Where("partner_team = ?", teamName).Update("partner_team", "").Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&model.Team{}).Where("parent_team = ?", teamName).Update("parent_team", team.ParentTeam).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&team).Error
+	})
+
+	return result, err
+}
+
+// fallbackLevels lists who takes over reviews once a PR's new team has nobody left, in the order
+// PR creation tries them: the teams beside the deleted one under the same parent, the parent, the
+// deleted team's lead and the global pool.
+func fallbackLevels(tx *gorm.DB, team *model.Team, globalPool []string) ([]user.FallbackLevel, error) {
+	var levels []user.FallbackLevel
+	if team.ParentTeam != "" {
+		var siblings []string
+		err := tx.Model(&model.Team{}).
+			Where("parent_team = ? AND team_name NOT IN ?", team.ParentTeam, []string{team.Name, team.PartnerTeam}).
+			Order("team_name").
+			Pluck("team_name", &siblings).Error
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels,
+			user.FallbackLevel{Teams: siblings},
+			user.FallbackLevel{Teams: []string{team.ParentTeam}},
+		)
+	}
+	if team.LeadUserID != "" {
+		levels = append(levels, user.FallbackLevel{UserIDs: []string{team.LeadUserID}})
+	}
+	if len(globalPool) > 0 {
+		levels = append(levels, user.FallbackLevel{UserIDs: globalPool})
+	}
+	return levels, nil
+}
+
+// retargetPRs moves the unmerged PRs of a deleted team to its partner, or without one to each
+// author's primary team and failing that to the parent team, then reassigns the reviews that
+// former members hold on them. Members who also belong to the partner team keep theirs.
+func (r *Repository) retargetPRs(
+	tx *gorm.DB,
+	result *Reassignment,
+	team *model.Team,
+	memberIDs []string,
+	fallback []user.FallbackLevel,
+) error {
+	var prIDs []string
+	err := tx.Model(&model.PullRequest{}).
+		Where("team_name = ? AND status <> ?", team.Name, "MERGED").
+		Pluck("pull_request_id", &prIDs).Error
+	if err != nil || len(prIDs) == 0 {
+		return err
+	}
+	prs := tx.Model(&model.PullRequest{}).Where("pull_request_id IN ?", prIDs)
+
+	departing := memberIDs
+	if team.PartnerTeam == "" {
+		primary := tx.Table("team_members").
+			Select("team_members.team_name").
+			Where("team_members.user_id = pull_requests.author_id AND team_members.is_primary")
+		err = prs.Update("team_name", gorm.Expr("COALESCE((?), ?)", primary, team.ParentTeam)).Error
+	} else {
+		if err = prs.Update("team_name", team.PartnerTeam).Error; err != nil {
+			return err
+		}
+		departing, err = notInTeam(tx, team.PartnerTeam, memberIDs)
+	}
+	if err != nil || len(departing) == 0 {
+		return err
+	}
+
+	reassigned, err := r.reassigner.ReassignPRReviewsTx(tx, prIDs, departing, fallback, reasonTeamDeleted)
+	if err != nil {
+		return err
+	}
+	result.ReassignedCount += reassigned.ReassignedCount
+	result.Assignments = append(result.Assignments, reassigned.Assignments...)
+	result.Short = reassigned.Missing
+	return nil
+}
+
+// notInTeam returns the userIDs that are not members of teamName.
+func notInTeam(tx *gorm.DB, teamName string, userIDs []string) ([]string, error) {
+	var inTeam []string
+	err := tx.Model(&model.TeamMember{}).
+		Where("team_name = ? AND user_id IN ?", teamName, userIDs).
+		Pluck("user_id", &inTeam).Error
+	if err != nil {
+		return nil, err
+	}
+	others := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		if !slices.Contains(inTeam, id) {
+			others = append(others, id)
+		}
+	}
+	return others, nil
+}
+
+func (r *Repository) reassign(tx *gorm.DB, result *Reassignment, teamName string, userIDs []string, reason string) error {
+	assignments, count, err := r.reassigner.ReassignReviewsTx(tx, teamName, userIDs, reason)
+	if err != nil {
+		return err
+	}
+	result.ReassignedCount += count
+	result.Assignments = append(result.Assignments, assignments...)
+	return nil
+}
+
+func lockTeam(tx *gorm.DB, teamName string, team *model.Team) error {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(team, "team_name = ?", teamName).Error
+}
+
+// upsertMembers creates users or updates existing ones and their membership in teamName.
+// An omitted email or tag list keeps the stored one.
+func upsertMembers(tx *gorm.DB, teamName string, members []model.TeamMember) error {
+	if len(members) == 0 {
+		return nil
+	}
+	users := make([]model.User, len(members))
+	memberships := make([]model.TeamMember, len(members))
+	var primary []string
+	for i, m := range members {
+		users[i] = m.User
+		users[i].ID = m.UserID
+		memberships[i] = model.TeamMember{TeamName: teamName, UserID: m.UserID, Role: m.Role}
+		if m.IsPrimary {
+			primary = append(primary, m.UserID)
+		}
+	}
+
+	updates := append(clause.AssignmentColumns([]string{"username", "is_active"}),
+		clause.Assignment{
+			Column: clause.Column{Name: "email"},
+			Value:  gorm.Expr("COALESCE(NULLIF(excluded.email, ''), users.email)"),
+		},
+		clause.Assignment{
+			Column: clause.Column{Name: "tags"},
+			Value:  gorm.Expr("COALESCE(excluded.tags, users.tags)"),
+		},
+	)
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: updates,
+	}).Create(&users).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Omit("User").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "team_name"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role"}),
+	}).Create(&memberships).Error
+	if err != nil {
+		return err
+	}
+
+	if len(primary) > 0 {
+		err = tx.Model(&model.TeamMember{}).
+			Where("user_id IN ?", primary).
+			Update("is_primary", gorm.Expr("team_name = ?", teamName)).Error
+		if err != nil {
+			return err
+		}
+	}
+	ids := make([]string, len(members))
+	for i := range members {
+		ids[i] = members[i].UserID
+	}
+	return EnsurePrimary(tx, ids)
+}
+
+// EnsurePrimary makes the alphabetically first membership primary for each of userIDs left without one.
+// Every change to memberships runs it in the same transaction, so a user in any team has a primary one.
+func EnsurePrimary(tx *gorm.DB, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return tx.Exec(`UPDATE team_members SET is_primary = true
+		WHERE (team_name, user_id) IN (
+			SELECT DISTINCT ON (user_id) team_name, user_id FROM team_members
+			WHERE user_id IN ? AND user_id NOT IN (SELECT user_id FROM team_members WHERE is_primary)
+			ORDER BY user_id, team_name
+		)`, userIDs).Error
+}
+
 func (r *Repository) GetByName(ctx context.Context, teamName string) (*model.Team, error) {
 	var team model.Team
 	err := r.db.PostgresDB.WithContext(ctx).
-		Preload("Members").
+		Preload("Members", func(db *gorm.DB) *gorm.DB { return db.Order("user_id") }).
+		Preload("Members.User").
 		First(&team, "team_name = ?", teamName).Error
 
 	if err != nil {
@@ -51,3 +364,107 @@ func (r *Repository) GetByName(ctx context.Context, teamName string) (*model.Tea
 
 	return &team, nil
 }
+
+func (r *Repository) GetSettings(ctx context.Context, teamName string) (*model.Team, error) {
+	var team model.Team
+	err := r.db.PostgresDB.WithContext(ctx).First(&team, "team_name = ?", teamName).Error
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// GetSubTeams returns the settings of the teams directly below parent, ordered by name.
+func (r *Repository) GetSubTeams(ctx context.Context, parent string) ([]model.Team, error) {
+	var teams []model.Team
+	err := r.db.PostgresDB.WithContext(ctx).
+		Where("parent_team = ?", parent).
+		Order("team_name").
+		Find(&teams).Error
+	return teams, err
+}
+
+// GetDescendants returns every team below teamName, at any depth, with their members.
+func (r *Repository) GetDescendants(ctx context.Context, teamName string) ([]model.Team, error) {
+	var teams []model.Team
+	err := r.db.PostgresDB.WithContext(ctx).
+		Preload("Members", func(db *gorm.DB) *gorm.DB { return db.Order("user_id") }).
+		Preload("Members.User").
+		Where("team_name IN (?)", gorm.Expr(descendantsSQL, teamName)).
+		Order("team_name").
+		Find(&teams).Error
+	return teams, err
+}
+
+func (r *Repository) UserExists(ctx context.Context, userID string) (bool, error) {
+	var count int64
+	err := r.db.PostgresDB.WithContext(ctx).Model(&model.User{}).Where("user_id = ?", userID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *Repository) UpdateSettings(ctx context.Context, teamName string, settings Settings) (*model.Team, error) {
+	team, err := r.GetSettings(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]any)
+	if settings.RequiredReviewers != nil {
+		updates["required_reviewers"] = *settings.RequiredReviewers
+	}
+	if settings.RequiredApprovals != nil {
+		updates["required_approvals"] = *settings.RequiredApprovals
+	}
+	if settings.PartnerTeam != nil {
+		updates["partner_team"] = *settings.PartnerTeam
+	}
+	if settings.LeadUserID != nil {
+		updates["lead_user_id"] = *settings.LeadUserID
+	}
+	if settings.SLAHours != nil {
+		updates["sla_hours"] = *settings.SLAHours
+	}
+	if settings.SLAPolicy != nil {
+		updates["sla_policy"] = *settings.SLAPolicy
+	}
+	if settings.ParentTeam != nil {
+		updates["parent_team"] = *settings.ParentTeam
+	}
+	if len(updates) > 0 {
+		if err = r.db.PostgresDB.WithContext(ctx).Model(team).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+	return r.GetByName(ctx, teamName)
+}
+
+// SaveHoliday adds a team holiday or renames an existing one for the same day.
+func (r *Repository) SaveHoliday(ctx context.Context, holiday *model.TeamHoliday) error {
+	return r.db.PostgresDB.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "team_name"}, {Name: "day"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name"}),
+		}).
+		Create(holiday).Error
+}
+
+func (r *Repository) ListHolidays(ctx context.Context, teamName string) ([]model.TeamHoliday, error) {
+	var holidays []model.TeamHoliday
+	err := r.db.PostgresDB.WithContext(ctx).
+		Where("team_name = ?", teamName).
+		Order("day").
+		Find(&holidays).Error
+	return holidays, err
+}
+
+func (r *Repository) DeleteHoliday(ctx context.Context, teamName string, day string) error {
+	res := r.db.PostgresDB.WithContext(ctx).
+		Delete(&model.TeamHoliday{}, "team_name = ? AND day = ?", teamName, day)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -3,28 +3,51 @@ package team
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/SeeXWH/pr-reviewer-service/configs"
 	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/user"
 
 	"gorm.io/gorm"
 )
 
 type Service struct {
-	repo Storer
-	log  *slog.Logger
+	repo       Storer
+	notifier   NotificationQueue
+	globalPool []string
+	log        *slog.Logger
 }
 
-func NewService(repo Storer, log *slog.Logger) *Service {
+func NewService(repo Storer, notifier NotificationQueue, conf configs.Reviewers, log *slog.Logger) *Service {
 	return &Service{
-		repo: repo,
-		log:  log.With("component", "teamService"),
+		repo:       repo,
+		notifier:   notifier,
+		globalPool: conf.GlobalPool,
+		log:        log.With("component", "teamService"),
 	}
 }
 
 func (s *Service) Create(ctx context.Context, team *model.Team) (*model.Team, error) {
 	log := s.log.With("op", "Create", "team_name", team.Name)
 
+	if team.RequiredReviewers < 0 || (team.RequiredApprovals != nil && *team.RequiredApprovals < 0) {
+		return nil, ErrBadSettings
+	}
+	if team.RequiredReviewers == 0 {
+		team.RequiredReviewers = model.DefaultRequiredReviewers
+	}
+	if err := prepareMembers(team.Members); err != nil {
+		return nil, err
+	}
+	if team.ParentTeam != "" {
+		if err := s.validateParent(ctx, team.Name, team.ParentTeam); err != nil {
+			log.WarnContext(ctx, "invalid parent team", "error", err)
+			return nil, err
+		}
+	}
 	err := s.repo.Create(ctx, team)
 	if err != nil {
 		if errors.Is(err, gorm.ErrDuplicatedKey) {
@@ -50,3 +73,301 @@ func (s *Service) GetByName(ctx context.Context, name string) (*model.Team, erro
 	}
 	return team, nil
 }
+
+// GetWithSubTeams returns the team with its members and the whole hierarchy below it.
+func (s *Service) GetWithSubTeams(ctx context.Context, name string) (*model.Team, error) {
+	team, err := s.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	descendants, err := s.repo.GetDescendants(ctx, name)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to get sub-teams", "op", "GetWithSubTeams", "team_name", name, "error", err)
+		return nil, err
+	}
+
+	children := make(map[string][]model.Team)
+	for _, t := range descendants {
+		children[t.ParentTeam] = append(children[t.ParentTeam], t)
+	}
+	attachSubTeams(team, children, map[string]bool{team.Name: true})
+	return team, nil
+}
+
+// attachSubTeams builds the tree below team from children, which maps a parent name to its teams.
+func attachSubTeams(team *model.Team, children map[string][]model.Team, seen map[string]bool) {
+	for _, child := range children[team.Name] {
+		if seen[child.Name] {
+			continue
+		}
+		seen[child.Name] = true
+		attachSubTeams(&child, children, seen)
+		team.SubTeams = append(team.SubTeams, child)
+	}
+}
+
+// GetSubTeams returns the teams directly below parent.
+func (s *Service) GetSubTeams(ctx context.Context, parent string) ([]model.Team, error) {
+	teams, err := s.repo.GetSubTeams(ctx, parent)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to get sub-teams", "op", "GetSubTeams", "team_name", parent, "error", err)
+		return nil, err
+	}
+	return teams, nil
+}
+
+// AddMembers adds users to an existing team or updates their profile and role in it.
+// Members keep their other teams, so no reviews move.
+func (s *Service) AddMembers(ctx context.Context, name string, members []model.TeamMember) (*model.Team, error) {
+	log := s.log.With("op", "AddMembers", "team_name", name)
+
+	if len(members) == 0 {
+		return nil, ErrBadMembers
+	}
+	for i := range members {
+		if members[i].UserID == "" || members[i].User.Username == "" {
+			return nil, ErrBadMembers
+		}
+	}
+	if err := prepareMembers(members); err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddMembers(ctx, name, members); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTeamNotFound
+		}
+		log.ErrorContext(ctx, "failed to add members", "error", err)
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "team members added", "members_count", len(members))
+	return s.GetByName(ctx, name)
+}
+
+// prepareMembers defaults and checks membership roles and normalizes tags.
+func prepareMembers(members []model.TeamMember) error {
+	for i := range members {
+		if members[i].Role == "" {
+			members[i].Role = model.RoleMember
+		}
+		if !model.ValidRole(members[i].Role) {
+			return ErrBadRole
+		}
+		members[i].User.Tags = model.NormalizeTags(members[i].User.Tags)
+	}
+	return nil
+}
+
+// RemoveMember takes the user out of the team and reassigns their open reviews within it.
+func (s *Service) RemoveMember(ctx context.Context, name, userID string) (Reassignment, error) {
+	log := s.log.With("op", "RemoveMember", "team_name", name, "user_id", userID)
+
+	result, err := s.repo.RemoveMember(ctx, name, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.WarnContext(ctx, "failed to remove member: not in team")
+			return Reassignment{}, ErrNoMember
+		}
+		log.ErrorContext(ctx, "failed to remove member", "error", err)
+		return Reassignment{}, err
+	}
+	user.NotifyAssigned(ctx, s.notifier, result.Assignments, reasonRemoved)
+
+	log.InfoContext(ctx, "team member removed", "reassigned_count", result.ReassignedCount)
+	return result, nil
+}
+
+func (s *Service) Rename(ctx context.Context, name, newName string) (*model.Team, error) {
+	log := s.log.With("op", "Rename", "team_name", name, "new_team_name", newName)
+
+	if newName == "" || newName == name {
+		return nil, ErrBadName
+	}
+	if err := s.repo.Rename(ctx, name, newName); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrTeamNotFound
+		case errors.Is(err, gorm.ErrDuplicatedKey):
+			log.WarnContext(ctx, "failed to rename team: name taken")
+			return nil, ErrTeamExists
+		}
+		log.ErrorContext(ctx, "failed to rename team", "error", err)
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "team renamed")
+	return s.GetByName(ctx, newName)
+}
+
+// Delete removes the team and its memberships. Its open PRs move to the partner team, or to the
+// author's primary or parent team, and their reviews are reassigned through the fallback chain.
+func (s *Service) Delete(ctx context.Context, name string) (Reassignment, error) {
+	log := s.log.With("op", "Delete", "team_name", name)
+
+	result, err := s.repo.Delete(ctx, name, s.globalPool)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Reassignment{}, ErrTeamNotFound
+		}
+		log.ErrorContext(ctx, "failed to delete team", "error", err)
+		return Reassignment{}, err
+	}
+	user.NotifyAssigned(ctx, s.notifier, result.Assignments, reasonTeamDeleted)
+
+	log.InfoContext(ctx, "team deleted", "reassigned_count", result.ReassignedCount, "short_count", len(result.Short))
+	return result, nil
+}
+
+func (s *Service) GetSettings(ctx context.Context, name string) (*model.Team, error) {
+	team, err := s.repo.GetSettings(ctx, name)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			s.log.ErrorContext(ctx, "failed to get team settings", "op", "GetSettings", "team_name", name, "error", err)
+		}
+		return nil, err
+	}
+	return team, nil
+}
+
+func (s *Service) UpdateSettings(ctx context.Context, name string, settings Settings) (*model.Team, error) {
+	log := s.log.With("op", "UpdateSettings", "team_name", name)
+
+	if settings.RequiredReviewers != nil && *settings.RequiredReviewers <= 0 {
+		return nil, ErrBadSettings
+	}
+	if settings.RequiredApprovals != nil && *settings.RequiredApprovals < 0 {
+		return nil, ErrBadSettings
+	}
+	if settings.SLAHours != nil && *settings.SLAHours < 0 {
+		return nil, ErrBadSLA
+	}
+	if p := settings.SLAPolicy; p != nil && *p != model.SLAPolicyFlag && *p != model.SLAPolicyReassign {
+		return nil, ErrBadSLA
+	}
+	if err := s.validateFallback(ctx, name, settings); err != nil {
+		log.WarnContext(ctx, "invalid fallback chain", "error", err)
+		return nil, err
+	}
+	if settings.ParentTeam != nil && *settings.ParentTeam != "" {
+		if err := s.validateParent(ctx, name, *settings.ParentTeam); err != nil {
+			log.WarnContext(ctx, "invalid parent team", "error", err)
+			return nil, err
+		}
+	}
+	team, err := s.repo.UpdateSettings(ctx, name, settings)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.WarnContext(ctx, "failed to update settings: team not found")
+			return nil, ErrTeamNotFound
+		}
+		log.ErrorContext(ctx, "failed to update team settings", "error", err)
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "team settings updated",
+		"required_reviewers", team.RequiredReviewers,
+		"required_approvals", team.RequiredApprovals,
+		"partner_team", team.PartnerTeam,
+		"lead_user_id", team.LeadUserID,
+		"sla_hours", team.SLAHours,
+		"sla_policy", team.SLAPolicy,
+		"parent_team", team.ParentTeam,
+	)
+	return team, nil
+}
+
+// validateFallback checks that the partner team and the team lead exist. Empty values clear them.
+func (s *Service) validateFallback(ctx context.Context, name string, settings Settings) error {
+	if settings.PartnerTeam != nil && *settings.PartnerTeam != "" {
+		if *settings.PartnerTeam == name {
+			return fmt.Errorf("%w: team cannot be its own partner", ErrBadFallback)
+		}
+		if _, err := s.repo.GetSettings(ctx, *settings.PartnerTeam); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: partner team %s not found", ErrBadFallback, *settings.PartnerTeam)
+			}
+			return err
+		}
+	}
+	if settings.LeadUserID != nil && *settings.LeadUserID != "" {
+		exists, err := s.repo.UserExists(ctx, *settings.LeadUserID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: lead %s not found", ErrBadFallback, *settings.LeadUserID)
+		}
+	}
+	return nil
+}
+
+// validateParent checks that parent exists and that placing name under it does not create a cycle,
+// i.e. parent is neither the team itself nor one of its sub-teams.
+func (s *Service) validateParent(ctx context.Context, name, parent string) error {
+	seen := make(map[string]bool)
+	for ancestor := parent; ancestor != "" && !seen[ancestor]; {
+		if ancestor == name {
+			return fmt.Errorf("%w: %s is %s itself or one of its sub-teams", ErrBadParent, parent, name)
+		}
+		seen[ancestor] = true
+		team, err := s.repo.GetSettings(ctx, ancestor)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: team %s not found", ErrBadParent, ancestor)
+			}
+			return err
+		}
+		ancestor = team.ParentTeam
+	}
+	return nil
+}
+
+func (s *Service) AddHoliday(ctx context.Context, holiday model.TeamHoliday) (*model.TeamHoliday, error) {
+	log := s.log.With("op", "AddHoliday", "team_name", holiday.TeamName, "day", holiday.Day)
+
+	if _, err := time.Parse(time.DateOnly, holiday.Day); err != nil {
+		return nil, ErrBadHoliday
+	}
+	if _, err := s.repo.GetSettings(ctx, holiday.TeamName); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTeamNotFound
+		}
+		log.ErrorContext(ctx, "failed to get team", "error", err)
+		return nil, err
+	}
+	if err := s.repo.SaveHoliday(ctx, &holiday); err != nil {
+		log.ErrorContext(ctx, "failed to save holiday", "error", err)
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "team holiday saved")
+	return &holiday, nil
+}
+
+func (s *Service) ListHolidays(ctx context.Context, teamName string) ([]model.TeamHoliday, error) {
+	if _, err := s.repo.GetSettings(ctx, teamName); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTeamNotFound
+		}
+		s.log.ErrorContext(ctx, "failed to get team", "op", "ListHolidays", "team_name", teamName, "error", err)
+		return nil, err
+	}
+	holidays, err := s.repo.ListHolidays(ctx, teamName)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to list holidays", "op", "ListHolidays", "team_name", teamName, "error", err)
+		return nil, err
+	}
+	return holidays, nil
+}
+
+func (s *Service) DeleteHoliday(ctx context.Context, teamName string, day string) error {
+	if err := s.repo.DeleteHoliday(ctx, teamName, day); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNoHoliday
+		}
+		s.log.ErrorContext(ctx, "failed to delete holiday", "op", "DeleteHoliday", "team_name", teamName, "day", day, "error", err)
+		return err
+	}
+	s.log.InfoContext(ctx, "team holiday deleted", "op", "DeleteHoliday", "team_name", teamName, "day", day)
+	return nil
+}
@@ -7,7 +7,10 @@ import (
 	"log/slog"
 	"testing"
 
+	"github.com/SeeXWH/pr-reviewer-service/configs"
 	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
+	"github.com/SeeXWH/pr-reviewer-service/internal/user"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -32,12 +35,101 @@ func (m *MockStorer) GetByName(ctx context.Context, name string) (*model.Team, e
 	return args.Get(0).(*model.Team), args.Error(1)
 }
 
+func (m *MockStorer) GetSettings(ctx context.Context, name string) (*model.Team, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Team), args.Error(1)
+}
+
+func (m *MockStorer) GetSubTeams(ctx context.Context, parent string) ([]model.Team, error) {
+	args := m.Called(ctx, parent)
+	if val, ok := args.Get(0).([]model.Team); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStorer) GetDescendants(ctx context.Context, name string) ([]model.Team, error) {
+	args := m.Called(ctx, name)
+	if val, ok := args.Get(0).([]model.Team); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStorer) AddMembers(ctx context.Context, name string, members []model.TeamMember) error {
+	args := m.Called(ctx, name, members)
+	return args.Error(0)
+}
+
+func (m *MockStorer) RemoveMember(ctx context.Context, name, userID string) (Reassignment, error) {
+	args := m.Called(ctx, name, userID)
+	return args.Get(0).(Reassignment), args.Error(1)
+}
+
+func (m *MockStorer) Rename(ctx context.Context, name, newName string) error {
+	args := m.Called(ctx, name, newName)
+	return args.Error(0)
+}
+
+func (m *MockStorer) Delete(ctx context.Context, name string, globalPool []string) (Reassignment, error) {
+	args := m.Called(ctx, name, globalPool)
+	return args.Get(0).(Reassignment), args.Error(1)
+}
+
+func (m *MockStorer) UpdateSettings(ctx context.Context, name string, settings Settings) (*model.Team, error) {
+	args := m.Called(ctx, name, settings)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Team), args.Error(1)
+}
+
+func (m *MockStorer) UserExists(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStorer) SaveHoliday(ctx context.Context, holiday *model.TeamHoliday) error {
+	args := m.Called(ctx, holiday)
+	return args.Error(0)
+}
+
+func (m *MockStorer) ListHolidays(ctx context.Context, teamName string) ([]model.TeamHoliday, error) {
+	args := m.Called(ctx, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.TeamHoliday), args.Error(1)
+}
+
+func (m *MockStorer) DeleteHoliday(ctx context.Context, teamName string, day string) error {
+	args := m.Called(ctx, teamName, day)
+	return args.Error(0)
+}
+
+type recordingQueue struct {
+	sent []notify.Notification
+}
+
+func (q *recordingQueue) Notify(_ context.Context, n notify.Notification) {
+	q.sent = append(q.sent, n)
+}
+
 func setupService() (*Service, *MockStorer) {
+	svc, mockRepo, _ := setupServiceWithQueue()
+	return svc, mockRepo
+}
+
+func setupServiceWithQueue() (*Service, *MockStorer, *recordingQueue) {
 	mockRepo := new(MockStorer)
+	queue := new(recordingQueue)
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
-	svc := NewService(mockRepo, logger)
-	return svc, mockRepo
+	svc := NewService(mockRepo, queue, configs.Reviewers{GlobalPool: []string{"pool-1"}}, logger)
+	return svc, mockRepo, queue
 }
 
 func TestService_Create(t *testing.T) {
@@ -53,9 +145,32 @@ func TestService_Create(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, "Backend", result.Name)
+		assert.Equal(t, model.DefaultRequiredReviewers, result.RequiredReviewers)
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("parent team not found", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		inputTeam := &model.Team{Name: "Backend", ParentTeam: "Ghost"}
+
+		mockRepo.On("GetSettings", ctx, "Ghost").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.Create(ctx, inputTeam)
+
+		require.ErrorIs(t, err, ErrBadParent)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("negative required reviewers", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		inputTeam := &model.Team{Name: "Backend", RequiredReviewers: -1}
+
+		_, err := svc.Create(ctx, inputTeam)
+
+		require.ErrorIs(t, err, ErrBadSettings)
+		mockRepo.AssertNotCalled(t, "Create", ctx, inputTeam)
+	})
+
 	t.Run("team already exists", func(t *testing.T) {
 		svc, mockRepo := setupService()
 		inputTeam := &model.Team{Name: "Backend"}
@@ -81,6 +196,24 @@ func TestService_Create(t *testing.T) {
 	})
 }
 
+func TestSettingsRow(t *testing.T) {
+	one := 1
+	team := &model.Team{
+		Name:              "billing",
+		RequiredReviewers: 3,
+		RequiredApprovals: &one,
+		ParentTeam:        "payments",
+		Members:           []model.TeamMember{{UserID: "u1"}},
+	}
+
+	assert.Equal(t, model.Team{
+		Name:              "billing",
+		RequiredReviewers: 3,
+		RequiredApprovals: &one,
+		ParentTeam:        "payments",
+	}, settingsRow(team))
+}
+
 func TestService_GetByName(t *testing.T) {
 	ctx := context.Background()
 
@@ -121,3 +254,384 @@ func TestService_GetByName(t *testing.T) {
 		assert.ErrorIs(t, err, unexpectedErr)
 	})
 }
+
+func TestService_GetWithSubTeams(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("builds the tree", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		mockRepo.On("GetByName", ctx, "Finance").Return(&model.Team{Name: "Finance"}, nil)
+		mockRepo.On("GetDescendants", ctx, "Finance").Return([]model.Team{
+			{Name: "Billing", ParentTeam: "Payments"},
+			{Name: "Payments", ParentTeam: "Finance"},
+			{Name: "Tax", ParentTeam: "Finance"},
+		}, nil)
+
+		team, err := svc.GetWithSubTeams(ctx, "Finance")
+
+		require.NoError(t, err)
+		require.Len(t, team.SubTeams, 2)
+		assert.Equal(t, "Payments", team.SubTeams[0].Name)
+		assert.Equal(t, "Tax", team.SubTeams[1].Name)
+		require.Len(t, team.SubTeams[0].SubTeams, 1)
+		assert.Equal(t, "Billing", team.SubTeams[0].SubTeams[0].Name)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		mockRepo.On("GetByName", ctx, "Ghost").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.GetWithSubTeams(ctx, "Ghost")
+
+		require.ErrorIs(t, err, ErrTeamNotFound)
+		mockRepo.AssertNotCalled(t, "GetDescendants", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_AddMembers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("normalizes tags and defaults the role", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		members := []model.TeamMember{{UserID: "u2", User: model.User{ID: "u2", Username: "Bob", Tags: []string{"Infra"}}}}
+		expected := &model.Team{Name: "platform", Members: members}
+
+		mockRepo.On("AddMembers", ctx, "platform", mock.MatchedBy(func(m []model.TeamMember) bool {
+			return len(m) == 1 && m[0].Role == model.RoleMember &&
+				assert.ObjectsAreEqual([]string{"infra"}, m[0].User.Tags)
+		})).Return(nil)
+		mockRepo.On("GetByName", ctx, "platform").Return(expected, nil)
+
+		result, err := svc.AddMembers(ctx, "platform", members)
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("member without username", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		_, err := svc.AddMembers(ctx, "platform", []model.TeamMember{{UserID: "u2"}})
+
+		require.ErrorIs(t, err, ErrBadMembers)
+		mockRepo.AssertNotCalled(t, "AddMembers", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		members := []model.TeamMember{{UserID: "u2", Role: "owner", User: model.User{ID: "u2", Username: "Bob"}}}
+
+		_, err := svc.AddMembers(ctx, "platform", members)
+
+		require.ErrorIs(t, err, ErrBadRole)
+		mockRepo.AssertNotCalled(t, "AddMembers", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		mockRepo.On("AddMembers", ctx, "ghost", mock.Anything).Return(gorm.ErrRecordNotFound)
+
+		_, err := svc.AddMembers(ctx, "ghost", []model.TeamMember{{UserID: "u2", User: model.User{ID: "u2", Username: "Bob"}}})
+
+		require.ErrorIs(t, err, ErrTeamNotFound)
+	})
+}
+
+func TestService_RemoveMember(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		svc, mockRepo, queue := setupServiceWithQueue()
+
+		mockRepo.On("RemoveMember", ctx, "backend", "u2").Return(Reassignment{
+			ReassignedCount: 1,
+			Assignments:     []user.Assignment{{PullRequestID: "pr-1", UserID: "u3"}},
+		}, nil)
+
+		result, err := svc.RemoveMember(ctx, "backend", "u2")
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.ReassignedCount)
+		require.Len(t, queue.sent, 1)
+		assert.Equal(t, reasonRemoved, queue.sent[0].Reason)
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		mockRepo.On("RemoveMember", ctx, "backend", "ghost").Return(Reassignment{}, gorm.ErrRecordNotFound)
+
+		_, err := svc.RemoveMember(ctx, "backend", "ghost")
+
+		require.ErrorIs(t, err, ErrNoMember)
+	})
+}
+
+func TestService_Rename(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		expected := &model.Team{Name: "core"}
+
+		mockRepo.On("Rename", ctx, "backend", "core").Return(nil)
+		mockRepo.On("GetByName", ctx, "core").Return(expected, nil)
+
+		result, err := svc.Rename(ctx, "backend", "core")
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("same name", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		_, err := svc.Rename(ctx, "backend", "backend")
+
+		require.ErrorIs(t, err, ErrBadName)
+		mockRepo.AssertNotCalled(t, "Rename", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("name taken", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		mockRepo.On("Rename", ctx, "backend", "platform").Return(gorm.ErrDuplicatedKey)
+
+		_, err := svc.Rename(ctx, "backend", "platform")
+
+		require.ErrorIs(t, err, ErrTeamExists)
+	})
+}
+
+func TestService_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		svc, mockRepo, queue := setupServiceWithQueue()
+
+		mockRepo.On("Delete", ctx, "backend", []string{"pool-1"}).Return(Reassignment{
+			ReassignedCount: 2,
+			Assignments: []user.Assignment{
+				{PullRequestID: "pr-1", UserID: "p1"},
+				{PullRequestID: "pr-2", UserID: "p1"},
+			},
+		}, nil)
+
+		result, err := svc.Delete(ctx, "backend")
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.ReassignedCount)
+		assert.Len(t, queue.sent, 2)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		mockRepo.On("Delete", ctx, "ghost", []string{"pool-1"}).Return(Reassignment{}, gorm.ErrRecordNotFound)
+
+		_, err := svc.Delete(ctx, "ghost")
+
+		require.ErrorIs(t, err, ErrTeamNotFound)
+	})
+}
+
+func TestService_UpdateSettings(t *testing.T) {
+	ctx := context.Background()
+	three := 3
+
+	t.Run("success", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		settings := Settings{RequiredReviewers: &three}
+		updated := &model.Team{Name: "Security", RequiredReviewers: 3}
+
+		mockRepo.On("UpdateSettings", ctx, "Security", settings).Return(updated, nil)
+
+		result, err := svc.UpdateSettings(ctx, "Security", settings)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.RequiredReviewers)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("non-positive required reviewers", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		zero := 0
+
+		_, err := svc.UpdateSettings(ctx, "Security", Settings{RequiredReviewers: &zero})
+
+		require.ErrorIs(t, err, ErrBadSettings)
+		mockRepo.AssertNotCalled(t, "UpdateSettings", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("negative required approvals", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		negative := -1
+
+		_, err := svc.UpdateSettings(ctx, "Security", Settings{RequiredApprovals: &negative})
+
+		require.ErrorIs(t, err, ErrBadSettings)
+		mockRepo.AssertNotCalled(t, "UpdateSettings", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid sla", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		negative, unknown := -1, "escalate"
+
+		_, err := svc.UpdateSettings(ctx, "Security", Settings{SLAHours: &negative})
+		require.ErrorIs(t, err, ErrBadSLA)
+
+		_, err = svc.UpdateSettings(ctx, "Security", Settings{SLAPolicy: &unknown})
+		require.ErrorIs(t, err, ErrBadSLA)
+
+		mockRepo.AssertNotCalled(t, "UpdateSettings", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("sets fallback chain", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		partner, lead := "Platform", "u9"
+		settings := Settings{PartnerTeam: &partner, LeadUserID: &lead}
+		updated := &model.Team{Name: "Security", PartnerTeam: partner, LeadUserID: lead}
+
+		mockRepo.On("GetSettings", ctx, "Platform").Return(&model.Team{Name: "Platform"}, nil)
+		mockRepo.On("UserExists", ctx, "u9").Return(true, nil)
+		mockRepo.On("UpdateSettings", ctx, "Security", settings).Return(updated, nil)
+
+		result, err := svc.UpdateSettings(ctx, "Security", settings)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Platform", result.PartnerTeam)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("empty values clear the chain without lookups", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		empty := ""
+		settings := Settings{PartnerTeam: &empty, LeadUserID: &empty}
+
+		mockRepo.On("UpdateSettings", ctx, "Security", settings).Return(&model.Team{Name: "Security"}, nil)
+
+		_, err := svc.UpdateSettings(ctx, "Security", settings)
+
+		require.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "UserExists", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid fallback chain", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		self, ghostTeam, ghostLead := "Security", "Ghost", "nobody"
+
+		mockRepo.On("GetSettings", ctx, "Ghost").Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.On("UserExists", ctx, "nobody").Return(false, nil)
+
+		_, err := svc.UpdateSettings(ctx, "Security", Settings{PartnerTeam: &self})
+		require.ErrorIs(t, err, ErrBadFallback)
+
+		_, err = svc.UpdateSettings(ctx, "Security", Settings{PartnerTeam: &ghostTeam})
+		require.ErrorIs(t, err, ErrBadFallback)
+
+		_, err = svc.UpdateSettings(ctx, "Security", Settings{LeadUserID: &ghostLead})
+		require.ErrorIs(t, err, ErrBadFallback)
+
+		mockRepo.AssertNotCalled(t, "UpdateSettings", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("sets parent team", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		parent := "Payments"
+		settings := Settings{ParentTeam: &parent}
+
+		mockRepo.On("GetSettings", ctx, "Payments").Return(&model.Team{Name: "Payments", ParentTeam: "Finance"}, nil)
+		mockRepo.On("GetSettings", ctx, "Finance").Return(&model.Team{Name: "Finance"}, nil)
+		mockRepo.On("UpdateSettings", ctx, "Security", settings).
+			Return(&model.Team{Name: "Security", ParentTeam: parent}, nil)
+
+		result, err := svc.UpdateSettings(ctx, "Security", settings)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Payments", result.ParentTeam)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid parent team", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		self, child, ghost := "Finance", "Payments", "Ghost"
+
+		mockRepo.On("GetSettings", ctx, "Payments").Return(&model.Team{Name: "Payments", ParentTeam: "Finance"}, nil)
+		mockRepo.On("GetSettings", ctx, "Ghost").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.UpdateSettings(ctx, "Finance", Settings{ParentTeam: &self})
+		require.ErrorIs(t, err, ErrBadParent)
+
+		_, err = svc.UpdateSettings(ctx, "Finance", Settings{ParentTeam: &child})
+		require.ErrorIs(t, err, ErrBadParent)
+
+		_, err = svc.UpdateSettings(ctx, "Finance", Settings{ParentTeam: &ghost})
+		require.ErrorIs(t, err, ErrBadParent)
+
+		mockRepo.AssertNotCalled(t, "UpdateSettings", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		settings := Settings{RequiredReviewers: &three}
+
+		mockRepo.On("UpdateSettings", ctx, "Ghost", settings).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.UpdateSettings(ctx, "Ghost", settings)
+
+		require.ErrorIs(t, err, ErrTeamNotFound)
+	})
+}
+
+func TestService_AddHoliday(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		holiday := model.TeamHoliday{TeamName: "Backend", Day: "2025-01-07", Name: "Christmas"}
+
+		mockRepo.On("GetSettings", ctx, "Backend").Return(&model.Team{Name: "Backend"}, nil)
+		mockRepo.On("SaveHoliday", ctx, &holiday).Return(nil)
+
+		res, err := svc.AddHoliday(ctx, holiday)
+
+		require.NoError(t, err)
+		assert.Equal(t, "2025-01-07", res.Day)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("bad day", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		_, err := svc.AddHoliday(ctx, model.TeamHoliday{TeamName: "Backend", Day: "07.01.2025"})
+
+		assert.ErrorIs(t, err, ErrBadHoliday)
+		mockRepo.AssertNotCalled(t, "SaveHoliday", mock.Anything, mock.Anything)
+	})
+
+	t.Run("team not found", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		mockRepo.On("GetSettings", ctx, "Ghost").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.AddHoliday(ctx, model.TeamHoliday{TeamName: "Ghost", Day: "2025-01-07"})
+
+		assert.ErrorIs(t, err, ErrTeamNotFound)
+	})
+}
+
+func TestService_DeleteHoliday(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		mockRepo.On("DeleteHoliday", ctx, "Backend", "2025-01-07").Return(gorm.ErrRecordNotFound)
+
+		assert.ErrorIs(t, svc.DeleteHoliday(ctx, "Backend", "2025-01-07"), ErrNoHoliday)
+	})
+}
@@ -0,0 +1,28 @@
+package teamsync
+
+type SyncRequestDTO struct {
+	TeamsYAML string `json:"teams_yaml"`
+}
+
+type ChangeDTO struct {
+	Action   string `json:"action"`
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+	Role     string `json:"role,omitempty"`
+}
+
+type PlanResponseDTO struct {
+	NewTeams []string    `json:"new_teams"`
+	Changes  []ChangeDTO `json:"changes"`
+}
+
+type AssignmentDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+type ApplyResponseDTO struct {
+	PlanResponseDTO
+	ReassignedCount int             `json:"reassigned_count"`
+	Assignments     []AssignmentDTO `json:"assignments"`
+}
@@ -0,0 +1,5 @@
+package teamsync
+
+import "errors"
+
+var ErrBadSpec = errors.New("invalid teams file")
@@ -0,0 +1,75 @@
+package teamsync
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/req"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/res"
+)
+
+type Handler struct {
+	syncService Provider
+	conf        *configs.Config
+}
+
+func NewHandler(router *http.ServeMux, syncService Provider, conf *configs.Config) {
+	handler := &Handler{
+		syncService: syncService,
+		conf:        conf,
+	}
+	router.HandleFunc("POST /team/syncPlan", handler.Plan())
+	router.HandleFunc("POST /team/syncApply", handler.Apply())
+}
+
+func (h *Handler) Plan() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		reqBody, err := req.HandleBody[SyncRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+
+		plan, err := h.syncService.Plan(ctx, []byte(reqBody.TeamsYAML))
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrBadSpec):
+				res.Error(w, http.StatusBadRequest, "BAD_TEAMS_FILE", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		res.JSON(w, http.StatusOK, ToPlanResponse(plan))
+	}
+}
+
+func (h *Handler) Apply() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		reqBody, err := req.HandleBody[SyncRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+
+		result, err := h.syncService.Apply(ctx, []byte(reqBody.TeamsYAML))
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrBadSpec):
+				res.Error(w, http.StatusBadRequest, "BAD_TEAMS_FILE", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		res.JSON(w, http.StatusOK, ToApplyResponse(result))
+	}
+}
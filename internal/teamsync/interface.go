@@ -0,0 +1,31 @@
+package teamsync
+
+import (
+	"context"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
+	"github.com/SeeXWH/pr-reviewer-service/internal/user"
+
+	"gorm.io/gorm"
+)
+
+type Storer interface {
+	Plan(context.Context, Spec) (Plan, error)
+	Apply(context.Context, Spec) (Result, error)
+}
+
+// Reassigner hands the open reviews of removed members to their former teammates and those of
+// deactivated users to the teams of the PRs they review.
+type Reassigner interface {
+	ReassignReviewsTx(tx *gorm.DB, teamName string, userIDs []string, reason string) ([]user.Assignment, int, error)
+	ReassignAllReviewsTx(tx *gorm.DB, fallbackTeam string, userIDs []string, reason string) ([]user.Assignment, int, error)
+}
+
+type NotificationQueue interface {
+	Notify(context.Context, notify.Notification)
+}
+
+type Provider interface {
+	Plan(ctx context.Context, data []byte) (Plan, error)
+	Apply(ctx context.Context, data []byte) (Result, error)
+}
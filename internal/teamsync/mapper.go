@@ -0,0 +1,32 @@
+package teamsync
+
+func ToPlanResponse(plan Plan) PlanResponseDTO {
+	resp := PlanResponseDTO{
+		NewTeams: plan.NewTeams,
+		Changes:  make([]ChangeDTO, len(plan.Changes)),
+	}
+	if resp.NewTeams == nil {
+		resp.NewTeams = []string{}
+	}
+	for i, c := range plan.Changes {
+		resp.Changes[i] = ChangeDTO{
+			Action:   c.Action,
+			UserID:   c.UserID,
+			TeamName: c.TeamName,
+			Role:     c.Role,
+		}
+	}
+	return resp
+}
+
+func ToApplyResponse(result Result) ApplyResponseDTO {
+	resp := ApplyResponseDTO{
+		PlanResponseDTO: ToPlanResponse(result.Plan),
+		ReassignedCount: result.ReassignedCount,
+		Assignments:     make([]AssignmentDTO, len(result.Assignments)),
+	}
+	for i, a := range result.Assignments {
+		resp.Assignments[i] = AssignmentDTO{PullRequestID: a.PullRequestID, UserID: a.UserID}
+	}
+	return resp
+}
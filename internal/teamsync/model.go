@@ -0,0 +1,90 @@
+package teamsync
+
+import (
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/user"
+)
+
+// Add creates a user together with their first membership; join and remove add and end
+// memberships, role changes a member's role or makes the team their primary one.
+const (
+	ActionAdd        = "add"
+	ActionJoin       = "join"
+	ActionRole       = "role"
+	ActionRemove     = "remove"
+	ActionActivate   = "activate"
+	ActionDeactivate = "deactivate"
+	ActionUpdate     = "update"
+)
+
+// Details recorded on UNASSIGNED events for reviews taken away by a sync.
+const (
+	reasonRemoved     = "removed from team"
+	reasonDeactivated = "reviewer deactivated"
+	reasonSynced      = "team sync"
+)
+
+// Spec is the desired membership read from teams.yaml. Teams missing from the file are left alone.
+type Spec struct {
+	Teams []TeamSpec `yaml:"teams"`
+}
+
+type TeamSpec struct {
+	Name    string       `yaml:"name"`
+	Members []MemberSpec `yaml:"members"`
+}
+
+// MemberSpec lists a user in one team. A user may be listed in several teams; the profile
+// (username, active, email, tags) is taken from the first listing and later ones may only repeat it.
+type MemberSpec struct {
+	UserID   string `yaml:"user_id"`
+	Username string `yaml:"username"`
+	// Active defaults to true.
+	Active *bool `yaml:"active"`
+	// Email and Tags are only changed when present.
+	Email string   `yaml:"email"`
+	Tags  []string `yaml:"tags"`
+	// Role defaults to member. Primary makes this team the user's primary one.
+	Role    string `yaml:"role"`
+	Primary bool   `yaml:"primary"`
+}
+
+func (m MemberSpec) active() bool {
+	return m.Active == nil || *m.Active
+}
+
+func (m MemberSpec) role() string {
+	if m.Role == "" {
+		return model.RoleMember
+	}
+	return m.Role
+}
+
+// Change is one step of a plan. Role is set for add, join and role changes.
+type Change struct {
+	Action   string
+	UserID   string
+	TeamName string
+	Role     string
+	member   MemberSpec
+}
+
+type Plan struct {
+	NewTeams []string
+	Changes  []Change
+}
+
+type Result struct {
+	Plan            Plan
+	ReassignedCount int
+	Assignments     []user.Assignment
+}
+
+// state is the part of the database a plan is computed against: which of the listed
+// teams exist, every user that is listed or belongs to a listed team, and their
+// memberships keyed by user ID and team name.
+type state struct {
+	teams       map[string]bool
+	users       map[string]model.User
+	memberships map[string]map[string]model.TeamMember
+}
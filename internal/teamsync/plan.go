@@ -0,0 +1,89 @@
+package teamsync
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+// buildPlan diffs spec against st. Memberships in a listed team whose user is missing from
+// that team in the file are removed; the user keeps their other teams.
+func buildPlan(spec Spec, st state) Plan {
+	var plan Plan
+	listed := make(map[string]map[string]bool, len(spec.Teams))
+	seen := make(map[string]bool)
+	for _, t := range spec.Teams {
+		if !st.teams[t.Name] {
+			plan.NewTeams = append(plan.NewTeams, t.Name)
+		}
+		listed[t.Name] = make(map[string]bool, len(t.Members))
+		for _, m := range t.Members {
+			listed[t.Name][m.UserID] = true
+			if seen[m.UserID] {
+				plan.Changes = append(plan.Changes, membershipChanges(t.Name, m, st.memberships[m.UserID])...)
+				continue
+			}
+			seen[m.UserID] = true
+			u, ok := st.users[m.UserID]
+			if !ok {
+				plan.Changes = append(plan.Changes, Change{Action: ActionAdd, UserID: m.UserID, TeamName: t.Name, Role: m.role(), member: m})
+				continue
+			}
+			plan.Changes = append(plan.Changes, membershipChanges(t.Name, m, st.memberships[m.UserID])...)
+			plan.Changes = append(plan.Changes, userChanges(t.Name, m, u)...)
+		}
+	}
+
+	var removed []Change
+	for userID, teams := range st.memberships {
+		for teamName := range teams {
+			if members, managed := listed[teamName]; managed && !members[userID] {
+				removed = append(removed, Change{Action: ActionRemove, UserID: userID, TeamName: teamName})
+			}
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		if removed[i].UserID != removed[j].UserID {
+			return removed[i].UserID < removed[j].UserID
+		}
+		return removed[i].TeamName < removed[j].TeamName
+	})
+	plan.Changes = append(plan.Changes, removed...)
+	return plan
+}
+
+func membershipChanges(teamName string, m MemberSpec, memberships map[string]model.TeamMember) []Change {
+	tm, ok := memberships[teamName]
+	switch {
+	case !ok:
+		return []Change{{Action: ActionJoin, UserID: m.UserID, TeamName: teamName, Role: m.role(), member: m}}
+	case tm.Role != m.role() || m.Primary && !tm.IsPrimary:
+		return []Change{{Action: ActionRole, UserID: m.UserID, TeamName: teamName, Role: m.role(), member: m}}
+	}
+	return nil
+}
+
+func userChanges(teamName string, m MemberSpec, u model.User) []Change {
+	var changes []Change
+	switch {
+	case m.active() && !u.IsActive:
+		changes = append(changes, Change{Action: ActionActivate, UserID: m.UserID, TeamName: teamName, member: m})
+	case !m.active() && u.IsActive:
+		changes = append(changes, Change{Action: ActionDeactivate, UserID: m.UserID, TeamName: teamName, member: m})
+	}
+	if profileChanged(u, m) {
+		changes = append(changes, Change{Action: ActionUpdate, UserID: m.UserID, TeamName: teamName, member: m})
+	}
+	return changes
+}
+
+func profileChanged(u model.User, m MemberSpec) bool {
+	if u.Username != m.Username {
+		return true
+	}
+	if m.Email != "" && m.Email != u.Email {
+		return true
+	}
+	return m.Tags != nil && !slices.Equal(model.NormalizeTags(m.Tags), u.Tags)
+}
@@ -0,0 +1,231 @@
+package teamsync
+
+import (
+	"context"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/team"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository struct {
+	db         *db.PostgresDB
+	reassigner Reassigner
+}
+
+func NewRepository(db *db.PostgresDB, reassigner Reassigner) *Repository {
+	return &Repository{
+		db:         db,
+		reassigner: reassigner,
+	}
+}
+
+func (r *Repository) Plan(ctx context.Context, spec Spec) (Plan, error) {
+	st, err := loadState(r.db.PostgresDB.WithContext(ctx), spec, false)
+	if err != nil {
+		return Plan{}, err
+	}
+	return buildPlan(spec, st), nil
+}
+
+// Apply computes the plan against locked rows and carries it out in one transaction.
+// Members are added before anyone leaves so they can take over the open reviews.
+func (r *Repository) Apply(ctx context.Context, spec Spec) (Result, error) {
+	var result Result
+
+	err := r.db.PostgresDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		st, err := loadState(tx, spec, true)
+		if err != nil {
+			return err
+		}
+		result.Plan = buildPlan(spec, st)
+
+		for _, name := range result.Plan.NewTeams {
+			created := model.Team{Name: name, RequiredReviewers: model.DefaultRequiredReviewers}
+			if err = tx.Create(&created).Error; err != nil {
+				return err
+			}
+		}
+
+		var groups []reassignGroup
+		for _, c := range result.Plan.Changes {
+			switch c.Action {
+			case ActionAdd:
+				if err = tx.Create(newUser(c)).Error; err != nil {
+					return err
+				}
+				err = tx.Omit("User").Create(newMembership(c)).Error
+			case ActionJoin:
+				err = tx.Omit("User").Create(newMembership(c)).Error
+			case ActionRole:
+				err = tx.Model(&model.TeamMember{}).
+					Where("team_name = ? AND user_id = ?", c.TeamName, c.UserID).
+					Update("role", c.Role).Error
+			case ActionActivate:
+				err = updateUser(tx, c.UserID, map[string]any{"is_active": true})
+			case ActionDeactivate:
+				err = updateUser(tx, c.UserID, map[string]any{"is_active": false})
+				groups = addToGroup(groups, c.TeamName, reasonDeactivated, c.UserID)
+			case ActionUpdate:
+				err = updateProfile(tx, c.member)
+			case ActionRemove:
+				err = tx.Where("team_name = ? AND user_id = ?", c.TeamName, c.UserID).Delete(&model.TeamMember{}).Error
+				groups = addToGroup(groups, c.TeamName, reasonRemoved, c.UserID)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if err = syncPrimaries(tx, spec, result.Plan); err != nil {
+			return err
+		}
+
+		for _, g := range groups {
+			reassign := r.reassigner.ReassignReviewsTx
+			if g.reason == reasonDeactivated {
+				// Deactivated users stop reviewing everywhere, not only in the team they are listed in.
+				reassign = r.reassigner.ReassignAllReviewsTx
+			}
+			assignments, count, err := reassign(tx, g.teamName, g.userIDs, g.reason)
+			if err != nil {
+				return err
+			}
+			result.ReassignedCount += count
+			result.Assignments = append(result.Assignments, assignments...)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// reassignGroup collects the users whose reviews go back to one team for the same reason.
+type reassignGroup struct {
+	teamName string
+	reason   string
+	userIDs  []string
+}
+
+func addToGroup(groups []reassignGroup, teamName, reason, userID string) []reassignGroup {
+	for i := range groups {
+		if groups[i].teamName == teamName && groups[i].reason == reason {
+			groups[i].userIDs = append(groups[i].userIDs, userID)
+			return groups
+		}
+	}
+	return append(groups, reassignGroup{teamName: teamName, reason: reason, userIDs: []string{userID}})
+}
+
+func loadState(tx *gorm.DB, spec Spec, lock bool) (state, error) {
+	query := func() *gorm.DB {
+		if lock {
+			return tx.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		return tx
+	}
+	st := state{
+		teams:       make(map[string]bool),
+		users:       make(map[string]model.User),
+		memberships: make(map[string]map[string]model.TeamMember),
+	}
+
+	var teams []model.Team
+	if err := query().Where("team_name IN ?", spec.teamNames()).Find(&teams).Error; err != nil {
+		return state{}, err
+	}
+	for _, t := range teams {
+		st.teams[t.Name] = true
+	}
+
+	var memberIDs []string
+	err := query().Model(&model.TeamMember{}).Where("team_name IN ?", spec.teamNames()).Pluck("user_id", &memberIDs).Error
+	if err != nil {
+		return state{}, err
+	}
+	ids := append(spec.userIDs(), memberIDs...)
+	if len(ids) == 0 {
+		return st, nil
+	}
+
+	var users []model.User
+	if err = query().Where("user_id IN ?", ids).Find(&users).Error; err != nil {
+		return state{}, err
+	}
+	for _, u := range users {
+		st.users[u.ID] = u
+	}
+	var memberships []model.TeamMember
+	if err = query().Where("user_id IN ?", ids).Find(&memberships).Error; err != nil {
+		return state{}, err
+	}
+	for _, m := range memberships {
+		if st.memberships[m.UserID] == nil {
+			st.memberships[m.UserID] = make(map[string]model.TeamMember)
+		}
+		st.memberships[m.UserID][m.TeamName] = m
+	}
+	return st, nil
+}
+
+func newUser(c Change) *model.User {
+	return &model.User{
+		ID:       c.UserID,
+		Username: c.member.Username,
+		IsActive: c.member.active(),
+		Email:    c.member.Email,
+		Tags:     model.NormalizeTags(c.member.Tags),
+	}
+}
+
+func newMembership(c Change) *model.TeamMember {
+	return &model.TeamMember{
+		TeamName: c.TeamName,
+		UserID:   c.UserID,
+		Role:     c.Role,
+	}
+}
+
+// syncPrimaries applies the primary flags from the file and gives every user touched by the plan
+// who is left without a primary team their alphabetically first one.
+func syncPrimaries(tx *gorm.DB, spec Spec, plan Plan) error {
+	for _, t := range spec.Teams {
+		for _, m := range t.Members {
+			if !m.Primary {
+				continue
+			}
+			err := tx.Model(&model.TeamMember{}).
+				Where("user_id = ?", m.UserID).
+				Update("is_primary", gorm.Expr("team_name = ?", t.Name)).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+	var ids []string
+	for _, c := range plan.Changes {
+		ids = append(ids, c.UserID)
+	}
+	return team.EnsurePrimary(tx, ids)
+}
+
+func updateUser(tx *gorm.DB, userID string, values map[string]any) error {
+	return tx.Model(&model.User{}).Where("user_id = ?", userID).Updates(values).Error
+}
+
+// updateProfile goes through the struct so the tags serializer applies.
+func updateProfile(tx *gorm.DB, m MemberSpec) error {
+	columns := []string{"username"}
+	values := model.User{Username: m.Username}
+	if m.Email != "" {
+		columns = append(columns, "email")
+		values.Email = m.Email
+	}
+	if m.Tags != nil {
+		columns = append(columns, "tags")
+		values.Tags = model.NormalizeTags(m.Tags)
+	}
+	return tx.Model(&model.User{}).Where("user_id = ?", m.UserID).Select(columns).Updates(&values).Error
+}
@@ -0,0 +1,60 @@
+package teamsync
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/user"
+)
+
+type Service struct {
+	repo     Storer
+	notifier NotificationQueue
+	log      *slog.Logger
+}
+
+func NewService(repo Storer, notifier NotificationQueue, log *slog.Logger) *Service {
+	return &Service{
+		repo:     repo,
+		notifier: notifier,
+		log:      log.With("component", "teamSyncService"),
+	}
+}
+
+// Plan reports what Apply would change without touching the database.
+func (s *Service) Plan(ctx context.Context, data []byte) (Plan, error) {
+	spec, err := ParseSpec(data)
+	if err != nil {
+		return Plan{}, err
+	}
+	plan, err := s.repo.Plan(ctx, spec)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to build plan", "op", "Plan", "error", err)
+		return Plan{}, err
+	}
+	return plan, nil
+}
+
+// Apply brings the listed teams in line with the file. Open reviews of removed, moved and
+// deactivated members are handed to their former teammates as in mass deactivation.
+func (s *Service) Apply(ctx context.Context, data []byte) (Result, error) {
+	log := s.log.With("op", "Apply")
+
+	spec, err := ParseSpec(data)
+	if err != nil {
+		return Result{}, err
+	}
+	result, err := s.repo.Apply(ctx, spec)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to apply team sync", "error", err)
+		return Result{}, err
+	}
+
+	user.NotifyAssigned(ctx, s.notifier, result.Assignments, reasonSynced)
+	log.InfoContext(ctx, "teams synced",
+		"new_teams", len(result.Plan.NewTeams),
+		"changes_count", len(result.Plan.Changes),
+		"reassigned_count", result.ReassignedCount,
+	)
+	return result, nil
+}
@@ -0,0 +1,203 @@
+package teamsync
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
+	"github.com/SeeXWH/pr-reviewer-service/internal/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockStorer struct {
+	mock.Mock
+}
+
+func (m *MockStorer) Plan(ctx context.Context, spec Spec) (Plan, error) {
+	args := m.Called(ctx, spec)
+	return args.Get(0).(Plan), args.Error(1)
+}
+
+func (m *MockStorer) Apply(ctx context.Context, spec Spec) (Result, error) {
+	args := m.Called(ctx, spec)
+	return args.Get(0).(Result), args.Error(1)
+}
+
+type recordingQueue struct {
+	sent []notify.Notification
+}
+
+func (q *recordingQueue) Notify(_ context.Context, n notify.Notification) {
+	q.sent = append(q.sent, n)
+}
+
+func setupService() (*Service, *MockStorer, *recordingQueue) {
+	mockRepo := new(MockStorer)
+	queue := new(recordingQueue)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewService(mockRepo, queue, logger), mockRepo, queue
+}
+
+const teamsYAML = `
+teams:
+  - name: backend
+    members:
+      - user_id: u1
+        username: Alice
+        email: alice@example.com
+        tags: [Database]
+      - user_id: u2
+        username: Bob
+        active: false
+  - name: frontend
+    members:
+      - user_id: u3
+        username: Carol
+      - user_id: u1
+        username: Alice
+        role: lead
+        primary: true
+`
+
+func TestParseSpec(t *testing.T) {
+	t.Run("reads teams and members", func(t *testing.T) {
+		spec, err := ParseSpec([]byte(teamsYAML))
+
+		require.NoError(t, err)
+		require.Len(t, spec.Teams, 2)
+		assert.Equal(t, "backend", spec.Teams[0].Name)
+		assert.True(t, spec.Teams[0].Members[0].active())
+		assert.False(t, spec.Teams[0].Members[1].active())
+		assert.Equal(t, model.RoleMember, spec.Teams[0].Members[0].role())
+		assert.Equal(t, model.RoleLead, spec.Teams[1].Members[1].role())
+		assert.Equal(t, []string{"u1", "u2", "u3"}, spec.userIDs())
+	})
+
+	cases := map[string]string{
+		"unknown field":  "teams:\n  - name: backend\n    lead: u1\n",
+		"missing name":   "teams:\n  - members: []\n",
+		"duplicate team": "teams:\n  - name: a\n  - name: a\n",
+		"user twice in a team": "teams:\n  - name: a\n" +
+			"    members: [{user_id: u1, username: A}, {user_id: u1, username: A}]\n",
+		"different profiles": "teams:\n  - name: a\n    members: [{user_id: u1, username: A}]\n" +
+			"  - name: b\n    members: [{user_id: u1, username: B}]\n",
+		"two primary teams": "teams:\n  - name: a\n    members: [{user_id: u1, username: A, primary: true}]\n" +
+			"  - name: b\n    members: [{user_id: u1, username: A, primary: true}]\n",
+		"unknown role":     "teams:\n  - name: a\n    members: [{user_id: u1, username: A, role: owner}]\n",
+		"missing username": "teams:\n  - name: a\n    members: [{user_id: u1}]\n",
+	}
+	for name, data := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseSpec([]byte(data))
+
+			require.ErrorIs(t, err, ErrBadSpec)
+		})
+	}
+}
+
+func TestBuildPlan(t *testing.T) {
+	spec, err := ParseSpec([]byte(teamsYAML))
+	require.NoError(t, err)
+
+	st := state{
+		teams: map[string]bool{"backend": true},
+		users: map[string]model.User{
+			"u1": {ID: "u1", Username: "Alice", IsActive: false, Email: "alice@example.com", Tags: []string{"database"}},
+			"u2": {ID: "u2", Username: "Bob", IsActive: true},
+			"u4": {ID: "u4", Username: "Dan", IsActive: true},
+		},
+		memberships: map[string]map[string]model.TeamMember{
+			"u1": {"backend": {TeamName: "backend", UserID: "u1", Role: model.RoleMember, IsPrimary: true}},
+			"u2": {"payments": {TeamName: "payments", UserID: "u2", Role: model.RoleMember, IsPrimary: true}},
+			"u4": {
+				"backend":  {TeamName: "backend", UserID: "u4", Role: model.RoleMember},
+				"payments": {TeamName: "payments", UserID: "u4", Role: model.RoleLead, IsPrimary: true},
+			},
+		},
+	}
+
+	plan := buildPlan(spec, st)
+
+	assert.Equal(t, []string{"frontend"}, plan.NewTeams)
+	var got []Change
+	for _, c := range plan.Changes {
+		got = append(got, Change{Action: c.Action, UserID: c.UserID, TeamName: c.TeamName, Role: c.Role})
+	}
+	assert.Equal(t, []Change{
+		{Action: ActionActivate, UserID: "u1", TeamName: "backend"},
+		{Action: ActionJoin, UserID: "u2", TeamName: "backend", Role: model.RoleMember},
+		{Action: ActionDeactivate, UserID: "u2", TeamName: "backend"},
+		{Action: ActionAdd, UserID: "u3", TeamName: "frontend", Role: model.RoleMember},
+		{Action: ActionJoin, UserID: "u1", TeamName: "frontend", Role: model.RoleLead},
+		{Action: ActionRemove, UserID: "u4", TeamName: "backend"},
+	}, got)
+}
+
+func TestBuildPlan_ProfileChanges(t *testing.T) {
+	spec := Spec{Teams: []TeamSpec{{Name: "backend", Members: []MemberSpec{
+		{UserID: "u1", Username: "Alice", Tags: []string{"Infra"}},
+		{UserID: "u2", Username: "Bob"},
+		{UserID: "u3", Username: "Carol", Role: model.RoleLead},
+	}}}}
+	st := state{
+		teams: map[string]bool{"backend": true},
+		users: map[string]model.User{
+			"u1": {ID: "u1", Username: "Alice", IsActive: true, Tags: []string{"database"}},
+			"u2": {ID: "u2", Username: "Bob", IsActive: true, Email: "bob@example.com", Tags: []string{"go"}},
+			"u3": {ID: "u3", Username: "Carol", IsActive: true},
+		},
+		memberships: map[string]map[string]model.TeamMember{
+			"u1": {"backend": {TeamName: "backend", UserID: "u1", Role: model.RoleMember, IsPrimary: true}},
+			"u2": {"backend": {TeamName: "backend", UserID: "u2", Role: model.RoleMember, IsPrimary: true}},
+			"u3": {"backend": {TeamName: "backend", UserID: "u3", Role: model.RoleMember, IsPrimary: true}},
+		},
+	}
+
+	plan := buildPlan(spec, st)
+
+	require.Len(t, plan.Changes, 2)
+	assert.Equal(t, ActionUpdate, plan.Changes[0].Action)
+	assert.Equal(t, "u1", plan.Changes[0].UserID)
+	assert.Equal(t, ActionRole, plan.Changes[1].Action)
+	assert.Equal(t, "u3", plan.Changes[1].UserID)
+}
+
+func TestService_Apply(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("notifies new reviewers", func(t *testing.T) {
+		svc, mockRepo, queue := setupService()
+
+		mockRepo.On("Apply", ctx, mock.AnythingOfType("Spec")).Return(Result{
+			ReassignedCount: 2,
+			Assignments: []user.Assignment{
+				{PullRequestID: "pr-1", UserID: "u1"},
+				{PullRequestID: "pr-2", UserID: "u1"},
+			},
+		}, nil)
+
+		result, err := svc.Apply(ctx, []byte(teamsYAML))
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.ReassignedCount)
+		require.Len(t, queue.sent, 2)
+		assert.Equal(t, notify.EventAssigned, queue.sent[0].Event)
+		assert.Equal(t, []string{"u1"}, queue.sent[0].RecipientIDs)
+	})
+
+	t.Run("bad file", func(t *testing.T) {
+		svc, mockRepo, queue := setupService()
+
+		_, err := svc.Apply(ctx, []byte("teams: [{name: ''}]"))
+
+		require.ErrorIs(t, err, ErrBadSpec)
+		mockRepo.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything)
+		assert.Empty(t, queue.sent)
+	})
+}
@@ -0,0 +1,105 @@
+package teamsync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseSpec reads a teams file and checks that team names are unique, that a user is listed
+// at most once per team with a consistent profile, and that roles are known.
+func ParseSpec(data []byte) (Spec, error) {
+	var spec Spec
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&spec); err != nil && !errors.Is(err, io.EOF) {
+		return Spec{}, fmt.Errorf("%w: %v", ErrBadSpec, err)
+	}
+
+	teams := make(map[string]bool, len(spec.Teams))
+	first := make(map[string]MemberSpec)
+	firstTeam := make(map[string]string)
+	primary := make(map[string]string)
+	for _, t := range spec.Teams {
+		if t.Name == "" {
+			return Spec{}, fmt.Errorf("%w: team name is required", ErrBadSpec)
+		}
+		if teams[t.Name] {
+			return Spec{}, fmt.Errorf("%w: team %s is listed twice", ErrBadSpec, t.Name)
+		}
+		teams[t.Name] = true
+		inTeam := make(map[string]bool, len(t.Members))
+		for _, m := range t.Members {
+			if m.UserID == "" || m.Username == "" {
+				return Spec{}, fmt.Errorf("%w: team %s: user_id and username are required", ErrBadSpec, t.Name)
+			}
+			if inTeam[m.UserID] {
+				return Spec{}, fmt.Errorf("%w: user %s is listed twice in %s", ErrBadSpec, m.UserID, t.Name)
+			}
+			inTeam[m.UserID] = true
+			if !model.ValidRole(m.role()) {
+				return Spec{}, fmt.Errorf("%w: user %s in %s: role must be member or lead", ErrBadSpec, m.UserID, t.Name)
+			}
+			if m.Primary {
+				if other, ok := primary[m.UserID]; ok {
+					return Spec{}, fmt.Errorf("%w: user %s is primary in %s and %s", ErrBadSpec, m.UserID, other, t.Name)
+				}
+				primary[m.UserID] = t.Name
+			}
+			prev, ok := first[m.UserID]
+			if !ok {
+				first[m.UserID] = m
+				firstTeam[m.UserID] = t.Name
+				continue
+			}
+			if !sameProfile(prev, m) {
+				return Spec{}, fmt.Errorf("%w: user %s has different profiles in %s and %s",
+					ErrBadSpec, m.UserID, firstTeam[m.UserID], t.Name)
+			}
+		}
+	}
+	return spec, nil
+}
+
+// sameProfile reports whether a later listing repeats the first one or leaves the optional fields out.
+func sameProfile(first, later MemberSpec) bool {
+	if later.Username != first.Username {
+		return false
+	}
+	if later.Active != nil && later.active() != first.active() {
+		return false
+	}
+	if later.Email != "" && later.Email != first.Email {
+		return false
+	}
+	return later.Tags == nil || slices.Equal(model.NormalizeTags(later.Tags), model.NormalizeTags(first.Tags))
+}
+
+func (s Spec) teamNames() []string {
+	names := make([]string, 0, len(s.Teams))
+	for _, t := range s.Teams {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// userIDs lists every listed user once, in order of first appearance.
+func (s Spec) userIDs() []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, t := range s.Teams {
+		for _, m := range t.Members {
+			if !seen[m.UserID] {
+				seen[m.UserID] = true
+				ids = append(ids, m.UserID)
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,58 @@
+package user
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+)
+
+type AwayStorer interface {
+	ReassignAwayReviews(context.Context, time.Time) (AwayReassignResult, error)
+}
+
+// AwayWorker reassigns the open reviews of users as soon as their away period starts.
+type AwayWorker struct {
+	repo AwayStorer
+	conf configs.Away
+	log  *slog.Logger
+}
+
+func NewAwayWorker(repo AwayStorer, conf configs.Away, log *slog.Logger) *AwayWorker {
+	return &AwayWorker{
+		repo: repo,
+		conf: conf,
+		log:  log.With("component", "awayWorker"),
+	}
+}
+
+func (w *AwayWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.conf.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			w.log.ErrorContext(ctx, "away reassignment failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *AwayWorker) RunOnce(ctx context.Context) error {
+	result, err := w.repo.ReassignAwayReviews(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	if result.PeriodCount > 0 {
+		w.log.InfoContext(ctx, "away reviews reassigned",
+			"periods_count", result.PeriodCount,
+			"reassigned_prs_count", result.ReassignedCount,
+		)
+	}
+	return nil
+}
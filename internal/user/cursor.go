@@ -0,0 +1,16 @@
+package user
+
+import "encoding/base64"
+
+// Pages of /users/list are ordered by user_id, so the cursor is the last user_id of a page.
+func encodeCursor(userID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(userID))
+}
+
+func decodeCursor(s string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(raw) == 0 {
+		return "", ErrBadCursor
+	}
+	return string(raw), nil
+}
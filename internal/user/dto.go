@@ -1,19 +1,51 @@
 package user
 
+import "time"
+
 type SetActiveRequestDTO struct {
 	UserID   string `json:"user_id"`
 	IsActive bool   `json:"is_active"`
 }
 
+type WorkingHoursRequestDTO struct {
+	UserID    string `json:"user_id"`
+	Timezone  string `json:"timezone"`
+	WorkStart string `json:"work_start"`
+	WorkEnd   string `json:"work_end"`
+	// WorkDays are "mon".."sun"; empty means Monday to Friday.
+	WorkDays []string `json:"work_days"`
+}
+
+// UpdateRequestDTO changes only the fields present in the body; "tags": [] clears the tags.
+type UpdateRequestDTO struct {
+	UserID   string   `json:"user_id"`
+	Username *string  `json:"username"`
+	Email    *string  `json:"email"`
+	Tags     []string `json:"tags"`
+}
+
 type ResponseWrapper struct {
 	User DTO `json:"user"`
 }
 
 type DTO struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	UserID   string   `json:"user_id"`
+	Username string   `json:"username"`
+	TeamName string   `json:"team_name"`
+	IsActive bool     `json:"is_active"`
+	Email    string   `json:"email,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	// Timezone and the working-hours window are omitted when not configured.
+	Timezone  string `json:"timezone,omitempty"`
+	WorkStart string `json:"work_start,omitempty"`
+	WorkEnd   string `json:"work_end,omitempty"`
+	// WorkDays is omitted when the user keeps the Monday to Friday default.
+	WorkDays []string `json:"work_days,omitempty"`
+}
+
+type ListResponseDTO struct {
+	Users      []DTO  `json:"users"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type PullRequestShortDTO struct {
@@ -27,12 +59,50 @@ type ReviewsResponseDTO struct {
 	UserID       string                `json:"user_id"`
 	PullRequests []PullRequestShortDTO `json:"pull_requests"`
 }
+type AwayPeriodRequestDTO struct {
+	UserID   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason"`
+}
+
+type AwayPeriodDTO struct {
+	ID           uint64     `json:"id"`
+	UserID       string     `json:"user_id"`
+	StartsAt     time.Time  `json:"starts_at"`
+	EndsAt       time.Time  `json:"ends_at"`
+	Reason       string     `json:"reason,omitempty"`
+	ReassignedAt *time.Time `json:"reassigned_at,omitempty"`
+}
+
+type AwayPeriodsResponseDTO struct {
+	UserID  string          `json:"user_id"`
+	Periods []AwayPeriodDTO `json:"periods"`
+}
+
+type DeleteAwayPeriodRequestDTO struct {
+	ID uint64 `json:"id"`
+}
+
 type MassDeactivateRequestDTO struct {
 	TeamName string   `json:"team_name"`
 	UserIDs  []string `json:"user_ids"`
+	DryRun   bool     `json:"dry_run"`
 }
 
 type MassDeactivateResponseDTO struct {
-	DeactivatedCount int `json:"deactivated_count"`
-	ReassignedPRs    int `json:"reassigned_prs_count"`
+	DeactivatedCount int           `json:"deactivated_count"`
+	ReassignedPRs    int           `json:"reassigned_prs_count"`
+	ShortPRs         int           `json:"short_prs_count"`
+	DryRun           bool          `json:"dry_run"`
+	PullRequests     []PRChangeDTO `json:"pull_requests"`
+}
+
+type PRChangeDTO struct {
+	PullRequestID    string   `json:"pull_request_id"`
+	TeamName         string   `json:"team_name"`
+	RemovedReviewers []string `json:"removed_reviewers"`
+	AddedReviewers   []string `json:"added_reviewers"`
+	// MissingReviewers is set when the team had nobody left to fill the PR up to required_reviewers.
+	MissingReviewers int `json:"missing_reviewers,omitempty"`
 }
@@ -3,5 +3,11 @@ package user
 import "errors"
 
 var (
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound    = errors.New("user not found")
+	ErrBadPeriod       = errors.New("ends_at must be after starts_at")
+	ErrPeriodNotFound  = errors.New("away period not found")
+	ErrBadWorkingHours = errors.New("timezone must be an IANA name, work_start/work_end must be HH:MM, set together, and work_days mon..sun")
+	ErrBadProfile      = errors.New("username must not be empty and email must be a valid address")
+	ErrBadFilter       = errors.New("invalid filter")
+	ErrBadCursor       = errors.New("invalid cursor")
 )
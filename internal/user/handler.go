@@ -21,8 +21,15 @@ func NewHandler(router *http.ServeMux, userService Provider, conf *configs.Confi
 		conf:        conf,
 	}
 	router.HandleFunc("POST /users/setIsActive", handler.UpdateStatus())
+	router.HandleFunc("POST /users/setWorkingHours", handler.SetWorkingHours())
+	router.HandleFunc("POST /users/update", handler.Update())
+	router.HandleFunc("GET /users/get", handler.Get())
+	router.HandleFunc("GET /users/list", handler.List())
 	router.HandleFunc("GET /users/getReview", handler.GetReviews())
 	router.HandleFunc("POST /users/massDeactivate", handler.MassDeactivate())
+	router.HandleFunc("POST /users/addAwayPeriod", handler.AddAwayPeriod())
+	router.HandleFunc("GET /users/listAwayPeriods", handler.ListAwayPeriods())
+	router.HandleFunc("POST /users/deleteAwayPeriod", handler.DeleteAwayPeriod())
 }
 
 func (h *Handler) UpdateStatus() http.HandlerFunc {
@@ -58,6 +65,61 @@ func (h *Handler) UpdateStatus() http.HandlerFunc {
 	}
 }
 
+func (h *Handler) Get() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+
+		userID := r.URL.Query().Get("user_id")
+		if userID == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+			return
+		}
+
+		user, err := h.userService.Get(ctx, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrUserNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", "User "+userID+" not found")
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+
+		resp := ToResponse(user)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+
+		filter, err := ToListFilter(r.URL.Query())
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+			return
+		}
+
+		page, err := h.userService.List(ctx, filter)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrBadCursor):
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		resp := ToListResponse(page)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
 func (h *Handler) GetReviews() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
@@ -99,7 +161,7 @@ func (h *Handler) MassDeactivate() http.HandlerFunc {
 			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "team_name and user_ids are required")
 			return
 		}
-		result, err := h.userService.MassDeactivate(ctx, reqBody.TeamName, reqBody.UserIDs)
+		result, err := h.userService.MassDeactivate(ctx, reqBody.TeamName, reqBody.UserIDs, reqBody.DryRun)
 		if err != nil {
 			res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
 			return
@@ -108,3 +170,161 @@ func (h *Handler) MassDeactivate() http.HandlerFunc {
 		res.JSON(w, http.StatusOK, resp)
 	}
 }
+
+func (h *Handler) SetWorkingHours() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		reqBody, err := req.HandleBody[WorkingHoursRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+		if reqBody.UserID == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+			return
+		}
+
+		updatedUser, err := h.userService.SetWorkingHours(ctx, reqBody.UserID, ToWorkingHours(*reqBody))
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrBadWorkingHours):
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+				return
+			case errors.Is(err, ErrUserNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", "User "+reqBody.UserID+" not found")
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+
+		resp := ToResponse(updatedUser)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		reqBody, err := req.HandleBody[UpdateRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+		if reqBody.UserID == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+			return
+		}
+
+		updatedUser, err := h.userService.UpdateProfile(ctx, reqBody.UserID, ToProfileUpdate(*reqBody))
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrBadProfile):
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+				return
+			case errors.Is(err, ErrUserNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", "User "+reqBody.UserID+" not found")
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+
+		resp := ToResponse(updatedUser)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) AddAwayPeriod() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		reqBody, err := req.HandleBody[AwayPeriodRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+		if reqBody.UserID == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+			return
+		}
+
+		period, err := h.userService.AddAwayPeriod(ctx, ToAwayPeriod(*reqBody))
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrBadPeriod):
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+				return
+			case errors.Is(err, ErrUserNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", "User "+reqBody.UserID+" not found")
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+
+		resp := ToAwayPeriodDTO(period)
+		res.JSON(w, http.StatusCreated, resp)
+	}
+}
+
+func (h *Handler) ListAwayPeriods() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+
+		userID := r.URL.Query().Get("user_id")
+		if userID == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+			return
+		}
+
+		periods, err := h.userService.ListAwayPeriods(ctx, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrUserNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", "User "+userID+" not found")
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+
+		resp := ToAwayPeriodsResponse(userID, periods)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) DeleteAwayPeriod() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		reqBody, err := req.HandleBody[DeleteAwayPeriodRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+		if reqBody.ID == 0 {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "id is required")
+			return
+		}
+
+		if err = h.userService.DeleteAwayPeriod(ctx, reqBody.ID); err != nil {
+			switch {
+			case errors.Is(err, ErrPeriodNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
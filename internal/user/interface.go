@@ -2,21 +2,42 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
 )
 
 type Provider interface {
 	SetIsActive(context.Context, string, bool) (*model.User, error)
+	SetWorkingHours(context.Context, string, WorkingHours) (*model.User, error)
+	UpdateProfile(context.Context, string, ProfileUpdate) (*model.User, error)
+	Get(context.Context, string) (*model.User, error)
+	List(context.Context, ListFilter) (*Page, error)
 	GetReviews(context.Context, string) ([]model.PullRequest, error)
-	MassDeactivate(context.Context, string, []string) (MassDeactivateResult, error)
+	MassDeactivate(context.Context, string, []string, bool) (MassDeactivateResult, error)
+	AddAwayPeriod(context.Context, model.AwayPeriod) (*model.AwayPeriod, error)
+	ListAwayPeriods(context.Context, string) ([]model.AwayPeriod, error)
+	DeleteAwayPeriod(context.Context, uint64) error
 }
 
 type Storer interface {
 	UpdateActiveStatus(context.Context, string, bool) (*model.User, error)
+	UpdateWorkingHours(context.Context, string, WorkingHours) (*model.User, error)
+	UpdateProfile(context.Context, string, ProfileUpdate) (*model.User, error)
 	GetUserReviews(context.Context, string) ([]model.PullRequest, error)
 	GetByID(context.Context, string) (*model.User, error)
-	GetReviewCandidates(context.Context, string, string) ([]model.User, error)
-	GetReplacementCandidate(context.Context, string, []string) (*model.User, error)
-	MassDeactivateAndReassign(context.Context, string, []string) (MassDeactivateResult, error)
+	List(context.Context, ListFilter, string, int) ([]model.User, error)
+	GetReviewCandidates(context.Context, string, []string) ([]model.User, error)
+	GetActiveUsers(context.Context, []string, []string) ([]model.User, error)
+	MassDeactivateAndReassign(context.Context, string, []string, bool) (MassDeactivateResult, error)
+	CreateAwayPeriod(context.Context, *model.AwayPeriod) error
+	ListAwayPeriods(context.Context, string) ([]model.AwayPeriod, error)
+	DeleteAwayPeriod(context.Context, uint64) error
+	ReassignAwayReviews(context.Context, time.Time) (AwayReassignResult, error)
+}
+
+// NotificationQueue accepts notifications without waiting for them to be delivered.
+type NotificationQueue interface {
+	Notify(context.Context, notify.Notification)
 }
@@ -1,6 +1,12 @@
 package user
 
-import "github.com/SeeXWH/pr-reviewer-service/internal/model"
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
 
 func ToResponse(u *model.User) ResponseWrapper {
 	if u == nil {
@@ -8,15 +14,59 @@ func ToResponse(u *model.User) ResponseWrapper {
 	}
 
 	return ResponseWrapper{
-		User: DTO{
-			UserID:   u.ID,
-			Username: u.Username,
-			TeamName: u.TeamName,
-			IsActive: u.IsActive,
-		},
+		User: ToDTO(u),
 	}
 }
 
+func ToDTO(u *model.User) DTO {
+	return DTO{
+		UserID:    u.ID,
+		Username:  u.Username,
+		TeamName:  u.TeamName,
+		IsActive:  u.IsActive,
+		Email:     u.Email,
+		Tags:      u.Tags,
+		Timezone:  u.Timezone,
+		WorkStart: u.WorkStart,
+		WorkEnd:   u.WorkEnd,
+		WorkDays:  u.WorkDays,
+	}
+}
+
+func ToListResponse(page *Page) ListResponseDTO {
+	items := make([]DTO, 0, len(page.Users))
+	for i := range page.Users {
+		items = append(items, ToDTO(&page.Users[i]))
+	}
+	return ListResponseDTO{
+		Users:      items,
+		NextCursor: page.NextCursor,
+	}
+}
+
+func ToListFilter(query url.Values) (ListFilter, error) {
+	filter := ListFilter{
+		TeamName:       query.Get("team_name"),
+		UsernamePrefix: query.Get("username_prefix"),
+		Cursor:         query.Get("cursor"),
+	}
+	if raw := query.Get("is_active"); raw != "" {
+		isActive, err := strconv.ParseBool(raw)
+		if err != nil {
+			return ListFilter{}, fmt.Errorf("%w: is_active must be true or false", ErrBadFilter)
+		}
+		filter.IsActive = &isActive
+	}
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return ListFilter{}, fmt.Errorf("%w: limit must be a positive integer", ErrBadFilter)
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
 func ToReviewsResponse(userID string, prs []model.PullRequest) ReviewsResponseDTO {
 	prDTOs := make([]PullRequestShortDTO, 0, len(prs))
 
@@ -36,8 +86,77 @@ func ToReviewsResponse(userID string, prs []model.PullRequest) ReviewsResponseDT
 }
 
 func ToMassDeactivateResponse(res MassDeactivateResult) MassDeactivateResponseDTO {
+	prs := make([]PRChangeDTO, 0, len(res.PullRequests))
+	for _, pr := range res.PullRequests {
+		prs = append(prs, PRChangeDTO{
+			PullRequestID:    pr.PullRequestID,
+			TeamName:         pr.TeamName,
+			RemovedReviewers: nonNil(pr.Removed),
+			AddedReviewers:   nonNil(pr.Added),
+			MissingReviewers: pr.Missing,
+		})
+	}
 	return MassDeactivateResponseDTO{
 		DeactivatedCount: res.DeactivatedCount,
 		ReassignedPRs:    res.ReassignedCount,
+		ShortPRs:         res.ShortCount,
+		DryRun:           res.DryRun,
+		PullRequests:     prs,
+	}
+}
+
+// nonNil keeps empty reviewer lists as [] rather than null in JSON.
+func nonNil(ids []string) []string {
+	if ids == nil {
+		return []string{}
+	}
+	return ids
+}
+
+func ToWorkingHours(req WorkingHoursRequestDTO) WorkingHours {
+	return WorkingHours{
+		Timezone:  req.Timezone,
+		WorkStart: req.WorkStart,
+		WorkEnd:   req.WorkEnd,
+		WorkDays:  req.WorkDays,
+	}
+}
+
+func ToProfileUpdate(req UpdateRequestDTO) ProfileUpdate {
+	return ProfileUpdate{
+		Username: req.Username,
+		Email:    req.Email,
+		Tags:     req.Tags,
+	}
+}
+
+func ToAwayPeriod(req AwayPeriodRequestDTO) model.AwayPeriod {
+	return model.AwayPeriod{
+		UserID:   req.UserID,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		Reason:   req.Reason,
+	}
+}
+
+func ToAwayPeriodDTO(p *model.AwayPeriod) AwayPeriodDTO {
+	return AwayPeriodDTO{
+		ID:           p.ID,
+		UserID:       p.UserID,
+		StartsAt:     p.StartsAt,
+		EndsAt:       p.EndsAt,
+		Reason:       p.Reason,
+		ReassignedAt: p.ReassignedAt,
+	}
+}
+
+func ToAwayPeriodsResponse(userID string, periods []model.AwayPeriod) AwayPeriodsResponseDTO {
+	items := make([]AwayPeriodDTO, 0, len(periods))
+	for i := range periods {
+		items = append(items, ToAwayPeriodDTO(&periods[i]))
+	}
+	return AwayPeriodsResponseDTO{
+		UserID:  userID,
+		Periods: items,
 	}
 }
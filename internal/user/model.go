@@ -1,14 +1,116 @@
 package user
 
+import "github.com/SeeXWH/pr-reviewer-service/internal/model"
+
 type affectedPR struct {
 	PRID          string
 	AuthorID      string
+	TeamName      string
 	OldReviewerID string
 }
 
+type replacementPlan struct {
+	rows        []affectedPR
+	reviewers   map[string][]string
+	deactivated []string
+	required    int
+	// fallback lists the pools tried, in order, once the team's own candidates run out.
+	fallback [][]model.User
+}
+
+type replacementResult struct {
+	added []prReviewer
+	// reassigned counts PRs that got at least one replacement.
+	reassigned int
+	// missing maps PRs still short of required reviewers to the number of reviewers they lack.
+	missing map[string]int
+}
+
+const (
+	reasonDeactivated = "reviewer deactivated"
+	reasonAway        = "reviewer out of office"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// ListFilter narrows /users/list; empty fields match every user.
+type ListFilter struct {
+	TeamName       string
+	IsActive       *bool
+	UsernamePrefix string
+	Cursor         string
+	Limit          int
+}
+
+type Page struct {
+	Users      []model.User
+	NextCursor string
+}
+
+type WorkingHours struct {
+	Timezone  string
+	WorkStart string
+	WorkEnd   string
+	// WorkDays are "mon".."sun"; empty means workhours.DefaultWorkDays.
+	WorkDays []string
+}
+
+// ProfileUpdate holds the fields changed by /users/update; nil fields are left as they are.
+type ProfileUpdate struct {
+	Username *string
+	Email    *string
+	Tags     []string
+}
+
+type AwayReassignResult struct {
+	PeriodCount     int
+	ReassignedCount int
+}
+
 type MassDeactivateResult struct {
 	DeactivatedCount int
 	ReassignedCount  int
+	// ShortCount is the number of PRs left with fewer reviewers than their team requires.
+	ShortCount int
+	// DryRun is set when the changes were computed and rolled back.
+	DryRun bool
+	// Assignments lists the reviewers added in place of the deactivated users.
+	Assignments []Assignment
+	// PullRequests lists every open PR that loses a reviewer, ordered by PR id.
+	PullRequests []PRChange
+}
+
+// PRChange describes how mass deactivation changes the reviewers of one PR.
+type PRChange struct {
+	PullRequestID string
+	TeamName      string
+	Removed       []string
+	Added         []string
+	// Missing is how many reviewers the PR still lacks when the team had nobody left to pick.
+	Missing int
+}
+
+// FallbackLevel is one step of the chain tried when a PR's own team has nobody left to review it:
+// the active members of Teams together with the active users in UserIDs.
+type FallbackLevel struct {
+	Teams   []string
+	UserIDs []string
+}
+
+// ReviewReassignment is the outcome of ReassignPRReviewsTx.
+type ReviewReassignment struct {
+	ReassignedCount int
+	Assignments     []Assignment
+	// Missing maps PRs still short of required reviewers to the number of reviewers they lack.
+	Missing map[string]int
+}
+
+type Assignment struct {
+	PullRequestID string
+	UserID        string
 }
 
 type prReviewer struct {
@@ -2,14 +2,25 @@ package user
 
 import (
 	"context"
+	"errors"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/SeeXWH/pr-reviewer-service/internal/event"
 	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/selection"
 	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+// errDryRun rolls back a transaction whose result is only a preview.
+var errDryRun = errors.New("dry run")
+
 type Repository struct {
 	db *db.PostgresDB
 }
@@ -20,18 +31,42 @@ func NewRepository(db *db.PostgresDB) *Repository {
 	}
 }
 
+// AvailableAt filters a users query down to people who are not in an away period at t.
+func AvailableAt(t time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			"NOT EXISTS (SELECT 1 FROM away_periods WHERE away_periods.user_id = users.user_id"+
+				" AND away_periods.starts_at <= ? AND away_periods.ends_at > ?)",
+			t, t,
+		)
+	}
+}
+
+// InTeam limits a users query to members of teamName and sets TeamName on the results.
+func InTeam(teamName string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Select("users.*, team_members.team_name").
+			Joins("JOIN team_members ON team_members.user_id = users.user_id AND team_members.team_name = ?", teamName)
+	}
+}
+
+// WithPrimaryTeam sets TeamName on the results of a users query to each user's primary team.
+func WithPrimaryTeam(db *gorm.DB) *gorm.DB {
+	return db.Select("users.*, team_members.team_name").
+		Joins("LEFT JOIN team_members ON team_members.user_id = users.user_id AND team_members.is_primary")
+}
+
 func (r *Repository) UpdateActiveStatus(ctx context.Context, userID string, isActive bool) (*model.User, error) {
-	var user model.User
-	err := r.db.PostgresDB.WithContext(ctx).First(&user, "user_id = ?", userID).Error
+	user, err := r.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
-	err = r.db.PostgresDB.WithContext(ctx).Model(&user).Update("is_active", isActive).Error
+	err = r.db.PostgresDB.WithContext(ctx).Model(user).Update("is_active", isActive).Error
 	if err != nil {
 		return nil, err
 	}
 	user.IsActive = isActive
-	return &user, nil
+	return user, nil
 }
 
 func (r *Repository) GetUserReviews(ctx context.Context, userID string) ([]model.PullRequest, error) {
@@ -55,50 +90,144 @@ func (r *Repository) GetUserReviews(ctx context.Context, userID string) ([]model
 	return prs, nil
 }
 
-func (r *Repository) GetReviewCandidates(ctx context.Context, teamName string, authorID string) ([]model.User, error) {
+func (r *Repository) GetReviewCandidates(
+	ctx context.Context,
+	teamName string,
+	excludeUserIDs []string,
+) ([]model.User, error) {
 	var candidates []model.User
-	err := r.db.PostgresDB.WithContext(ctx).
-		Where("team_name = ? AND is_active = ? AND user_id != ?", teamName, true, authorID).
-		Order("RANDOM()").
-		Limit(2).
-		Find(&candidates).Error
-
+	now := time.Now()
+	query := r.db.PostgresDB.WithContext(ctx).
+		Scopes(AvailableAt(now), InTeam(teamName)).
+		Where("users.is_active = ?", true)
+	if len(excludeUserIDs) > 0 {
+		query = query.Where("users.user_id NOT IN ?", excludeUserIDs)
+	}
+	err := query.Order("users.user_id").Find(&candidates).Error
 	if err != nil {
 		return nil, err
 	}
+	if err = MarkOnDuty(r.db.PostgresDB.WithContext(ctx), candidates, now); err != nil {
+		return nil, err
+	}
 	return candidates, nil
 }
 
-func (r *Repository) GetByID(ctx context.Context, userID string) (*model.User, error) {
-	var user model.User
-	err := r.db.PostgresDB.WithContext(ctx).First(&user, "user_id = ?", userID).Error
-	return &user, err
-}
-
-func (r *Repository) GetReplacementCandidate(
+func (r *Repository) GetActiveUsers(
 	ctx context.Context,
-	teamName string,
+	userIDs []string,
 	excludeUserIDs []string,
-) (*model.User, error) {
-	var candidate model.User
-	err := r.db.PostgresDB.WithContext(ctx).
-		Where("team_name = ? AND is_active = ?", teamName, true).
-		Not("user_id", excludeUserIDs).
-		Order("RANDOM()").
-		First(&candidate).Error
+) ([]model.User, error) {
+	var users []model.User
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+	now := time.Now()
+	query := r.db.PostgresDB.WithContext(ctx).
+		Scopes(AvailableAt(now), WithPrimaryTeam).
+		Where("users.user_id IN ? AND users.is_active = ?", userIDs, true)
+	if len(excludeUserIDs) > 0 {
+		query = query.Where("users.user_id NOT IN ?", excludeUserIDs)
+	}
+	err := query.Order("users.user_id").Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	if err = MarkOnDuty(r.db.PostgresDB.WithContext(ctx), users, now); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
 
+func (r *Repository) UpdateWorkingHours(ctx context.Context, userID string, hours WorkingHours) (*model.User, error) {
+	user, err := r.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Timezone, user.WorkStart, user.WorkEnd = hours.Timezone, hours.WorkStart, hours.WorkEnd
+	user.WorkDays = hours.WorkDays
+	err = r.db.PostgresDB.WithContext(ctx).Model(user).
+		Select("timezone", "work_start", "work_end", "work_days").
+		Updates(user).Error
 	if err != nil {
 		return nil, err
 	}
-	return &candidate, nil
+	return user, nil
+}
+
+func (r *Repository) UpdateProfile(ctx context.Context, userID string, upd ProfileUpdate) (*model.User, error) {
+	user, err := r.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []string
+	if upd.Username != nil {
+		user.Username = *upd.Username
+		columns = append(columns, "username")
+	}
+	if upd.Email != nil {
+		user.Email = *upd.Email
+		columns = append(columns, "email")
+	}
+	if upd.Tags != nil {
+		user.Tags = upd.Tags
+		columns = append(columns, "tags")
+	}
+	if len(columns) == 0 {
+		return user, nil
+	}
+	err = r.db.PostgresDB.WithContext(ctx).Model(user).Select(columns).Updates(user).Error
+	return user, err
+}
+
+// GetByID returns the user with TeamName set to their primary team.
+func (r *Repository) GetByID(ctx context.Context, userID string) (*model.User, error) {
+	var user model.User
+	err := r.db.PostgresDB.WithContext(ctx).
+		Scopes(WithPrimaryTeam).
+		Where("users.user_id = ?", userID).
+		First(&user).Error
+	return &user, err
+}
+
+// List returns up to limit users matching filter with user_id greater than after, ordered by
+// user_id. TeamName is set to the filtered team, or to the primary team without a team filter.
+func (r *Repository) List(ctx context.Context, filter ListFilter, after string, limit int) ([]model.User, error) {
+	query := r.db.PostgresDB.WithContext(ctx).Model(&model.User{})
+	if filter.TeamName != "" {
+		query = query.Scopes(InTeam(filter.TeamName))
+	} else {
+		query = query.Scopes(WithPrimaryTeam)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("users.is_active = ?", *filter.IsActive)
+	}
+	if filter.UsernamePrefix != "" {
+		query = query.Where(`users.username ILIKE ? ESCAPE '\'`, escapeLike(filter.UsernamePrefix)+"%")
+	}
+	if after != "" {
+		query = query.Where("users.user_id > ?", after)
+	}
+
+	var users []model.User
+	err := query.Order("users.user_id").Limit(limit).Find(&users).Error
+	return users, err
 }
 
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// MassDeactivateAndReassign deactivates the members of teamName among userIDs and replaces them
+// on their open reviews. With dryRun the same changes are made and then rolled back.
 func (r *Repository) MassDeactivateAndReassign(
 	ctx context.Context,
 	teamName string,
 	userIDs []string,
+	dryRun bool,
 ) (MassDeactivateResult, error) {
-	var result MassDeactivateResult
+	result := MassDeactivateResult{DryRun: dryRun}
 
 	err := r.db.PostgresDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		count, err := r.deactivateUsers(tx, teamName, userIDs)
@@ -110,70 +239,472 @@ func (r *Repository) MassDeactivateAndReassign(
 			return nil
 		}
 
-		candidates, err := r.getActiveCandidates(tx, teamName)
+		affected, err := r.getAffectedPRs(tx, userIDs, "")
 		if err != nil {
 			return err
 		}
-
-		affectedPRs, err := r.getAffectedPRs(tx, userIDs)
+		replaced, err := r.reassignReviews(tx, affected, teamName, userIDs, nil, reasonDeactivated)
 		if err != nil {
 			return err
 		}
-		if len(affectedPRs) == 0 {
-			return nil
+		result.ReassignedCount = replaced.reassigned
+		result.ShortCount = len(replaced.missing)
+		result.Assignments = toAssignments(replaced.added)
+		result.PullRequests = toPRChanges(affected, replaced, teamName)
+		if dryRun {
+			return errDryRun
 		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		err = nil
+	}
 
-		newRelations, count := r.calculateReplacements(affectedPRs, candidates)
-		result.ReassignedCount = count
+	return result, err
+}
+
+// ReassignAwayReviews hands the open reviews of users whose away period has started
+// to their teammates, once per period.
+func (r *Repository) ReassignAwayReviews(ctx context.Context, now time.Time) (AwayReassignResult, error) {
+	var result AwayReassignResult
+
+	err := r.db.PostgresDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var periods []model.AwayPeriod
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("reassigned_at IS NULL AND starts_at <= ? AND ends_at > ?", now, now).
+			Find(&periods).Error
+		if err != nil || len(periods) == 0 {
+			return err
+		}
+
+		periodIDs := make([]uint64, 0, len(periods))
+		userIDs := make([]string, 0, len(periods))
+		for _, p := range periods {
+			periodIDs = append(periodIDs, p.ID)
+			userIDs = append(userIDs, p.UserID)
+		}
+		var users []model.User
+		err = tx.Scopes(WithPrimaryTeam).Where("users.user_id IN ?", userIDs).Order("users.user_id").Find(&users).Error
+		if err != nil {
+			return err
+		}
+		byTeam := make(map[string][]string)
+		var teams []string
+		for _, u := range users {
+			if _, ok := byTeam[u.TeamName]; !ok {
+				teams = append(teams, u.TeamName)
+			}
+			byTeam[u.TeamName] = append(byTeam[u.TeamName], u.ID)
+		}
+		for _, teamName := range teams {
+			_, count, err := r.ReassignAllReviewsTx(tx, teamName, byTeam[teamName], reasonAway)
+			if err != nil {
+				return err
+			}
+			result.ReassignedCount += count
+		}
 
-		return r.applyReviewerChanges(tx, userIDs, affectedPRs, newRelations)
+		result.PeriodCount = len(periods)
+		return tx.Model(&model.AwayPeriod{}).Where("id IN ?", periodIDs).Update("reassigned_at", now).Error
 	})
 
 	return result, err
 }
 
+// ReassignReviewsTx replaces userIDs on the open PRs of teamName with other members of that team.
+// Callers must have already taken userIDs out of the team or deactivated them, so they are
+// no longer candidates.
+func (r *Repository) ReassignReviewsTx(
+	tx *gorm.DB,
+	teamName string,
+	userIDs []string,
+	reason string,
+) ([]Assignment, int, error) {
+	affected, err := r.getAffectedPRs(tx, userIDs, teamName)
+	if err != nil {
+		return nil, 0, err
+	}
+	replaced, err := r.reassignReviews(tx, affected, teamName, userIDs, nil, reason)
+	if err != nil {
+		return nil, 0, err
+	}
+	return toAssignments(replaced.added), replaced.reassigned, nil
+}
+
+// ReassignAllReviewsTx replaces userIDs on every open PR they review, picking from each PR's team;
+// PRs without a stored team use fallbackTeam. It is used when users stop reviewing altogether.
+func (r *Repository) ReassignAllReviewsTx(
+	tx *gorm.DB,
+	fallbackTeam string,
+	userIDs []string,
+	reason string,
+) ([]Assignment, int, error) {
+	affected, err := r.getAffectedPRs(tx, userIDs, "")
+	if err != nil {
+		return nil, 0, err
+	}
+	replaced, err := r.reassignReviews(tx, affected, fallbackTeam, userIDs, nil, reason)
+	if err != nil {
+		return nil, 0, err
+	}
+	return toAssignments(replaced.added), replaced.reassigned, nil
+}
+
+// ReassignPRReviewsTx replaces userIDs on the open PRs prIDs. Replacements come from each PR's team
+// first and then from the fallback levels in order; PRs that still lack reviewers are reported in
+// Missing.
+func (r *Repository) ReassignPRReviewsTx(
+	tx *gorm.DB,
+	prIDs []string,
+	userIDs []string,
+	fallback []FallbackLevel,
+	reason string,
+) (ReviewReassignment, error) {
+	result := ReviewReassignment{Missing: map[string]int{}}
+	if len(prIDs) == 0 || len(userIDs) == 0 {
+		return result, nil
+	}
+	var affected []affectedPR
+	err := r.affectedPRsQuery(tx, userIDs).
+		Where("pull_requests.pull_request_id IN ?", prIDs).
+		Scan(&affected).Error
+	if err != nil {
+		return result, err
+	}
+	replaced, err := r.reassignReviews(tx, affected, "", userIDs, fallback, reason)
+	if err != nil {
+		return result, err
+	}
+	result.ReassignedCount = replaced.reassigned
+	result.Assignments = toAssignments(replaced.added)
+	if replaced.missing != nil {
+		result.Missing = replaced.missing
+	}
+	return result, nil
+}
+
+// reassignReviews replaces userIDs on the affected reviews with the least loaded available members
+// of each PR's team, moving on to the fallback levels when the team runs out. PRs without a stored
+// team use fallbackTeam.
+func (r *Repository) reassignReviews(
+	tx *gorm.DB,
+	affected []affectedPR,
+	fallbackTeam string,
+	userIDs []string,
+	fallback []FallbackLevel,
+	reason string,
+) (replacementResult, error) {
+	var result replacementResult
+	if len(affected) == 0 {
+		return result, nil
+	}
+	reviewers, err := r.getCurrentReviewers(tx, affected)
+	if err != nil {
+		return result, err
+	}
+
+	byTeam := make(map[string][]affectedPR)
+	var teams []string
+	for _, row := range affected {
+		team := row.TeamName
+		if team == "" {
+			team = fallbackTeam
+		}
+		if _, ok := byTeam[team]; !ok {
+			teams = append(teams, team)
+		}
+		byTeam[team] = append(byTeam[team], row)
+	}
+
+	candidates := make(map[string][]model.User, len(teams))
+	var all []model.User
+	for _, team := range teams {
+		if candidates[team], err = r.getActiveCandidates(tx, team); err != nil {
+			return result, err
+		}
+		all = append(all, candidates[team]...)
+	}
+	levels := make([][]model.User, len(fallback))
+	for i, level := range fallback {
+		if levels[i], err = r.getFallbackCandidates(tx, level); err != nil {
+			return result, err
+		}
+		all = append(all, levels[i]...)
+	}
+	loads, err := r.getOpenReviewLoads(tx, all)
+	if err != nil {
+		return result, err
+	}
+
+	result.missing = make(map[string]int)
+	for _, team := range teams {
+		required, err := r.getRequiredReviewers(tx, team)
+		if err != nil {
+			return result, err
+		}
+		plan := replacementPlan{
+			rows:        byTeam[team],
+			reviewers:   reviewers,
+			deactivated: userIDs,
+			required:    required,
+			fallback:    levels,
+		}
+		teamResult := r.calculateReplacements(plan, candidates[team], loads)
+		result.added = append(result.added, teamResult.added...)
+		result.reassigned += teamResult.reassigned
+		maps.Copy(result.missing, teamResult.missing)
+	}
+
+	if err = r.applyReviewerChanges(tx, userIDs, affected, result.added); err != nil {
+		return result, err
+	}
+	return result, event.Append(tx, reassignmentEvents(affected, result.added, reason)...)
+}
+
+func toAssignments(relations []prReviewer) []Assignment {
+	assignments := make([]Assignment, 0, len(relations))
+	for _, rel := range relations {
+		assignments = append(assignments, Assignment{PullRequestID: rel.PullRequestID, UserID: rel.UserID})
+	}
+	return assignments
+}
+
+func toPRChanges(affected []affectedPR, replaced replacementResult, fallbackTeam string) []PRChange {
+	index := make(map[string]int, len(affected))
+	var changes []PRChange
+	for _, row := range affected {
+		i, ok := index[row.PRID]
+		if !ok {
+			team := row.TeamName
+			if team == "" {
+				team = fallbackTeam
+			}
+			i = len(changes)
+			index[row.PRID] = i
+			changes = append(changes, PRChange{
+				PullRequestID: row.PRID,
+				TeamName:      team,
+				Missing:       replaced.missing[row.PRID],
+			})
+		}
+		changes[i].Removed = append(changes[i].Removed, row.OldReviewerID)
+	}
+	for _, rel := range replaced.added {
+		i := index[rel.PullRequestID]
+		changes[i].Added = append(changes[i].Added, rel.UserID)
+	}
+	slices.SortFunc(changes, func(a, b PRChange) int {
+		return strings.Compare(a.PullRequestID, b.PullRequestID)
+	})
+	return changes
+}
+
+func (r *Repository) CreateAwayPeriod(ctx context.Context, period *model.AwayPeriod) error {
+	return r.db.PostgresDB.WithContext(ctx).Create(period).Error
+}
+
+func (r *Repository) ListAwayPeriods(ctx context.Context, userID string) ([]model.AwayPeriod, error) {
+	var periods []model.AwayPeriod
+	err := r.db.PostgresDB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("starts_at").
+		Find(&periods).Error
+	return periods, err
+}
+
+func (r *Repository) DeleteAwayPeriod(ctx context.Context, id uint64) error {
+	res := r.db.PostgresDB.WithContext(ctx).Delete(&model.AwayPeriod{}, "id = ?", id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func (r *Repository) deactivateUsers(tx *gorm.DB, teamName string, userIDs []string) (int, error) {
+	members := tx.Session(&gorm.Session{NewDB: true}).
+		Model(&model.TeamMember{}).
+		Select("user_id").
+		Where("team_name = ?", teamName)
 	res := tx.Model(&model.User{}).
-		Where("team_name = ? AND user_id IN ?", teamName, userIDs).
+		Where("user_id IN ? AND user_id IN (?)", userIDs, members).
 		Update("is_active", false)
 	return int(res.RowsAffected), res.Error
 }
 
 func (r *Repository) getActiveCandidates(tx *gorm.DB, teamName string) ([]model.User, error) {
 	var candidates []model.User
-	err := tx.Where("team_name = ? AND is_active = ?", teamName, true).Find(&candidates).Error
-	return candidates, err
+	now := time.Now()
+	err := tx.Scopes(AvailableAt(now), InTeam(teamName)).
+		Where("users.is_active = ?", true).
+		Find(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+	return candidates, MarkOnDuty(tx, candidates, now)
+}
+
+// getFallbackCandidates returns the active, available members of level's teams and users, each once.
+func (r *Repository) getFallbackCandidates(tx *gorm.DB, level FallbackLevel) ([]model.User, error) {
+	var candidates []model.User
+	seen := make(map[string]bool)
+	add := func(users []model.User) {
+		for _, u := range users {
+			if !seen[u.ID] {
+				seen[u.ID] = true
+				candidates = append(candidates, u)
+			}
+		}
+	}
+	for _, team := range level.Teams {
+		members, err := r.getActiveCandidates(tx, team)
+		if err != nil {
+			return nil, err
+		}
+		add(members)
+	}
+	if len(level.UserIDs) == 0 {
+		return candidates, nil
+	}
+
+	var users []model.User
+	now := time.Now()
+	err := tx.Scopes(AvailableAt(now), WithPrimaryTeam).
+		Where("users.user_id IN ? AND users.is_active = ?", level.UserIDs, true).
+		Order("users.user_id").
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	if err = MarkOnDuty(tx, users, now); err != nil {
+		return nil, err
+	}
+	add(users)
+	return candidates, nil
 }
 
-func (r *Repository) getAffectedPRs(tx *gorm.DB, userIDs []string) ([]affectedPR, error) {
+func (r *Repository) getOpenReviewLoads(tx *gorm.DB, candidates []model.User) (map[string]int, error) {
+	ids := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.ID)
+	}
+	return selection.OpenReviewLoads(tx, ids)
+}
+
+// getAffectedPRs lists the open reviews of userIDs, only on PRs of teamName when it is set.
+func (r *Repository) getAffectedPRs(tx *gorm.DB, userIDs []string, teamName string) ([]affectedPR, error) {
 	var rows []affectedPR
-	err := tx.Table("pr_reviewers").
-		Select("pr_reviewers.pull_request_id as pr_id, pull_requests.author_id").
-		Joins("JOIN pull_requests ON pull_requests.pull_request_id = pr_reviewers.pull_request_id").
-		Where("pr_reviewers.user_id IN ? AND pull_requests.status = ?", userIDs, "OPEN").
-		Scan(&rows).Error
+	query := r.affectedPRsQuery(tx, userIDs)
+	if teamName != "" {
+		query = query.Where("pull_requests.team_name = ?", teamName)
+	}
+	err := query.Scan(&rows).Error
 	return rows, err
 }
 
-func (r *Repository) calculateReplacements(rows []affectedPR, candidates []model.User) ([]prReviewer, int) {
-	var newRelations []prReviewer
-	reassignedCount := 0
+// affectedPRsQuery selects the reviews userIDs hold on open PRs.
+func (r *Repository) affectedPRsQuery(tx *gorm.DB, userIDs []string) *gorm.DB {
+	return tx.Table("pr_reviewers").
+		Select("pr_reviewers.pull_request_id as pr_id, pull_requests.author_id, pull_requests.team_name, "+
+			"pr_reviewers.user_id as old_reviewer_id").
+		Joins("JOIN pull_requests ON pull_requests.pull_request_id = pr_reviewers.pull_request_id").
+		Where("pr_reviewers.user_id IN ? AND pull_requests.status = ?", userIDs, "OPEN")
+}
+
+func (r *Repository) getCurrentReviewers(tx *gorm.DB, affected []affectedPR) (map[string][]string, error) {
+	prIDs := make([]string, 0, len(affected))
+	for _, row := range affected {
+		prIDs = append(prIDs, row.PRID)
+	}
 
-	if len(candidates) == 0 {
-		return newRelations, 0
+	var rows []prReviewer
+	err := tx.Table("pr_reviewers").
+		Where("pull_request_id IN ?", prIDs).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
 	}
 
+	reviewers := make(map[string][]string, len(prIDs))
 	for _, row := range rows {
-		candidate := pickRandomCandidate(candidates, row.AuthorID)
-		if candidate != nil {
-			newRelations = append(newRelations, prReviewer{
-				PullRequestID: row.PRID,
-				UserID:        candidate.ID,
-			})
-			reassignedCount++
+		reviewers[row.PullRequestID] = append(reviewers[row.PullRequestID], row.UserID)
+	}
+	return reviewers, nil
+}
+
+func (r *Repository) getRequiredReviewers(tx *gorm.DB, teamName string) (int, error) {
+	var team model.Team
+	err := tx.Select("team_name", "required_reviewers").First(&team, "team_name = ?", teamName).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.DefaultRequiredReviewers, nil
 		}
+		return 0, err
+	}
+	if team.RequiredReviewers <= 0 {
+		return model.DefaultRequiredReviewers, nil
 	}
-	return newRelations, reassignedCount
+	return team.RequiredReviewers, nil
+}
+
+// calculateReplacements tops each PR of the plan back up to plan.required reviewers. Every pick
+// goes to the least loaded candidate and raises their load, so the orphaned reviews are spread
+// across the team. The author, the removed users and the PR's remaining reviewers are never picked.
+func (r *Repository) calculateReplacements(
+	plan replacementPlan,
+	candidates []model.User,
+	loads map[string]int,
+) replacementResult {
+	result := replacementResult{missing: make(map[string]int)}
+
+	removed := make(map[string]bool, len(plan.deactivated))
+	for _, id := range plan.deactivated {
+		removed[id] = true
+	}
+
+	handled := make(map[string]bool, len(plan.rows))
+	for _, row := range plan.rows {
+		if handled[row.PRID] {
+			continue
+		}
+		handled[row.PRID] = true
+
+		exclude := maps.Clone(removed)
+		exclude[row.AuthorID] = true
+		remaining := 0
+		for _, id := range plan.reviewers[row.PRID] {
+			if !removed[id] {
+				exclude[id] = true
+				remaining++
+			}
+		}
+
+		added := 0
+		for _, pool := range append([][]model.User{candidates}, plan.fallback...) {
+			for added < plan.required-remaining {
+				candidate := pickLeastLoadedCandidate(pool, loads, exclude)
+				if candidate == nil {
+					break
+				}
+				result.added = append(result.added, prReviewer{
+					PullRequestID: row.PRID,
+					UserID:        candidate.ID,
+				})
+				loads[candidate.ID]++
+				exclude[candidate.ID] = true
+				added++
+			}
+		}
+		if added > 0 {
+			result.reassigned++
+		}
+		if missing := plan.required - remaining - added; missing > 0 {
+			result.missing[row.PRID] = missing
+		}
+	}
+	return result
 }
 
 func (r *Repository) applyReviewerChanges(
@@ -194,19 +725,44 @@ func (r *Repository) applyReviewerChanges(
 	}
 
 	if len(newRelations) > 0 {
-		return tx.Table("pr_reviewers").
-			Clauses(clause.OnConflict{DoNothing: true}).
-			Create(&newRelations).Error
+		return tx.Table("pr_reviewers").Create(&newRelations).Error
 	}
 
 	return nil
 }
 
-func pickRandomCandidate(candidates []model.User, excludeAuthorID string) *model.User {
-	for _, c := range candidates {
-		if c.ID != excludeAuthorID {
-			return &c
+func reassignmentEvents(affected []affectedPR, newRelations []prReviewer, reason string) []model.PREvent {
+	events := make([]model.PREvent, 0, len(affected)+len(newRelations))
+	for _, row := range affected {
+		events = append(events, event.Unassigned(row.PRID, row.OldReviewerID, reason))
+	}
+	for _, rel := range newRelations {
+		events = append(events, event.Assigned(rel.PullRequestID, rel.UserID))
+	}
+	return events
+}
+
+// pickLeastLoadedCandidate prefers people on duty, then the fewest open reviews, breaking ties randomly.
+func pickLeastLoadedCandidate(candidates []model.User, loads map[string]int, exclude map[string]bool) *model.User {
+	var best *model.User
+	ties := 0
+	for i := range candidates {
+		c := &candidates[i]
+		if exclude[c.ID] {
+			continue
+		}
+		switch {
+		case best == nil || c.OnDuty && !best.OnDuty:
+			best, ties = c, 1
+		case c.OnDuty != best.OnDuty:
+		case loads[c.ID] < loads[best.ID]:
+			best, ties = c, 1
+		case loads[c.ID] == loads[best.ID]:
+			ties++
+			if rand.IntN(ties) == 0 {
+				best = c
+			}
 		}
 	}
-	return nil
+	return best
 }
@@ -3,22 +3,30 @@ package user
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"net/mail"
+	"strings"
+	"time"
 
 	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
+	"github.com/SeeXWH/pr-reviewer-service/internal/workhours"
 
 	"gorm.io/gorm"
 )
 
 type Service struct {
-	repo Storer
-	log  *slog.Logger
+	repo     Storer
+	notifier NotificationQueue
+	log      *slog.Logger
 }
 
-func NewService(repo Storer, log *slog.Logger) *Service {
+func NewService(repo Storer, notifier NotificationQueue, log *slog.Logger) *Service {
 	return &Service{
-		repo: repo,
-		log:  log.With("component", "userService"),
+		repo:     repo,
+		notifier: notifier,
+		log:      log.With("component", "userService"),
 	}
 }
 
@@ -39,6 +47,111 @@ func (s *Service) SetIsActive(ctx context.Context, userID string, isActive bool)
 	return updatedUser, nil
 }
 
+func (s *Service) SetWorkingHours(ctx context.Context, userID string, hours WorkingHours) (*model.User, error) {
+	log := s.log.With("op", "SetWorkingHours", "user_id", userID)
+
+	if err := validateWorkingHours(hours); err != nil {
+		return nil, err
+	}
+	hours.WorkDays = normalizeWorkDays(hours.WorkDays)
+	updatedUser, err := s.repo.UpdateWorkingHours(ctx, userID, hours)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.WarnContext(ctx, "failed to update working hours: user not found")
+			return nil, ErrUserNotFound
+		}
+		log.ErrorContext(ctx, "failed to update working hours", "error", err)
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "working hours updated",
+		"timezone", hours.Timezone,
+		"work_start", hours.WorkStart,
+		"work_end", hours.WorkEnd,
+		"work_days", hours.WorkDays,
+	)
+	return updatedUser, nil
+}
+
+func validateWorkingHours(hours WorkingHours) error {
+	if hours.Timezone != "" {
+		if _, err := time.LoadLocation(hours.Timezone); err != nil {
+			return fmt.Errorf("%w: unknown timezone %s", ErrBadWorkingHours, hours.Timezone)
+		}
+	}
+	if (hours.WorkStart == "") != (hours.WorkEnd == "") {
+		return ErrBadWorkingHours
+	}
+	if hours.WorkStart == "" {
+		if len(hours.WorkDays) > 0 {
+			return fmt.Errorf("%w: work_days need a working window", ErrBadWorkingHours)
+		}
+		return nil
+	}
+	for _, day := range hours.WorkDays {
+		if _, ok := workhours.ParseWeekday(day); !ok {
+			return fmt.Errorf("%w: bad work day %s", ErrBadWorkingHours, day)
+		}
+	}
+	if _, ok := workhours.ParseClock(hours.WorkStart); !ok {
+		return fmt.Errorf("%w: bad work_start %s", ErrBadWorkingHours, hours.WorkStart)
+	}
+	if _, ok := workhours.ParseClock(hours.WorkEnd); !ok {
+		return fmt.Errorf("%w: bad work_end %s", ErrBadWorkingHours, hours.WorkEnd)
+	}
+	return nil
+}
+
+// normalizeWorkDays lowercases the days and orders them Monday first without repeats.
+func normalizeWorkDays(days []string) []string {
+	if len(days) == 0 {
+		return nil
+	}
+	var set [7]bool
+	for _, day := range days {
+		wd, _ := workhours.ParseWeekday(day)
+		set[wd] = true
+	}
+	normalized := make([]string, 0, len(days))
+	for i := range 7 {
+		wd := time.Weekday((i + 1) % 7)
+		if set[wd] {
+			normalized = append(normalized, strings.ToLower(wd.String()[:3]))
+		}
+	}
+	return normalized
+}
+
+// UpdateProfile changes the username, email and tags of a user. Tags are normalized
+// to lowercase; an empty list clears them.
+func (s *Service) UpdateProfile(ctx context.Context, userID string, upd ProfileUpdate) (*model.User, error) {
+	log := s.log.With("op", "UpdateProfile", "user_id", userID)
+
+	if upd.Username != nil && strings.TrimSpace(*upd.Username) == "" {
+		return nil, ErrBadProfile
+	}
+	if upd.Email != nil && *upd.Email != "" {
+		addr, err := mail.ParseAddress(*upd.Email)
+		if err != nil || addr.Address != *upd.Email {
+			return nil, ErrBadProfile
+		}
+	}
+	upd.Tags = model.NormalizeTags(upd.Tags)
+
+	updatedUser, err := s.repo.UpdateProfile(ctx, userID, upd)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.WarnContext(ctx, "failed to update profile: user not found")
+			return nil, ErrUserNotFound
+		}
+		log.ErrorContext(ctx, "failed to update profile", "error", err)
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "profile updated", "tags", updatedUser.Tags)
+	return updatedUser, nil
+}
+
 func (s *Service) GetReviews(ctx context.Context, userID string) ([]model.PullRequest, error) {
 	log := s.log.With("op", "GetReviews", "user_id", userID)
 
@@ -56,11 +169,11 @@ func (s *Service) GetReviews(ctx context.Context, userID string) ([]model.PullRe
 func (s *Service) GetReviewCandidates(
 	ctx context.Context,
 	teamName string,
-	excludeUserID string,
+	excludeUserIDs []string,
 ) ([]model.User, error) {
-	log := s.log.With("op", "GetReviewCandidates", "team", teamName)
+	log := s.log.With("op", "GetReviewCandidates", "team", teamName, "excluded_count", len(excludeUserIDs))
 
-	users, err := s.repo.GetReviewCandidates(ctx, teamName, excludeUserID)
+	users, err := s.repo.GetReviewCandidates(ctx, teamName, excludeUserIDs)
 	if err != nil {
 		log.ErrorContext(ctx, "failed to fetch candidates", "error", err)
 		return nil, err
@@ -68,6 +181,19 @@ func (s *Service) GetReviewCandidates(
 	return users, nil
 }
 
+func (s *Service) GetActiveUsers(
+	ctx context.Context,
+	userIDs []string,
+	excludeUserIDs []string,
+) ([]model.User, error) {
+	users, err := s.repo.GetActiveUsers(ctx, userIDs, excludeUserIDs)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to fetch active users", "op", "GetActiveUsers", "error", err)
+		return nil, err
+	}
+	return users, nil
+}
+
 func (s *Service) GetByID(ctx context.Context, id string) (*model.User, error) {
 	user, err := s.repo.GetByID(ctx, id)
 	if err != nil {
@@ -79,32 +205,139 @@ func (s *Service) GetByID(ctx context.Context, id string) (*model.User, error) {
 	return user, nil
 }
 
-func (s *Service) GetReplacementCandidate(
-	ctx context.Context,
-	teamName string,
-	excludeUserIDs []string,
-) (*model.User, error) {
-	log := s.log.With("op", "GetReplacementCandidate", "team", teamName, "excluded_count", len(excludeUserIDs))
-
-	candidate, err := s.repo.GetReplacementCandidate(ctx, teamName, excludeUserIDs)
+// Get is GetByID for the API: a missing user is reported as ErrUserNotFound.
+func (s *Service) Get(ctx context.Context, userID string) (*model.User, error) {
+	user, err := s.GetByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			log.WarnContext(ctx, "no replacement candidate found")
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *Service) List(ctx context.Context, filter ListFilter) (*Page, error) {
+	var after string
+	if filter.Cursor != "" {
+		cursor, err := decodeCursor(filter.Cursor)
+		if err != nil {
 			return nil, err
 		}
-		log.ErrorContext(ctx, "failed to find replacement", "error", err)
+		after = cursor
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	limit = min(limit, MaxPageSize)
+
+	users, err := s.repo.List(ctx, filter, after, limit+1)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to list users", "op", "List", "error", err)
 		return nil, err
 	}
 
-	log.InfoContext(ctx, "replacement candidate selected", "candidate_id", candidate.ID)
-	return candidate, nil
+	page := &Page{Users: users}
+	if len(users) > limit {
+		page.Users = users[:limit]
+		page.NextCursor = encodeCursor(page.Users[limit-1].ID)
+	}
+	return page, nil
 }
 
-func (s *Service) MassDeactivate(ctx context.Context, teamName string, userIDs []string) (MassDeactivateResult, error) {
-	result, err := s.repo.MassDeactivateAndReassign(ctx, teamName, userIDs)
+// MassDeactivate deactivates userIDs in teamName and hands their open reviews to teammates.
+// With dryRun nothing is saved and nobody is notified; the result shows what would change.
+func (s *Service) MassDeactivate(
+	ctx context.Context,
+	teamName string,
+	userIDs []string,
+	dryRun bool,
+) (MassDeactivateResult, error) {
+	result, err := s.repo.MassDeactivateAndReassign(ctx, teamName, userIDs, dryRun)
 	if err != nil {
 		s.log.ErrorContext(ctx, "failed to mass deactivation", "error", err)
 		return MassDeactivateResult{}, err
 	}
+	if result.ShortCount > 0 {
+		s.log.WarnContext(ctx, "prs left short of reviewers after mass deactivation",
+			"op", "MassDeactivate", "team_name", teamName, "short_prs", result.ShortCount, "dry_run", dryRun)
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	NotifyAssigned(ctx, s.notifier, result.Assignments, reasonDeactivated)
 	return result, nil
 }
+
+// NotifyAssigned queues one notification per PR for the reviewers added by a reassignment.
+func NotifyAssigned(ctx context.Context, queue NotificationQueue, assignments []Assignment, reason string) {
+	byPR := make(map[string][]string)
+	var prIDs []string
+	for _, a := range assignments {
+		if _, ok := byPR[a.PullRequestID]; !ok {
+			prIDs = append(prIDs, a.PullRequestID)
+		}
+		byPR[a.PullRequestID] = append(byPR[a.PullRequestID], a.UserID)
+	}
+	for _, prID := range prIDs {
+		queue.Notify(ctx, notify.Notification{
+			Event:         notify.EventAssigned,
+			PullRequestID: prID,
+			RecipientIDs:  byPR[prID],
+			Reason:        reason,
+		})
+	}
+}
+
+func (s *Service) AddAwayPeriod(ctx context.Context, period model.AwayPeriod) (*model.AwayPeriod, error) {
+	log := s.log.With("op", "AddAwayPeriod", "user_id", period.UserID)
+
+	if period.StartsAt.IsZero() || !period.EndsAt.After(period.StartsAt) {
+		return nil, ErrBadPeriod
+	}
+	if _, err := s.repo.GetByID(ctx, period.UserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		log.ErrorContext(ctx, "failed to get user", "error", err)
+		return nil, err
+	}
+	period.CreatedAt = time.Now()
+	if err := s.repo.CreateAwayPeriod(ctx, &period); err != nil {
+		log.ErrorContext(ctx, "failed to create away period", "error", err)
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "away period added", "period_id", period.ID, "starts_at", period.StartsAt, "ends_at", period.EndsAt)
+	return &period, nil
+}
+
+func (s *Service) ListAwayPeriods(ctx context.Context, userID string) ([]model.AwayPeriod, error) {
+	if _, err := s.repo.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		s.log.ErrorContext(ctx, "failed to get user", "op", "ListAwayPeriods", "user_id", userID, "error", err)
+		return nil, err
+	}
+	periods, err := s.repo.ListAwayPeriods(ctx, userID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to list away periods", "op", "ListAwayPeriods", "user_id", userID, "error", err)
+		return nil, err
+	}
+	return periods, nil
+}
+
+func (s *Service) DeleteAwayPeriod(ctx context.Context, id uint64) error {
+	if err := s.repo.DeleteAwayPeriod(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPeriodNotFound
+		}
+		s.log.ErrorContext(ctx, "failed to delete away period", "op", "DeleteAwayPeriod", "period_id", id, "error", err)
+		return err
+	}
+	s.log.InfoContext(ctx, "away period deleted", "op", "DeleteAwayPeriod", "period_id", id)
+	return nil
+}
@@ -3,11 +3,14 @@ package user
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"testing"
+	"time"
 
 	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -27,6 +30,22 @@ func (m *MockStorer) UpdateActiveStatus(ctx context.Context, userID string, isAc
 	return nil, args.Error(1)
 }
 
+func (m *MockStorer) UpdateWorkingHours(ctx context.Context, userID string, hours WorkingHours) (*model.User, error) {
+	args := m.Called(ctx, userID, hours)
+	if val, ok := args.Get(0).(*model.User); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStorer) UpdateProfile(ctx context.Context, userID string, upd ProfileUpdate) (*model.User, error) {
+	args := m.Called(ctx, userID, upd)
+	if val, ok := args.Get(0).(*model.User); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockStorer) GetUserReviews(ctx context.Context, userID string) ([]model.PullRequest, error) {
 	args := m.Called(ctx, userID)
 	if val, ok := args.Get(0).([]model.PullRequest); ok {
@@ -43,25 +62,33 @@ func (m *MockStorer) GetByID(ctx context.Context, id string) (*model.User, error
 	return nil, args.Error(1)
 }
 
+func (m *MockStorer) List(ctx context.Context, filter ListFilter, after string, limit int) ([]model.User, error) {
+	args := m.Called(ctx, filter, after, limit)
+	if val, ok := args.Get(0).([]model.User); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockStorer) GetReviewCandidates(
 	ctx context.Context,
 	teamName string,
-	excludeUserID string,
+	excludeUserIDs []string,
 ) ([]model.User, error) {
-	args := m.Called(ctx, teamName, excludeUserID)
+	args := m.Called(ctx, teamName, excludeUserIDs)
 	if val, ok := args.Get(0).([]model.User); ok {
 		return val, args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-func (m *MockStorer) GetReplacementCandidate(
+func (m *MockStorer) GetActiveUsers(
 	ctx context.Context,
-	teamName string,
+	userIDs []string,
 	excludeUserIDs []string,
-) (*model.User, error) {
-	args := m.Called(ctx, teamName, excludeUserIDs)
-	if val, ok := args.Get(0).(*model.User); ok {
+) ([]model.User, error) {
+	args := m.Called(ctx, userIDs, excludeUserIDs)
+	if val, ok := args.Get(0).([]model.User); ok {
 		return val, args.Error(1)
 	}
 	return nil, args.Error(1)
@@ -71,17 +98,55 @@ func (m *MockStorer) MassDeactivateAndReassign(
 	ctx context.Context,
 	teamName string,
 	userIDs []string,
+	dryRun bool,
 ) (MassDeactivateResult, error) {
-	args := m.Called(ctx, teamName, userIDs)
+	args := m.Called(ctx, teamName, userIDs, dryRun)
 	return args.Get(0).(MassDeactivateResult), args.Error(1)
 }
 
+func (m *MockStorer) CreateAwayPeriod(ctx context.Context, period *model.AwayPeriod) error {
+	args := m.Called(ctx, period)
+	return args.Error(0)
+}
+
+func (m *MockStorer) ListAwayPeriods(ctx context.Context, userID string) ([]model.AwayPeriod, error) {
+	args := m.Called(ctx, userID)
+	if val, ok := args.Get(0).([]model.AwayPeriod); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStorer) DeleteAwayPeriod(ctx context.Context, id uint64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockStorer) ReassignAwayReviews(ctx context.Context, now time.Time) (AwayReassignResult, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(AwayReassignResult), args.Error(1)
+}
+
+type recordingQueue struct {
+	sent []notify.Notification
+}
+
+func (q *recordingQueue) Notify(_ context.Context, n notify.Notification) {
+	q.sent = append(q.sent, n)
+}
+
 func setupService() (*Service, *MockStorer) {
+	svc, mockRepo, _ := setupServiceWithQueue()
+	return svc, mockRepo
+}
+
+func setupServiceWithQueue() (*Service, *MockStorer, *recordingQueue) {
 	mockRepo := new(MockStorer)
+	queue := new(recordingQueue)
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 
-	svc := NewService(mockRepo, logger)
-	return svc, mockRepo
+	svc := NewService(mockRepo, queue, logger)
+	return svc, mockRepo, queue
 }
 
 func TestService_SetIsActive(t *testing.T) {
@@ -155,9 +220,9 @@ func TestService_GetReviewCandidates(t *testing.T) {
 		svc, mockRepo := setupService()
 		expectedUsers := []model.User{{ID: "u2"}, {ID: "u3"}}
 
-		mockRepo.On("GetReviewCandidates", ctx, "TeamA", "u1").Return(expectedUsers, nil)
+		mockRepo.On("GetReviewCandidates", ctx, "TeamA", []string{"u1"}).Return(expectedUsers, nil)
 
-		res, err := svc.GetReviewCandidates(ctx, "TeamA", "u1")
+		res, err := svc.GetReviewCandidates(ctx, "TeamA", []string{"u1"})
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedUsers, res)
@@ -165,9 +230,9 @@ func TestService_GetReviewCandidates(t *testing.T) {
 
 	t.Run("db error", func(t *testing.T) {
 		svc, mockRepo := setupService()
-		mockRepo.On("GetReviewCandidates", ctx, "TeamA", "u1").Return(nil, errors.New("db fail"))
+		mockRepo.On("GetReviewCandidates", ctx, "TeamA", []string{"u1"}).Return(nil, errors.New("db fail"))
 
-		_, err := svc.GetReviewCandidates(ctx, "TeamA", "u1")
+		_, err := svc.GetReviewCandidates(ctx, "TeamA", []string{"u1"})
 
 		assert.Error(t, err)
 	})
@@ -199,31 +264,61 @@ func TestService_GetByID(t *testing.T) {
 	})
 }
 
-func TestService_GetReplacementCandidate(t *testing.T) {
+func TestService_Get(t *testing.T) {
 	ctx := context.Background()
 
-	t.Run("success", func(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
 		svc, mockRepo := setupService()
-		excludes := []string{"u1", "u2"}
-		candidate := &model.User{ID: "u3"}
 
-		mockRepo.On("GetReplacementCandidate", ctx, "TeamA", excludes).Return(candidate, nil)
+		mockRepo.On("GetByID", ctx, "u1").Return(nil, gorm.ErrRecordNotFound)
 
-		res, err := svc.GetReplacementCandidate(ctx, "TeamA", excludes)
+		_, err := svc.Get(ctx, "u1")
 
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
+
+func TestService_List(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("default limit and next cursor", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		users := make([]model.User, DefaultPageSize+1)
+		for i := range users {
+			users[i] = model.User{ID: fmt.Sprintf("u%03d", i)}
+		}
+		filter := ListFilter{TeamName: "backend"}
+
+		mockRepo.On("List", ctx, filter, "", DefaultPageSize+1).Return(users, nil)
+
+		page, err := svc.List(ctx, filter)
+
+		require.NoError(t, err)
+		assert.Len(t, page.Users, DefaultPageSize)
+		after, err := decodeCursor(page.NextCursor)
 		require.NoError(t, err)
-		assert.Equal(t, candidate, res)
+		assert.Equal(t, users[DefaultPageSize-1].ID, after)
 	})
 
-	t.Run("not found returns original error", func(t *testing.T) {
+	t.Run("cursor and capped limit", func(t *testing.T) {
 		svc, mockRepo := setupService()
-		excludes := []string{"u1"}
+		filter := ListFilter{Cursor: encodeCursor("u5"), Limit: MaxPageSize * 2}
 
-		mockRepo.On("GetReplacementCandidate", ctx, "TeamA", excludes).Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.On("List", ctx, filter, "u5", MaxPageSize+1).Return([]model.User{{ID: "u6"}}, nil)
 
-		_, err := svc.GetReplacementCandidate(ctx, "TeamA", excludes)
+		page, err := svc.List(ctx, filter)
 
-		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		require.NoError(t, err)
+		assert.Len(t, page.Users, 1)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("bad cursor", func(t *testing.T) {
+		svc, _ := setupService()
+
+		_, err := svc.List(ctx, ListFilter{Cursor: "!!"})
+
+		assert.ErrorIs(t, err, ErrBadCursor)
 	})
 }
 
@@ -238,24 +333,320 @@ func TestService_MassDeactivate(t *testing.T) {
 			ReassignedCount:  5,
 		}
 
-		mockRepo.On("MassDeactivateAndReassign", ctx, "TeamA", ids).Return(expectedResult, nil)
+		mockRepo.On("MassDeactivateAndReassign", ctx, "TeamA", ids, false).Return(expectedResult, nil)
 
-		res, err := svc.MassDeactivate(ctx, "TeamA", ids)
+		res, err := svc.MassDeactivate(ctx, "TeamA", ids, false)
 
 		require.NoError(t, err)
 		assert.Equal(t, 2, res.DeactivatedCount)
 		assert.Equal(t, 5, res.ReassignedCount)
 	})
 
+	t.Run("notifies new reviewers per pr", func(t *testing.T) {
+		svc, mockRepo, queue := setupServiceWithQueue()
+		ids := []string{"u1"}
+		result := MassDeactivateResult{
+			DeactivatedCount: 1,
+			ReassignedCount:  2,
+			Assignments: []Assignment{
+				{PullRequestID: "pr-1", UserID: "u2"},
+				{PullRequestID: "pr-2", UserID: "u3"},
+				{PullRequestID: "pr-1", UserID: "u3"},
+			},
+		}
+		mockRepo.On("MassDeactivateAndReassign", ctx, "TeamA", ids, false).Return(result, nil)
+
+		_, err := svc.MassDeactivate(ctx, "TeamA", ids, false)
+
+		require.NoError(t, err)
+		require.Len(t, queue.sent, 2)
+		assert.Equal(t, "pr-1", queue.sent[0].PullRequestID)
+		assert.Equal(t, []string{"u2", "u3"}, queue.sent[0].RecipientIDs)
+		assert.Equal(t, notify.EventAssigned, queue.sent[0].Event)
+		assert.Equal(t, "pr-2", queue.sent[1].PullRequestID)
+		assert.Equal(t, []string{"u3"}, queue.sent[1].RecipientIDs)
+	})
+
+	t.Run("dry run does not notify", func(t *testing.T) {
+		svc, mockRepo, queue := setupServiceWithQueue()
+		ids := []string{"u1"}
+		result := MassDeactivateResult{
+			DeactivatedCount: 1,
+			ReassignedCount:  1,
+			DryRun:           true,
+			Assignments:      []Assignment{{PullRequestID: "pr-1", UserID: "u2"}},
+			PullRequests: []PRChange{
+				{PullRequestID: "pr-1", TeamName: "TeamA", Removed: []string{"u1"}, Added: []string{"u2"}},
+			},
+		}
+		mockRepo.On("MassDeactivateAndReassign", ctx, "TeamA", ids, true).Return(result, nil)
+
+		res, err := svc.MassDeactivate(ctx, "TeamA", ids, true)
+
+		require.NoError(t, err)
+		assert.Equal(t, result, res)
+		assert.Empty(t, queue.sent)
+	})
+
 	t.Run("error", func(t *testing.T) {
 		svc, mockRepo := setupService()
 		ids := []string{"u1"}
 
-		mockRepo.On("MassDeactivateAndReassign", ctx, "TeamA", ids).
+		mockRepo.On("MassDeactivateAndReassign", ctx, "TeamA", ids, false).
 			Return(MassDeactivateResult{}, errors.New("tx failed"))
 
-		_, err := svc.MassDeactivate(ctx, "TeamA", ids)
+		_, err := svc.MassDeactivate(ctx, "TeamA", ids, false)
 
 		assert.Error(t, err)
 	})
 }
+
+func TestCalculateReplacements(t *testing.T) {
+	repo := &Repository{}
+
+	t.Run("spreads reviews evenly", func(t *testing.T) {
+		plan := replacementPlan{deactivated: []string{"u1"}, required: 1, reviewers: map[string][]string{}}
+		for i := range 4 {
+			id := fmt.Sprintf("pr-%d", i)
+			plan.rows = append(plan.rows, affectedPR{PRID: id, AuthorID: "author", OldReviewerID: "u1"})
+			plan.reviewers[id] = []string{"u1"}
+		}
+		candidates := []model.User{{ID: "u2"}, {ID: "u3"}}
+
+		result := repo.calculateReplacements(plan, candidates, map[string]int{})
+
+		perUser := map[string]int{}
+		for _, rel := range result.added {
+			perUser[rel.UserID]++
+		}
+		assert.Equal(t, map[string]int{"u2": 2, "u3": 2}, perUser)
+		assert.Equal(t, 4, result.reassigned)
+		assert.Empty(t, result.missing)
+	})
+
+	t.Run("skips existing reviewers and removed users", func(t *testing.T) {
+		plan := replacementPlan{
+			rows:        []affectedPR{{PRID: "pr-1", AuthorID: "author", OldReviewerID: "u1"}},
+			reviewers:   map[string][]string{"pr-1": {"u1", "u2"}},
+			deactivated: []string{"u1", "u4"},
+			required:    2,
+		}
+		candidates := []model.User{{ID: "author"}, {ID: "u2"}, {ID: "u4"}, {ID: "u3"}}
+
+		result := repo.calculateReplacements(plan, candidates, map[string]int{"u3": 10})
+
+		assert.Equal(t, []prReviewer{{PullRequestID: "pr-1", UserID: "u3"}}, result.added)
+		assert.Empty(t, result.missing)
+	})
+
+	t.Run("reports prs left short", func(t *testing.T) {
+		plan := replacementPlan{
+			rows: []affectedPR{
+				{PRID: "pr-1", AuthorID: "author", OldReviewerID: "u1"},
+				{PRID: "pr-2", AuthorID: "u2", OldReviewerID: "u1"},
+			},
+			reviewers:   map[string][]string{"pr-1": {"u1"}, "pr-2": {"u1"}},
+			deactivated: []string{"u1"},
+			required:    2,
+		}
+		candidates := []model.User{{ID: "u2"}}
+
+		result := repo.calculateReplacements(plan, candidates, map[string]int{})
+
+		assert.Equal(t, []prReviewer{{PullRequestID: "pr-1", UserID: "u2"}}, result.added)
+		assert.Equal(t, map[string]int{"pr-1": 1, "pr-2": 2}, result.missing)
+
+		result = repo.calculateReplacements(plan, nil, map[string]int{})
+		assert.Empty(t, result.added)
+		assert.Equal(t, map[string]int{"pr-1": 2, "pr-2": 2}, result.missing)
+	})
+
+	t.Run("falls back level by level", func(t *testing.T) {
+		plan := replacementPlan{
+			rows:        []affectedPR{{PRID: "pr-1", AuthorID: "author", OldReviewerID: "u1"}},
+			reviewers:   map[string][]string{"pr-1": {"u1"}},
+			deactivated: []string{"u1"},
+			required:    3,
+			fallback: [][]model.User{
+				{{ID: "lead"}, {ID: "u2"}},
+				{{ID: "pool-1"}, {ID: "pool-2"}},
+			},
+		}
+
+		result := repo.calculateReplacements(plan, []model.User{{ID: "u2"}}, map[string]int{"pool-2": 1})
+
+		assert.Equal(t, []prReviewer{
+			{PullRequestID: "pr-1", UserID: "u2"},
+			{PullRequestID: "pr-1", UserID: "lead"},
+			{PullRequestID: "pr-1", UserID: "pool-1"},
+		}, result.added)
+		assert.Empty(t, result.missing)
+	})
+}
+
+func TestService_AddAwayPeriod(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		period := model.AwayPeriod{UserID: "u1", StartsAt: start, EndsAt: start.Add(48 * time.Hour), Reason: "vacation"}
+
+		mockRepo.On("GetByID", ctx, "u1").Return(&model.User{ID: "u1"}, nil)
+		mockRepo.On("CreateAwayPeriod", ctx, mock.MatchedBy(func(p *model.AwayPeriod) bool {
+			return p.UserID == "u1" && p.Reason == "vacation" && !p.CreatedAt.IsZero()
+		})).Return(nil)
+
+		res, err := svc.AddAwayPeriod(ctx, period)
+
+		require.NoError(t, err)
+		assert.Equal(t, "u1", res.UserID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("ends before start", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		period := model.AwayPeriod{UserID: "u1", StartsAt: start, EndsAt: start}
+
+		_, err := svc.AddAwayPeriod(ctx, period)
+
+		assert.ErrorIs(t, err, ErrBadPeriod)
+		mockRepo.AssertNotCalled(t, "CreateAwayPeriod")
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		period := model.AwayPeriod{UserID: "missing", StartsAt: start, EndsAt: start.Add(time.Hour)}
+
+		mockRepo.On("GetByID", ctx, "missing").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.AddAwayPeriod(ctx, period)
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+		mockRepo.AssertNotCalled(t, "CreateAwayPeriod")
+	})
+}
+
+func TestService_DeleteAwayPeriod(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		mockRepo.On("DeleteAwayPeriod", ctx, uint64(7)).Return(nil)
+
+		require.NoError(t, svc.DeleteAwayPeriod(ctx, 7))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		mockRepo.On("DeleteAwayPeriod", ctx, uint64(7)).Return(gorm.ErrRecordNotFound)
+
+		assert.ErrorIs(t, svc.DeleteAwayPeriod(ctx, 7), ErrPeriodNotFound)
+	})
+}
+
+func TestService_SetWorkingHours(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		hours := WorkingHours{Timezone: "Asia/Novosibirsk", WorkStart: "09:00", WorkEnd: "18:00"}
+
+		mockRepo.On("UpdateWorkingHours", ctx, "u1", hours).Return(&model.User{ID: "u1", Timezone: hours.Timezone}, nil)
+
+		res, err := svc.SetWorkingHours(ctx, "u1", hours)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Asia/Novosibirsk", res.Timezone)
+	})
+
+	t.Run("normalizes work days", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		hours := WorkingHours{WorkStart: "09:00", WorkEnd: "18:00", WorkDays: []string{"SUN", "tue", "Sun", "mon"}}
+		stored := WorkingHours{WorkStart: "09:00", WorkEnd: "18:00", WorkDays: []string{"mon", "tue", "sun"}}
+
+		mockRepo.On("UpdateWorkingHours", ctx, "u1", stored).Return(&model.User{ID: "u1", WorkDays: stored.WorkDays}, nil)
+
+		res, err := svc.SetWorkingHours(ctx, "u1", hours)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"mon", "tue", "sun"}, res.WorkDays)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		for _, hours := range []WorkingHours{
+			{Timezone: "Mars/Olympus"},
+			{WorkStart: "09:00"},
+			{WorkStart: "9am", WorkEnd: "18:00"},
+			{WorkDays: []string{"mon"}},
+			{WorkStart: "09:00", WorkEnd: "18:00", WorkDays: []string{"monday"}},
+		} {
+			_, err := svc.SetWorkingHours(ctx, "u1", hours)
+			assert.ErrorIs(t, err, ErrBadWorkingHours)
+		}
+		mockRepo.AssertNotCalled(t, "UpdateWorkingHours")
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		mockRepo.On("UpdateWorkingHours", ctx, "missing", WorkingHours{}).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.SetWorkingHours(ctx, "missing", WorkingHours{})
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
+
+func TestService_UpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	email := "db@example.com"
+
+	t.Run("normalizes tags", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		expected := ProfileUpdate{Email: &email, Tags: []string{"database", "infra"}}
+
+		mockRepo.On("UpdateProfile", ctx, "u1", expected).Return(&model.User{ID: "u1", Tags: expected.Tags}, nil)
+
+		res, err := svc.UpdateProfile(ctx, "u1", ProfileUpdate{Email: &email, Tags: []string{" Database", "infra", "", "DATABASE"}})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"database", "infra"}, res.Tags)
+	})
+
+	t.Run("empty list clears tags", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		mockRepo.On("UpdateProfile", ctx, "u1", ProfileUpdate{Tags: []string{}}).Return(&model.User{ID: "u1"}, nil)
+
+		_, err := svc.UpdateProfile(ctx, "u1", ProfileUpdate{Tags: []string{}})
+
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		svc, mockRepo := setupService()
+		blank, badEmail := " ", "not an email"
+
+		_, err := svc.UpdateProfile(ctx, "u1", ProfileUpdate{Username: &blank})
+		assert.ErrorIs(t, err, ErrBadProfile)
+		_, err = svc.UpdateProfile(ctx, "u1", ProfileUpdate{Email: &badEmail})
+		assert.ErrorIs(t, err, ErrBadProfile)
+		mockRepo.AssertNotCalled(t, "UpdateProfile")
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		svc, mockRepo := setupService()
+
+		mockRepo.On("UpdateProfile", ctx, "missing", ProfileUpdate{}).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.UpdateProfile(ctx, "missing", ProfileUpdate{})
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
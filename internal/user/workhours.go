@@ -0,0 +1,48 @@
+package user
+
+import (
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/workhours"
+
+	"gorm.io/gorm"
+)
+
+// MarkOnDuty sets OnDuty for users who are within their working hours at t and whose team
+// has no holiday on their local date.
+func MarkOnDuty(db *gorm.DB, users []model.User, t time.Time) error {
+	if len(users) == 0 {
+		return nil
+	}
+	teams := make([]string, 0, len(users))
+	for _, u := range users {
+		teams = append(teams, u.TeamName)
+	}
+	// Local dates are at most one day away from UTC.
+	days := []string{
+		t.UTC().AddDate(0, 0, -1).Format(time.DateOnly),
+		t.UTC().Format(time.DateOnly),
+		t.UTC().AddDate(0, 0, 1).Format(time.DateOnly),
+	}
+
+	var rows []model.TeamHoliday
+	err := db.Session(&gorm.Session{NewDB: true}).
+		Where("team_name IN ? AND day IN ?", teams, days).
+		Find(&rows).Error
+	if err != nil {
+		return err
+	}
+	holidays := make(map[string]map[string]bool, len(rows))
+	for _, h := range rows {
+		if holidays[h.TeamName] == nil {
+			holidays[h.TeamName] = make(map[string]bool)
+		}
+		holidays[h.TeamName][h.Day] = true
+	}
+
+	for i := range users {
+		users[i].OnDuty = workhours.For(&users[i]).OnDuty(t, holidays[users[i].TeamName])
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+)
+
+// Dispatcher moves outbox messages to subscribers and retries failed deliveries
+// with exponential backoff until they succeed or run out of attempts.
+type Dispatcher struct {
+	repo   DeliveryStorer
+	client *http.Client
+	conf   configs.Webhooks
+	log    *slog.Logger
+}
+
+func NewDispatcher(repo DeliveryStorer, conf configs.Webhooks, log *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		repo:   repo,
+		client: &http.Client{Timeout: conf.RequestTimeout},
+		conf:   conf,
+		log:    log.With("component", "webhookDispatcher"),
+	}
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.conf.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.DispatchOnce(ctx); err != nil && ctx.Err() == nil {
+			d.log.ErrorContext(ctx, "dispatch failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) DispatchOnce(ctx context.Context) error {
+	now := time.Now()
+	if _, err := d.repo.FanOut(ctx, now, d.conf.BatchSize); err != nil {
+		return err
+	}
+	jobs, err := d.repo.ClaimDue(ctx, now, 2*d.conf.RequestTimeout, d.conf.BatchSize)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if err = d.deliver(ctx, job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, job Job) error {
+	log := d.log.With("delivery_id", job.DeliveryID, "url", job.URL)
+
+	attempts := job.Attempts + 1
+	sendErr := d.send(ctx, job)
+	now := time.Now()
+	if sendErr == nil {
+		return d.repo.MarkDelivered(ctx, job.DeliveryID, attempts, now)
+	}
+
+	dead := attempts >= d.conf.MaxAttempts
+	if dead {
+		log.WarnContext(ctx, "delivery moved to dead letter", "attempts", attempts, "error", sendErr)
+	} else {
+		log.InfoContext(ctx, "delivery failed, will retry", "attempts", attempts, "error", sendErr)
+	}
+	return d.repo.MarkFailed(ctx, job.DeliveryID, attempts, now.Add(backoff(d.conf, attempts)), dead, sendErr.Error())
+}
+
+func (d *Dispatcher) send(ctx context.Context, job Job) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.URL, strings.NewReader(job.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, job.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(job.DeliveryID, 10))
+	req.Header.Set(SignatureHeader, Sign(job.Secret, []byte(job.Payload)))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the value of the signature header: "sha256=" followed by the
+// hex-encoded HMAC-SHA256 of body keyed with the subscription secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func backoff(conf configs.Webhooks, attempts int) time.Duration {
+	delay := conf.BaseBackoff
+	for i := 1; i < attempts && delay < conf.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, conf.MaxBackoff)
+}
@@ -0,0 +1,29 @@
+package webhook
+
+import "time"
+
+type RegisterRequestDTO struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+}
+
+type DeleteRequestDTO struct {
+	SubscriptionID string `json:"subscription_id"`
+}
+
+type RegisterResponseDTO struct {
+	Subscription SubscriptionDTO `json:"subscription"`
+	Secret       string          `json:"secret"`
+}
+
+type ListResponseDTO struct {
+	Subscriptions []SubscriptionDTO `json:"subscriptions"`
+}
+
+type SubscriptionDTO struct {
+	SubscriptionID string    `json:"subscription_id"`
+	URL            string    `json:"url"`
+	EventTypes     []string  `json:"event_types"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
@@ -0,0 +1,9 @@
+package webhook
+
+import "errors"
+
+var (
+	ErrBadURL               = errors.New("url must be an absolute http or https URL")
+	ErrBadEventType         = errors.New("unknown event type")
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+)
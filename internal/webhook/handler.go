@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/req"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/res"
+)
+
+type Handler struct {
+	webhookService Provider
+	conf           *configs.Config
+}
+
+func NewHandler(router *http.ServeMux, webhookService Provider, conf *configs.Config) {
+	handler := &Handler{
+		webhookService: webhookService,
+		conf:           conf,
+	}
+	router.HandleFunc("POST /webhooks/register", handler.Register())
+	router.HandleFunc("GET /webhooks/list", handler.List())
+	router.HandleFunc("POST /webhooks/delete", handler.Delete())
+}
+
+func (h *Handler) Register() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		reqBody, err := req.HandleBody[RegisterRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+		if reqBody.URL == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "url is required")
+			return
+		}
+
+		sub, err := h.webhookService.Register(ctx, ToDomain(*reqBody))
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrBadURL), errors.Is(err, ErrBadEventType):
+				res.Error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		resp := ToRegisterResponse(sub)
+		res.JSON(w, http.StatusCreated, resp)
+	}
+}
+
+func (h *Handler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+
+		subs, err := h.webhookService.List(ctx)
+		if err != nil {
+			res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+			return
+		}
+		resp := ToListResponse(subs)
+		res.JSON(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), h.conf.App.TimeOut)
+		defer cancel()
+		reqBody, err := req.HandleBody[DeleteRequestDTO](r)
+		if err != nil {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+			return
+		}
+		if reqBody.SubscriptionID == "" {
+			res.Error(w, http.StatusBadRequest, "BAD_REQUEST", "subscription_id is required")
+			return
+		}
+
+		if err = h.webhookService.Delete(ctx, reqBody.SubscriptionID); err != nil {
+			switch {
+			case errors.Is(err, ErrSubscriptionNotFound):
+				res.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+				return
+			default:
+				res.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "unknown error")
+				return
+			}
+		}
+		res.JSON(w, http.StatusNoContent, nil)
+	}
+}
@@ -0,0 +1,27 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+type Storer interface {
+	CreateSubscription(context.Context, *model.WebhookSubscription) error
+	ListSubscriptions(context.Context) ([]model.WebhookSubscription, error)
+	DeleteSubscription(context.Context, string) error
+}
+
+type DeliveryStorer interface {
+	FanOut(ctx context.Context, now time.Time, limit int) (int, error)
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Job, error)
+	MarkDelivered(ctx context.Context, deliveryID uint64, attempts int, at time.Time) error
+	MarkFailed(ctx context.Context, deliveryID uint64, attempts int, next time.Time, dead bool, reason string) error
+}
+
+type Provider interface {
+	Register(context.Context, model.WebhookSubscription) (*model.WebhookSubscription, error)
+	List(context.Context) ([]model.WebhookSubscription, error)
+	Delete(context.Context, string) error
+}
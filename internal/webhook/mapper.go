@@ -0,0 +1,39 @@
+package webhook
+
+import "github.com/SeeXWH/pr-reviewer-service/internal/model"
+
+func ToDomain(req RegisterRequestDTO) model.WebhookSubscription {
+	return model.WebhookSubscription{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: joinEventTypes(req.EventTypes),
+	}
+}
+
+func ToSubscriptionDTO(sub *model.WebhookSubscription) SubscriptionDTO {
+	eventTypes := splitEventTypes(sub.EventTypes)
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return SubscriptionDTO{
+		SubscriptionID: sub.ID,
+		URL:            sub.URL,
+		EventTypes:     eventTypes,
+		CreatedAt:      sub.CreatedAt,
+	}
+}
+
+func ToRegisterResponse(sub *model.WebhookSubscription) RegisterResponseDTO {
+	return RegisterResponseDTO{
+		Subscription: ToSubscriptionDTO(sub),
+		Secret:       sub.Secret,
+	}
+}
+
+func ToListResponse(subs []model.WebhookSubscription) ListResponseDTO {
+	items := make([]SubscriptionDTO, 0, len(subs))
+	for i := range subs {
+		items = append(items, ToSubscriptionDTO(&subs[i]))
+	}
+	return ListResponseDTO{Subscriptions: items}
+}
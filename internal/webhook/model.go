@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	DeliveryPending   = "PENDING"
+	DeliveryDelivered = "DELIVERED"
+	DeliveryDead      = "DEAD"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature-256"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Job is a claimed delivery together with everything needed to send it.
+type Job struct {
+	DeliveryID uint64
+	Attempts   int
+	URL        string
+	Secret     string
+	EventType  string
+	Payload    string
+}
+
+func splitEventTypes(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	return strings.Split(raw, ",")
+}
+
+// subscribed reports whether a subscription with the given event type filter wants eventType.
+// An empty filter means all events.
+func subscribed(eventTypes string, eventType string) bool {
+	types := splitEventTypes(eventTypes)
+	return len(types) == 0 || slices.Contains(types, eventType)
+}
@@ -0,0 +1,169 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository struct {
+	db *db.PostgresDB
+}
+
+func NewRepository(db *db.PostgresDB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateSubscription(ctx context.Context, sub *model.WebhookSubscription) error {
+	return r.db.PostgresDB.WithContext(ctx).Create(sub).Error
+}
+
+func (r *Repository) ListSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	var subs []model.WebhookSubscription
+	err := r.db.PostgresDB.WithContext(ctx).Order("created_at").Find(&subs).Error
+	if err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (r *Repository) DeleteSubscription(ctx context.Context, id string) error {
+	return r.db.PostgresDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(&model.WebhookSubscription{}, "subscription_id = ?", id)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("subscription_id = ? AND status = ?", id, DeliveryPending).
+			Delete(&model.WebhookDelivery{}).Error
+	})
+}
+
+// FanOut turns undispatched outbox messages into one pending delivery per matching subscription.
+func (r *Repository) FanOut(ctx context.Context, now time.Time, limit int) (int, error) {
+	created := 0
+	err := r.db.PostgresDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var messages []model.OutboxMessage
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("dispatched_at IS NULL").
+			Order("id").
+			Limit(limit).
+			Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		var subs []model.WebhookSubscription
+		if err = tx.Find(&subs).Error; err != nil {
+			return err
+		}
+
+		ids := make([]uint64, 0, len(messages))
+		var deliveries []model.WebhookDelivery
+		for _, msg := range messages {
+			ids = append(ids, msg.ID)
+			for _, sub := range subs {
+				if !subscribed(sub.EventTypes, msg.EventType) {
+					continue
+				}
+				deliveries = append(deliveries, model.WebhookDelivery{
+					OutboxID:       msg.ID,
+					SubscriptionID: sub.ID,
+					Status:         DeliveryPending,
+					NextAttemptAt:  now,
+					CreatedAt:      now,
+				})
+			}
+		}
+		if len(deliveries) > 0 {
+			if err = tx.Create(&deliveries).Error; err != nil {
+				return err
+			}
+		}
+		created = len(deliveries)
+
+		return tx.Model(&model.OutboxMessage{}).
+			Where("id IN ?", ids).
+			Update("dispatched_at", now).Error
+	})
+	return created, err
+}
+
+// ClaimDue picks pending deliveries that are due and pushes their next attempt
+// forward by lease, so that concurrent dispatchers do not send them twice.
+func (r *Repository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Job, error) {
+	var jobs []Job
+	err := r.db.PostgresDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uint64
+		err := tx.Model(&model.WebhookDelivery{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", DeliveryPending, now).
+			Order("next_attempt_at").
+			Order("id").
+			Limit(limit).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		err = tx.Model(&model.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Table("webhook_deliveries").
+			Select("webhook_deliveries.id AS delivery_id, webhook_deliveries.attempts, "+
+				"webhook_subscriptions.url, webhook_subscriptions.secret, "+
+				"outbox_messages.event_type, outbox_messages.payload").
+			Joins("JOIN webhook_subscriptions ON webhook_subscriptions.subscription_id = webhook_deliveries.subscription_id").
+			Joins("JOIN outbox_messages ON outbox_messages.id = webhook_deliveries.outbox_id").
+			Where("webhook_deliveries.id IN ?", ids).
+			Order("webhook_deliveries.id").
+			Scan(&jobs).Error
+	})
+	return jobs, err
+}
+
+func (r *Repository) MarkDelivered(ctx context.Context, deliveryID uint64, attempts int, at time.Time) error {
+	return r.db.PostgresDB.WithContext(ctx).
+		Model(&model.WebhookDelivery{}).
+		Where("id = ?", deliveryID).
+		Updates(map[string]any{
+			"status":       DeliveryDelivered,
+			"attempts":     attempts,
+			"delivered_at": at,
+			"last_error":   "",
+		}).Error
+}
+
+func (r *Repository) MarkFailed(
+	ctx context.Context,
+	deliveryID uint64,
+	attempts int,
+	next time.Time,
+	dead bool,
+	reason string,
+) error {
+	status := DeliveryPending
+	if dead {
+		status = DeliveryDead
+	}
+	return r.db.PostgresDB.WithContext(ctx).
+		Model(&model.WebhookDelivery{}).
+		Where("id = ?", deliveryID).
+		Updates(map[string]any{
+			"status":          status,
+			"attempts":        attempts,
+			"next_attempt_at": next,
+			"last_error":      reason,
+		}).Error
+}
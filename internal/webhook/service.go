@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/event"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type Service struct {
+	repo Storer
+	log  *slog.Logger
+}
+
+func NewService(repo Storer, log *slog.Logger) *Service {
+	return &Service{
+		repo: repo,
+		log:  log.With("component", "webhookService"),
+	}
+}
+
+func (s *Service) Register(ctx context.Context, sub model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	log := s.log.With("op", "Register", "url", sub.URL)
+
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrBadURL
+	}
+	for _, t := range splitEventTypes(sub.EventTypes) {
+		if !event.Known(t) {
+			return nil, ErrBadEventType
+		}
+	}
+
+	sub.ID = "wh_" + randomHex(8)
+	if sub.Secret == "" {
+		sub.Secret = randomHex(32)
+	}
+	sub.CreatedAt = time.Now()
+	if err = s.repo.CreateSubscription(ctx, &sub); err != nil {
+		log.ErrorContext(ctx, "failed to create subscription", "error", err)
+		return nil, err
+	}
+
+	log.InfoContext(ctx, "webhook registered", "subscription_id", sub.ID, "event_types", sub.EventTypes)
+	return &sub, nil
+}
+
+func (s *Service) List(ctx context.Context) ([]model.WebhookSubscription, error) {
+	subs, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to list subscriptions", "op", "List", "error", err)
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (s *Service) Delete(ctx context.Context, id string) error {
+	log := s.log.With("op", "Delete", "subscription_id", id)
+
+	if err := s.repo.DeleteSubscription(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSubscriptionNotFound
+		}
+		log.ErrorContext(ctx, "failed to delete subscription", "error", err)
+		return err
+	}
+
+	log.InfoContext(ctx, "webhook deleted")
+	return nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func joinEventTypes(types []string) string {
+	return strings.Join(types, ",")
+}
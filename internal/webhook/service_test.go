@@ -0,0 +1,235 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type MockStorer struct {
+	mock.Mock
+}
+
+func (m *MockStorer) CreateSubscription(ctx context.Context, sub *model.WebhookSubscription) error {
+	args := m.Called(ctx, sub)
+	return args.Error(0)
+}
+
+func (m *MockStorer) ListSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	args := m.Called(ctx)
+	if val, ok := args.Get(0).([]model.WebhookSubscription); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStorer) DeleteSubscription(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockDeliveryStorer struct {
+	mock.Mock
+}
+
+func (m *MockDeliveryStorer) FanOut(ctx context.Context, now time.Time, limit int) (int, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDeliveryStorer) ClaimDue(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]Job, error) {
+	args := m.Called(ctx, now, lease, limit)
+	if val, ok := args.Get(0).([]Job); ok {
+		return val, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDeliveryStorer) MarkDelivered(ctx context.Context, deliveryID uint64, attempts int, at time.Time) error {
+	args := m.Called(ctx, deliveryID, attempts, at)
+	return args.Error(0)
+}
+
+func (m *MockDeliveryStorer) MarkFailed(
+	ctx context.Context,
+	deliveryID uint64,
+	attempts int,
+	next time.Time,
+	dead bool,
+	reason string,
+) error {
+	args := m.Called(ctx, deliveryID, attempts, next, dead, reason)
+	return args.Error(0)
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func testConfig() configs.Webhooks {
+	return configs.Webhooks{
+		PollInterval:   time.Second,
+		RequestTimeout: time.Second,
+		MaxAttempts:    3,
+		BaseBackoff:    time.Second,
+		MaxBackoff:     3 * time.Second,
+		BatchSize:      10,
+	}
+}
+
+func TestService_Register(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("generates id and secret", func(t *testing.T) {
+		mockRepo := new(MockStorer)
+		svc := NewService(mockRepo, discardLogger())
+
+		mockRepo.On("CreateSubscription", ctx, mock.Anything).Return(nil)
+
+		sub, err := svc.Register(ctx, model.WebhookSubscription{URL: "https://example.com/hook", EventTypes: "CREATED"})
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, sub.ID)
+		assert.Len(t, sub.Secret, 64)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("keeps provided secret", func(t *testing.T) {
+		mockRepo := new(MockStorer)
+		svc := NewService(mockRepo, discardLogger())
+
+		mockRepo.On("CreateSubscription", ctx, mock.Anything).Return(nil)
+
+		sub, err := svc.Register(ctx, model.WebhookSubscription{URL: "http://hooks.local", Secret: "s3cret"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "s3cret", sub.Secret)
+	})
+
+	t.Run("rejects bad url", func(t *testing.T) {
+		mockRepo := new(MockStorer)
+		svc := NewService(mockRepo, discardLogger())
+
+		_, err := svc.Register(ctx, model.WebhookSubscription{URL: "ftp://example.com"})
+
+		require.ErrorIs(t, err, ErrBadURL)
+		mockRepo.AssertNotCalled(t, "CreateSubscription", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects unknown event type", func(t *testing.T) {
+		mockRepo := new(MockStorer)
+		svc := NewService(mockRepo, discardLogger())
+
+		_, err := svc.Register(ctx, model.WebhookSubscription{URL: "https://example.com", EventTypes: "CREATED,DELETED"})
+
+		require.ErrorIs(t, err, ErrBadEventType)
+	})
+}
+
+func TestService_Delete(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockStorer)
+	svc := NewService(mockRepo, discardLogger())
+
+	mockRepo.On("DeleteSubscription", ctx, "wh_missing").Return(gorm.ErrRecordNotFound)
+
+	err := svc.Delete(ctx, "wh_missing")
+
+	require.ErrorIs(t, err, ErrSubscriptionNotFound)
+}
+
+func TestDispatcher_DispatchOnce(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("delivers signed payload", func(t *testing.T) {
+		var gotSignature, gotEvent string
+		var gotBody []byte
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotSignature = r.Header.Get(SignatureHeader)
+			gotEvent = r.Header.Get(EventHeader)
+			gotBody, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer receiver.Close()
+
+		mockRepo := new(MockDeliveryStorer)
+		dispatcher := NewDispatcher(mockRepo, testConfig(), discardLogger())
+		job := Job{DeliveryID: 7, URL: receiver.URL, Secret: "key", EventType: "CREATED", Payload: `{"type":"CREATED"}`}
+
+		mockRepo.On("FanOut", ctx, mock.Anything, 10).Return(1, nil)
+		mockRepo.On("ClaimDue", ctx, mock.Anything, 2*time.Second, 10).Return([]Job{job}, nil)
+		mockRepo.On("MarkDelivered", ctx, uint64(7), 1, mock.Anything).Return(nil)
+
+		require.NoError(t, dispatcher.DispatchOnce(ctx))
+
+		assert.Equal(t, job.Payload, string(gotBody))
+		assert.Equal(t, "CREATED", gotEvent)
+		assert.Equal(t, Sign("key", gotBody), gotSignature)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("schedules retry with backoff", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer receiver.Close()
+
+		mockRepo := new(MockDeliveryStorer)
+		dispatcher := NewDispatcher(mockRepo, testConfig(), discardLogger())
+		job := Job{DeliveryID: 8, Attempts: 1, URL: receiver.URL, Payload: `{}`}
+		before := time.Now()
+
+		mockRepo.On("FanOut", ctx, mock.Anything, 10).Return(0, nil)
+		mockRepo.On("ClaimDue", ctx, mock.Anything, mock.Anything, 10).Return([]Job{job}, nil)
+		mockRepo.On("MarkFailed", ctx, uint64(8), 2, mock.MatchedBy(func(next time.Time) bool {
+			return !next.Before(before.Add(2 * time.Second))
+		}), false, "unexpected status 500").Return(nil)
+
+		require.NoError(t, dispatcher.DispatchOnce(ctx))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("dead-letters after max attempts", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer receiver.Close()
+
+		mockRepo := new(MockDeliveryStorer)
+		dispatcher := NewDispatcher(mockRepo, testConfig(), discardLogger())
+		job := Job{DeliveryID: 9, Attempts: 2, URL: receiver.URL, Payload: `{}`}
+
+		mockRepo.On("FanOut", ctx, mock.Anything, 10).Return(0, nil)
+		mockRepo.On("ClaimDue", ctx, mock.Anything, mock.Anything, 10).Return([]Job{job}, nil)
+		mockRepo.On("MarkFailed", ctx, uint64(9), 3, mock.Anything, true, "unexpected status 502").Return(nil)
+
+		require.NoError(t, dispatcher.DispatchOnce(ctx))
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	conf := testConfig()
+
+	assert.Equal(t, time.Second, backoff(conf, 1))
+	assert.Equal(t, 2*time.Second, backoff(conf, 2))
+	assert.Equal(t, 3*time.Second, backoff(conf, 3))
+	assert.Equal(t, 3*time.Second, backoff(conf, 50))
+}
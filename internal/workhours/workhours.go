@@ -0,0 +1,165 @@
+// Package workhours decides when users are on duty from their working window, time zone and
+// team holidays, and measures working time between two instants.
+package workhours
+
+import (
+	"strings"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+)
+
+const clockLayout = "15:04"
+
+// DefaultWorkDays apply to users with a working window but no work days of their own.
+var DefaultWorkDays = []string{"mon", "tue", "wed", "thu", "fri"}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Schedule is a user's working window resolved in their time zone. Build it once per user with
+// For and reuse it: loading the time zone is the expensive part.
+type Schedule struct {
+	loc   *time.Location
+	start int
+	end   int
+	days  [7]bool
+	// always is set when no window is configured: every non-holiday minute is working time.
+	always bool
+}
+
+// For resolves u's schedule. Unknown time zones fall back to UTC.
+func For(u *model.User) Schedule {
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	start, okStart := ParseClock(u.WorkStart)
+	end, okEnd := ParseClock(u.WorkEnd)
+	s := Schedule{
+		loc:    loc,
+		start:  start,
+		end:    end,
+		always: !okStart || !okEnd || start == end,
+	}
+	days := u.WorkDays
+	if len(days) == 0 {
+		days = DefaultWorkDays
+	}
+	for _, name := range days {
+		if wd, ok := ParseWeekday(name); ok {
+			s.days[wd] = true
+		}
+	}
+	return s
+}
+
+// OnDuty reports whether t falls within a shift that starts on a working local date.
+func (s Schedule) OnDuty(t time.Time, holidays map[string]bool) bool {
+	local := t.In(s.loc)
+	if s.always {
+		return s.workingDay(local, holidays)
+	}
+	now := local.Hour()*60 + local.Minute()
+	if s.start < s.end {
+		return now >= s.start && now < s.end && s.workingDay(local, holidays)
+	}
+	// The window wraps past midnight, e.g. 22:00-06:00: the hours after midnight belong to
+	// the shift that started the day before.
+	if now >= s.start {
+		return s.workingDay(local, holidays)
+	}
+	return now < s.end && s.workingDay(midnight(local, -1), holidays)
+}
+
+// Between returns how much of [from, to) falls within the working window outside holidays.
+// It walks the local calendar days of the range, so the cost grows with days, not minutes.
+func (s Schedule) Between(from, to time.Time, holidays map[string]bool) time.Duration {
+	var total time.Duration
+	local := from.In(s.loc)
+	for day := midnight(local, 0); day.Before(to); day = midnight(day, 1) {
+		for _, w := range s.windows(day, holidays) {
+			total += overlap(w[0], w[1], from, to)
+		}
+	}
+	return total
+}
+
+// windows returns the working intervals that lie on the local date of day. The hours after midnight
+// of a wrapped window count only if the shift they belong to started on a working day.
+func (s Schedule) windows(day time.Time, holidays map[string]bool) [][2]time.Time {
+	next := midnight(day, 1)
+	if s.start < s.end || s.always {
+		if !s.workingDay(day, holidays) {
+			return nil
+		}
+		if s.always {
+			return [][2]time.Time{{day, next}}
+		}
+		return [][2]time.Time{{clock(day, s.start), clock(day, s.end)}}
+	}
+	var windows [][2]time.Time
+	if s.workingDay(midnight(day, -1), holidays) {
+		windows = append(windows, [2]time.Time{day, clock(day, s.end)})
+	}
+	if s.workingDay(day, holidays) {
+		windows = append(windows, [2]time.Time{clock(day, s.start), next})
+	}
+	return windows
+}
+
+// workingDay reports whether the local date of t is a working day. Work days only apply to users
+// with a working window; holidays apply to everyone.
+func (s Schedule) workingDay(t time.Time, holidays map[string]bool) bool {
+	if holidays[t.Format(time.DateOnly)] {
+		return false
+	}
+	return s.always || s.days[t.Weekday()]
+}
+
+// ParseWeekday maps a case-insensitive "mon".."sun" name to its weekday.
+func ParseWeekday(name string) (time.Weekday, bool) {
+	wd, ok := weekdays[strings.ToLower(name)]
+	return wd, ok
+}
+
+// ParseClock returns minutes since midnight for a "HH:MM" value.
+func ParseClock(value string) (int, bool) {
+	if value == "" {
+		return 0, false
+	}
+	c, err := time.Parse(clockLayout, value)
+	if err != nil {
+		return 0, false
+	}
+	return c.Hour()*60 + c.Minute(), true
+}
+
+// midnight returns the start of the local date days after t's.
+func midnight(t time.Time, days int) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+days, 0, 0, 0, 0, t.Location())
+}
+
+func clock(day time.Time, minutes int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, minutes, 0, 0, day.Location())
+}
+
+func overlap(start, end, from, to time.Time) time.Duration {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
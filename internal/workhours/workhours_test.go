@@ -0,0 +1,136 @@
+package workhours
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedule_OnDuty(t *testing.T) {
+	// 2025-03-10 06:30 UTC is 13:30 in Novosibirsk, 09:30 in Moscow and 07:30 in Berlin.
+	now := time.Date(2025, 3, 10, 6, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		user     model.User
+		holidays map[string]bool
+		want     bool
+	}{
+		{"no hours configured", model.User{}, nil, true},
+		{"inside window", model.User{Timezone: "Europe/Moscow", WorkStart: "09:00", WorkEnd: "18:00"}, nil, true},
+		{"before window", model.User{Timezone: "Europe/Berlin", WorkStart: "09:00", WorkEnd: "18:00"}, nil, false},
+		{"overnight window started on sunday", model.User{Timezone: "Asia/Novosibirsk", WorkStart: "22:00", WorkEnd: "14:00"}, nil, false},
+		{"team holiday", model.User{Timezone: "Europe/Moscow"}, map[string]bool{"2025-03-10": true}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, For(&tc.user).OnDuty(now, tc.holidays))
+		})
+	}
+
+	t.Run("custom work days", func(t *testing.T) {
+		// Sunday 2025-03-09 in Moscow, inside the window.
+		sunday := time.Date(2025, 3, 9, 7, 0, 0, 0, time.UTC)
+		u := model.User{Timezone: "Europe/Moscow", WorkStart: "09:00", WorkEnd: "18:00", WorkDays: []string{"sun", "mon"}}
+		assert.True(t, For(&u).OnDuty(sunday, nil))
+		assert.False(t, For(&u).OnDuty(sunday.AddDate(0, 0, 2), nil))
+	})
+
+	t.Run("weekend", func(t *testing.T) {
+		u := model.User{Timezone: "Europe/Moscow", WorkStart: "09:00", WorkEnd: "18:00"}
+		assert.False(t, For(&u).OnDuty(now.AddDate(0, 0, -1), nil))
+	})
+}
+
+func TestSchedule_OnDutyOvernight(t *testing.T) {
+	// Novosibirsk is UTC+7; 2025-03-14 is a Friday.
+	night := For(&model.User{Timezone: "Asia/Novosibirsk", WorkStart: "22:00", WorkEnd: "06:00"})
+
+	cases := []struct {
+		name     string
+		at       time.Time
+		holidays map[string]bool
+		want     bool
+	}{
+		{"friday night", time.Date(2025, 3, 14, 16, 0, 0, 0, time.UTC), nil, true},
+		{"saturday early morning ends the friday shift", time.Date(2025, 3, 14, 20, 0, 0, 0, time.UTC), nil, true},
+		{"saturday night", time.Date(2025, 3, 15, 16, 0, 0, 0, time.UTC), nil, false},
+		{"monday early morning follows a sunday", time.Date(2025, 3, 9, 20, 0, 0, 0, time.UTC), nil, false},
+		{"tuesday early morning ends the monday shift", time.Date(2025, 3, 10, 20, 0, 0, 0, time.UTC), nil, true},
+		{"shift started on a holiday", time.Date(2025, 3, 10, 20, 0, 0, 0, time.UTC), map[string]bool{"2025-03-10": true}, false},
+		{"holiday after the shift started", time.Date(2025, 3, 10, 20, 0, 0, 0, time.UTC), map[string]bool{"2025-03-11": true}, true},
+		{"after the shift", time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC), nil, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, night.OnDuty(tc.at, tc.holidays))
+		})
+	}
+}
+
+func TestSchedule_Between(t *testing.T) {
+	u := &model.User{Timezone: "Europe/Berlin", WorkStart: "09:00", WorkEnd: "17:00"}
+	// Friday 16:00 to Monday 10:00 Berlin time: one hour on Friday, the weekend off, one hour on Monday.
+	from := time.Date(2025, 3, 7, 15, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 2*time.Hour, For(u).Between(from, to, nil))
+	assert.Equal(t, time.Hour, For(u).Between(from, to, map[string]bool{"2025-03-10": true}))
+	assert.Equal(t, 66*time.Hour, For(&model.User{}).Between(from, to, nil))
+
+	t.Run("overnight window", func(t *testing.T) {
+		u := &model.User{Timezone: "Asia/Novosibirsk", WorkStart: "22:00", WorkEnd: "06:00"}
+		// Tuesday 20:00 to Wednesday 08:00 Novosibirsk time covers one whole night shift.
+		from := time.Date(2025, 3, 11, 13, 0, 0, 0, time.UTC)
+		to := time.Date(2025, 3, 12, 1, 0, 0, 0, time.UTC)
+
+		assert.Equal(t, 8*time.Hour, For(u).Between(from, to, nil))
+		// The shift belongs to Tuesday, the day it started on.
+		assert.Zero(t, For(u).Between(from, to, map[string]bool{"2025-03-11": true}))
+		assert.Equal(t, 8*time.Hour, For(u).Between(from, to, map[string]bool{"2025-03-12": true}))
+
+		// Friday 20:00 to Monday 08:00: only the Friday night shift, which ends on Saturday.
+		from = time.Date(2025, 3, 14, 13, 0, 0, 0, time.UTC)
+		to = time.Date(2025, 3, 17, 1, 0, 0, 0, time.UTC)
+		assert.Equal(t, 8*time.Hour, For(u).Between(from, to, nil))
+	})
+
+	t.Run("overnight matches on-duty minutes", func(t *testing.T) {
+		schedule := For(&model.User{Timezone: "Asia/Novosibirsk", WorkStart: "21:30", WorkEnd: "05:45"})
+		from := time.Date(2025, 3, 5, 11, 7, 0, 0, time.UTC)
+		to := time.Date(2025, 3, 18, 18, 41, 0, 0, time.UTC)
+		holidays := map[string]bool{"2025-03-11": true, "2025-03-14": true}
+
+		var want time.Duration
+		for at := from; at.Before(to); at = at.Add(time.Minute) {
+			if schedule.OnDuty(at, holidays) {
+				want += time.Minute
+			}
+		}
+		assert.Equal(t, want, schedule.Between(from, to, holidays))
+	})
+
+	t.Run("matches on-duty minutes", func(t *testing.T) {
+		u := &model.User{Timezone: "America/New_York", WorkStart: "09:30", WorkEnd: "17:15"}
+		schedule := For(u)
+		// Spans the US daylight saving switch on 2025-03-09.
+		from := time.Date(2025, 3, 5, 11, 7, 0, 0, time.UTC)
+		to := time.Date(2025, 3, 12, 18, 41, 0, 0, time.UTC)
+		holidays := map[string]bool{"2025-03-11": true}
+
+		var want time.Duration
+		for at := from; at.Before(to); at = at.Add(time.Minute) {
+			if schedule.OnDuty(at, holidays) {
+				want += time.Minute
+			}
+		}
+		assert.Equal(t, want, schedule.Between(from, to, holidays))
+	})
+
+	t.Run("empty range", func(t *testing.T) {
+		assert.Zero(t, For(u).Between(to, from, nil))
+	})
+}
@@ -0,0 +1,28 @@
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// Transaction runs fn in a transaction carried by the context passed to fn: repositories that take
+// their connection from Conn join it, even across packages. Inside a transaction fn joins the outer one.
+func (p *PostgresDB) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	return p.PostgresDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// Conn returns the transaction started by Transaction for ctx, or a ctx-bound connection outside one.
+func (p *PostgresDB) Conn(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return p.PostgresDB.WithContext(ctx)
+}
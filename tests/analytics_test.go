@@ -71,7 +71,7 @@ func (s *AnalyticsSuit) SetupSuite() {
 	s.Require().NoError(errDB)
 	s.rawDB = s.dbWrapper.PostgresDB
 
-	err = s.rawDB.AutoMigrate(&model.Team{}, &model.User{}, &model.PullRequest{})
+	err = MigrateSchema(s.rawDB)
 	s.Require().NoError(err)
 
 	analyticsRepo := analytics.NewRepository(s.dbWrapper)
@@ -88,6 +88,8 @@ func (s *AnalyticsSuit) TearDownSuite() {
 
 func (s *AnalyticsSuit) SetupTest() {
 	s.rawDB.Exec("TRUNCATE TABLE pr_reviewers CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pr_events CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE outbox_messages CASCADE")
 	s.rawDB.Exec("TRUNCATE TABLE pull_requests CASCADE")
 	s.rawDB.Exec("TRUNCATE TABLE users CASCADE")
 	s.rawDB.Exec("TRUNCATE TABLE teams CASCADE")
@@ -100,9 +102,9 @@ func (s *AnalyticsSuit) TestGetStats() {
 	u1 := model.User{ID: "u1", Username: "Alice", IsActive: true, TeamName: "backend"}
 	u2 := model.User{ID: "u2", Username: "Bob", IsActive: true, TeamName: "backend"}
 	u3 := model.User{ID: "u3_author", Username: "Author", IsActive: true, TeamName: "backend"}
-	s.Require().NoError(s.rawDB.Create(&u1).Error)
-	s.Require().NoError(s.rawDB.Create(&u2).Error)
-	s.Require().NoError(s.rawDB.Create(&u3).Error)
+	s.Require().NoError(createUsers(s.rawDB, u1))
+	s.Require().NoError(createUsers(s.rawDB, u2))
+	s.Require().NoError(createUsers(s.rawDB, u3))
 
 	pr1 := model.PullRequest{ID: "pr-1", AuthorID: "u3_author", Reviewers: []*model.User{&u1, &u2}}
 	pr2 := model.PullRequest{ID: "pr-2", AuthorID: "u3_author", Reviewers: []*model.User{&u1}}
@@ -129,3 +131,84 @@ func (s *AnalyticsSuit) TestGetStats() {
 	s.Equal("u2", resp.Stats[1].UserID)
 	s.Equal(1, resp.Stats[1].ReviewCount)
 }
+
+func (s *AnalyticsSuit) TestGetStats_IncludeSubTeams() {
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "finance"}).Error)
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "payments", ParentTeam: "finance"}).Error)
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "billing", ParentTeam: "payments"}).Error)
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "backend"}).Error)
+
+	users := []model.User{
+		{ID: "author", Username: "Author", IsActive: true, TeamName: "finance"},
+		{ID: "f1", Username: "Fay", IsActive: true, TeamName: "finance"},
+		{ID: "b1", Username: "Bea", IsActive: true, TeamName: "billing"},
+		{ID: "k1", Username: "Kim", IsActive: true, TeamName: "backend"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+	prs := []model.PullRequest{
+		{ID: "pr-1", AuthorID: "author", TeamName: "finance", Reviewers: []*model.User{&users[1]}},
+		{ID: "pr-2", AuthorID: "author", TeamName: "billing", Reviewers: []*model.User{&users[2]}},
+		{ID: "pr-3", AuthorID: "author", TeamName: "billing", Reviewers: []*model.User{&users[2]}},
+		{ID: "pr-4", AuthorID: "author", TeamName: "backend", Reviewers: []*model.User{&users[3]}},
+	}
+	s.Require().NoError(s.rawDB.Create(&prs).Error)
+
+	stats := func(query string) []analytics.StatItemDTO {
+		rr := serveJSON(s.router, http.MethodGet, "/analytics/pr?"+query, nil)
+		s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+		var resp analytics.StatsResponseDTO
+		s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+		return resp.Stats
+	}
+
+	s.Equal([]analytics.StatItemDTO{{UserID: "f1", ReviewCount: 1}}, stats("team_name=finance"))
+	s.Equal([]analytics.StatItemDTO{
+		{UserID: "b1", ReviewCount: 2},
+		{UserID: "f1", ReviewCount: 1},
+	}, stats("team_name=finance&include_subteams=true"))
+
+	rr := serveJSON(s.router, http.MethodGet, "/analytics/pr?include_subteams=maybe", nil)
+	s.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func (s *AnalyticsSuit) TestGetDepartmentStats() {
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "finance"}).Error)
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "payments", ParentTeam: "finance"}).Error)
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "billing", ParentTeam: "payments"}).Error)
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "backend"}).Error)
+
+	users := []model.User{
+		{ID: "author", Username: "Author", IsActive: true, TeamName: "finance"},
+		{ID: "f1", Username: "Fay", IsActive: true, TeamName: "finance"},
+		{ID: "b1", Username: "Bea", IsActive: true, TeamName: "billing"},
+		{ID: "b2", Username: "Ben", IsActive: true, TeamName: "billing"},
+		{ID: "k1", Username: "Kim", IsActive: true, TeamName: "backend"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+	prs := []model.PullRequest{
+		{ID: "pr-1", AuthorID: "author", TeamName: "finance", Status: "OPEN", Reviewers: []*model.User{&users[1]}},
+		{ID: "pr-2", AuthorID: "author", TeamName: "billing", Status: "OPEN", Reviewers: []*model.User{&users[2], &users[3]}},
+		{ID: "pr-3", AuthorID: "author", TeamName: "billing", Status: "MERGED", Reviewers: []*model.User{&users[2]}},
+		{ID: "pr-4", AuthorID: "author", TeamName: "backend", Status: "OPEN", Reviewers: []*model.User{&users[4]}},
+	}
+	s.Require().NoError(s.rawDB.Create(&prs).Error)
+
+	rr := serveJSON(s.router, http.MethodGet, "/analytics/teams?team_name=finance", nil)
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+	var resp analytics.DepartmentStatsResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+
+	s.Equal("finance", resp.TeamName)
+	s.Equal(analytics.TeamStatDTO{TeamName: "finance", PullRequests: 3, OpenPullRequests: 2, Reviews: 4}, resp.Total)
+	s.Equal([]analytics.TeamStatDTO{
+		{TeamName: "billing", PullRequests: 2, OpenPullRequests: 1, Reviews: 3},
+		{TeamName: "finance", PullRequests: 1, OpenPullRequests: 1, Reviews: 1},
+		{TeamName: "payments"},
+	}, resp.Teams)
+
+	rr = serveJSON(s.router, http.MethodGet, "/analytics/teams?team_name=ghost", nil)
+	s.Equal(http.StatusNotFound, rr.Code)
+
+	rr = serveJSON(s.router, http.MethodGet, "/analytics/teams", nil)
+	s.Equal(http.StatusBadRequest, rr.Code)
+}
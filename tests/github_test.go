@@ -0,0 +1,214 @@
+//go:build integration
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/event"
+	"github.com/SeeXWH/pr-reviewer-service/internal/github"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
+	"github.com/SeeXWH/pr-reviewer-service/internal/ownership"
+	"github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+	"github.com/SeeXWH/pr-reviewer-service/internal/selection"
+	"github.com/SeeXWH/pr-reviewer-service/internal/team"
+	"github.com/SeeXWH/pr-reviewer-service/internal/user"
+	"github.com/SeeXWH/pr-reviewer-service/internal/webhook"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/logger"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+const githubSecret = "github-secret"
+
+func TestGitHubSuite(t *testing.T) {
+	suite.Run(t, new(GitHubSuite))
+}
+
+type GitHubSuite struct {
+	suite.Suite
+	rawDB     *gorm.DB
+	dbWrapper *db.PostgresDB
+	router    http.Handler
+	cleanUp   func()
+}
+
+func (s *GitHubSuite) SetupSuite() {
+	ctx := context.Background()
+	log := logger.Setup()
+	mux := http.NewServeMux()
+
+	pgContainer, cleanup, err := SetupPostgresContainer()
+	s.Require().NoError(err)
+	s.cleanUp = cleanup
+
+	host, _ := pgContainer.Host(ctx)
+	natPort, _ := pgContainer.MappedPort(ctx, "5432")
+
+	cfg := &configs.Config{
+		DB: configs.DB{
+			Username: "user",
+			Password: "password",
+			Dbname:   "testdb",
+			Host:     host,
+			Port:     natPort.Port(),
+		},
+		App: configs.App{
+			TimeOut: 500 * time.Millisecond,
+		},
+		GitHub: configs.GitHub{
+			WebhookSecret: githubSecret,
+			UserMap:       map[string]string{"alice-gh": "u1"},
+		},
+	}
+
+	var errDB error
+	for i := 0; i < 10; i++ {
+		s.dbWrapper, errDB = db.NewPostgresDB(cfg)
+		if errDB == nil {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	s.Require().NoError(errDB)
+	s.rawDB = s.dbWrapper.PostgresDB
+
+	s.Require().NoError(MigrateSchema(s.rawDB))
+
+	notifyService := notify.NewService(notify.NewRepository(s.dbWrapper), nil, cfg.Notify, log)
+	userService := user.NewService(user.NewRepository(s.dbWrapper), notifyService, log)
+	teamService := team.NewService(team.NewRepository(s.dbWrapper, user.NewRepository(s.dbWrapper)), notifyService, configs.Reviewers{}, log)
+	selectionService := selection.NewService(selection.NewRepository(s.dbWrapper), cfg.Reviewers, log)
+	ownershipService := ownership.NewService(ownership.NewRepository(s.dbWrapper), log)
+	prService := pullrequest.NewService(userService, teamService, selectionService, ownershipService, pullrequest.NewRepository(s.dbWrapper), notifyService, cfg.Reviewers, log)
+
+	githubService := github.NewService(prService, github.NewRepository(s.dbWrapper), cfg.GitHub, log)
+	github.NewHandler(mux, githubService, cfg)
+
+	s.router = mux
+}
+
+func (s *GitHubSuite) TearDownSuite() {
+	if s.cleanUp != nil {
+		s.cleanUp()
+	}
+}
+
+func (s *GitHubSuite) SetupTest() {
+	s.rawDB.Exec("TRUNCATE TABLE integration_deliveries CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE outbox_messages CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pr_events CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pr_reviewers CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pull_requests CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE users CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE teams CASCADE")
+
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "backend", RequiredReviewers: 1}).Error)
+	users := []model.User{
+		{ID: "u1", Username: "Alice", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "Bob", IsActive: true, TeamName: "backend"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+}
+
+func (s *GitHubSuite) deliver(deliveryID, secret string, payload github.PullRequestEventDTO) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(payload)
+	req, _ := http.NewRequest(http.MethodPost, "/integrations/github/webhook", bytes.NewBuffer(body))
+	req.Header.Set(github.EventHeader, github.PullRequestEvent)
+	req.Header.Set(github.DeliveryHeader, deliveryID)
+	req.Header.Set(github.SignatureHeader, webhook.Sign(secret, body))
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	return rr
+}
+
+func (s *GitHubSuite) payload(action string, merged bool) github.PullRequestEventDTO {
+	return github.PullRequestEventDTO{
+		Action: action,
+		PullRequest: github.PullRequestDTO{
+			Number: 7,
+			Title:  "Add caching",
+			Merged: merged,
+			User:   github.UserDTO{Login: "alice-gh"},
+		},
+		Repository: github.RepositoryDTO{FullName: "org/service"},
+	}
+}
+
+func (s *GitHubSuite) TestOpenedThenMerged() {
+	rr := s.deliver("delivery-1", githubSecret, s.payload(github.ActionOpened, false))
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp github.ResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal(github.StatusProcessed, resp.Status)
+	s.Require().NotNil(resp.PR)
+	s.Equal("org/service#7", resp.PR.PRID)
+	s.Equal("u1", resp.PR.AuthorID)
+	s.Equal([]string{"u2"}, resp.PR.Reviewers)
+
+	rr = s.deliver("delivery-1", githubSecret, s.payload(github.ActionOpened, false))
+	s.Require().Equal(http.StatusOK, rr.Code)
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal(github.StatusDuplicate, resp.Status)
+
+	rr = s.deliver("delivery-2", githubSecret, s.payload(github.ActionClosed, true))
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	var pr model.PullRequest
+	s.Require().NoError(s.rawDB.First(&pr, "pull_request_id = ?", "org/service#7").Error)
+	s.Equal(pullrequest.MergeStatus, pr.Status)
+}
+
+func (s *GitHubSuite) TestMergedWithoutApprovalsIsRecorded() {
+	s.Require().NoError(s.rawDB.Model(&model.Team{}).Where("team_name = ?", "backend").
+		Update("required_approvals", 1).Error)
+	rr := s.deliver("delivery-5", githubSecret, s.payload(github.ActionOpened, false))
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = s.deliver("delivery-6", githubSecret, s.payload(github.ActionClosed, true))
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	var pr model.PullRequest
+	s.Require().NoError(s.rawDB.First(&pr, "pull_request_id = ?", "org/service#7").Error)
+	s.Equal(pullrequest.MergeStatus, pr.Status)
+	var count int64
+	s.rawDB.Model(&model.PREvent{}).
+		Where("pull_request_id = ? AND type = ?", "org/service#7", event.TypeApprovalBypassed).
+		Count(&count)
+	s.Equal(int64(1), count)
+}
+
+func (s *GitHubSuite) TestFailedDeliveryIsNotRecorded() {
+	rr := s.deliver("delivery-7", githubSecret, s.payload(github.ActionReopened, false))
+	s.Require().NotEqual(http.StatusOK, rr.Code, rr.Body.String())
+
+	var count int64
+	s.rawDB.Model(&model.IntegrationDelivery{}).Where("delivery_id = ?", "delivery-7").Count(&count)
+	s.Equal(int64(0), count)
+
+	rr = s.deliver("delivery-7", githubSecret, s.payload(github.ActionOpened, false))
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+	var resp github.ResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal(github.StatusProcessed, resp.Status)
+}
+
+func (s *GitHubSuite) TestRejectsBadSignature() {
+	rr := s.deliver("delivery-3", "wrong", s.payload(github.ActionOpened, false))
+	s.Equal(http.StatusUnauthorized, rr.Code)
+
+	var count int64
+	s.rawDB.Model(&model.PullRequest{}).Count(&count)
+	s.Zero(count)
+}
@@ -0,0 +1,189 @@
+//go:build integration
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/event"
+	"github.com/SeeXWH/pr-reviewer-service/internal/gitlab"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
+	"github.com/SeeXWH/pr-reviewer-service/internal/ownership"
+	"github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+	"github.com/SeeXWH/pr-reviewer-service/internal/selection"
+	"github.com/SeeXWH/pr-reviewer-service/internal/team"
+	"github.com/SeeXWH/pr-reviewer-service/internal/user"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/logger"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+const gitlabToken = "gitlab-token"
+
+func TestGitLabSuite(t *testing.T) {
+	suite.Run(t, new(GitLabSuite))
+}
+
+type GitLabSuite struct {
+	suite.Suite
+	rawDB     *gorm.DB
+	dbWrapper *db.PostgresDB
+	router    http.Handler
+	cleanUp   func()
+}
+
+func (s *GitLabSuite) SetupSuite() {
+	ctx := context.Background()
+	log := logger.Setup()
+	mux := http.NewServeMux()
+
+	pgContainer, cleanup, err := SetupPostgresContainer()
+	s.Require().NoError(err)
+	s.cleanUp = cleanup
+
+	host, _ := pgContainer.Host(ctx)
+	natPort, _ := pgContainer.MappedPort(ctx, "5432")
+
+	cfg := &configs.Config{
+		DB: configs.DB{
+			Username: "user",
+			Password: "password",
+			Dbname:   "testdb",
+			Host:     host,
+			Port:     natPort.Port(),
+		},
+		App: configs.App{
+			TimeOut: 500 * time.Millisecond,
+		},
+		GitLab: configs.GitLab{
+			WebhookToken: gitlabToken,
+			UserMap:      map[string]string{"alice.gl": "u1", "bob.gl": "u2"},
+		},
+	}
+
+	var errDB error
+	for i := 0; i < 10; i++ {
+		s.dbWrapper, errDB = db.NewPostgresDB(cfg)
+		if errDB == nil {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	s.Require().NoError(errDB)
+	s.rawDB = s.dbWrapper.PostgresDB
+
+	s.Require().NoError(MigrateSchema(s.rawDB))
+
+	notifyService := notify.NewService(notify.NewRepository(s.dbWrapper), nil, cfg.Notify, log)
+	userService := user.NewService(user.NewRepository(s.dbWrapper), notifyService, log)
+	teamService := team.NewService(team.NewRepository(s.dbWrapper, user.NewRepository(s.dbWrapper)), notifyService, configs.Reviewers{}, log)
+	selectionService := selection.NewService(selection.NewRepository(s.dbWrapper), cfg.Reviewers, log)
+	ownershipService := ownership.NewService(ownership.NewRepository(s.dbWrapper), log)
+	prService := pullrequest.NewService(userService, teamService, selectionService, ownershipService, pullrequest.NewRepository(s.dbWrapper), notifyService, cfg.Reviewers, log)
+
+	gitlab.NewHandler(mux, gitlab.NewService(prService, cfg.GitLab, log), cfg)
+
+	s.router = mux
+}
+
+func (s *GitLabSuite) TearDownSuite() {
+	if s.cleanUp != nil {
+		s.cleanUp()
+	}
+}
+
+func (s *GitLabSuite) SetupTest() {
+	s.rawDB.Exec("TRUNCATE TABLE outbox_messages CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pr_events CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pr_reviewers CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pull_requests CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE users CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE teams CASCADE")
+
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "backend", RequiredReviewers: 1}).Error)
+	users := []model.User{
+		{ID: "u1", Username: "Alice", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "Bob", IsActive: true, TeamName: "backend"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+}
+
+func (s *GitLabSuite) deliver(token string, action string) *httptest.ResponseRecorder {
+	payload := gitlab.MergeRequestEventDTO{
+		ObjectKind: gitlab.MergeRequestKind,
+		User:       gitlab.UserDTO{Username: "alice.gl"},
+		Project:    gitlab.ProjectDTO{PathWithNamespace: "platform/api"},
+		ObjectAttributes: gitlab.ObjectAttributesDTO{
+			IID:    12,
+			Title:  "Add rate limits",
+			Action: action,
+		},
+	}
+	body, _ := json.Marshal(payload)
+	req, _ := http.NewRequest(http.MethodPost, "/integrations/gitlab/webhook", bytes.NewBuffer(body))
+	req.Header.Set(gitlab.EventHeader, gitlab.MergeRequestEvent)
+	req.Header.Set(gitlab.TokenHeader, token)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	return rr
+}
+
+func (s *GitLabSuite) TestOpenThenMerge() {
+	rr := s.deliver(gitlabToken, gitlab.ActionOpen)
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp gitlab.ResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal(gitlab.StatusProcessed, resp.Status)
+	s.Equal("platform/api!12", resp.PullRequestID)
+	s.Require().Len(resp.AssignedReviewers, 1)
+	s.Equal(gitlab.ReviewerDTO{UserID: "u2", Username: "Bob", GitLabUsername: "bob.gl"}, resp.AssignedReviewers[0])
+
+	rr = s.deliver(gitlabToken, gitlab.ActionOpen)
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = s.deliver(gitlabToken, gitlab.ActionMerge)
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	var pr model.PullRequest
+	s.Require().NoError(s.rawDB.First(&pr, "pull_request_id = ?", "platform/api!12").Error)
+	s.Equal(pullrequest.MergeStatus, pr.Status)
+}
+
+func (s *GitLabSuite) TestMergeWithoutApprovalsIsRecorded() {
+	s.Require().NoError(s.rawDB.Model(&model.Team{}).Where("team_name = ?", "backend").
+		Update("required_approvals", 1).Error)
+	rr := s.deliver(gitlabToken, gitlab.ActionOpen)
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = s.deliver(gitlabToken, gitlab.ActionMerge)
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	var pr model.PullRequest
+	s.Require().NoError(s.rawDB.First(&pr, "pull_request_id = ?", "platform/api!12").Error)
+	s.Equal(pullrequest.MergeStatus, pr.Status)
+	var count int64
+	s.rawDB.Model(&model.PREvent{}).
+		Where("pull_request_id = ? AND type = ?", "platform/api!12", event.TypeApprovalBypassed).
+		Count(&count)
+	s.Equal(int64(1), count)
+}
+
+func (s *GitLabSuite) TestRejectsBadToken() {
+	rr := s.deliver("wrong", gitlab.ActionOpen)
+	s.Equal(http.StatusUnauthorized, rr.Code)
+
+	var count int64
+	s.rawDB.Model(&model.PullRequest{}).Count(&count)
+	s.Zero(count)
+}
@@ -3,12 +3,21 @@
 package tests
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"time"
 
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func SetupPostgresContainer() (*postgres.PostgresContainer, func(), error) {
@@ -35,3 +44,57 @@ func SetupPostgresContainer() (*postgres.PostgresContainer, func(), error) {
 
 	return pgContainer, cleanup, nil
 }
+
+func MigrateSchema(db *gorm.DB) error {
+	return db.AutoMigrate(
+		&model.Team{},
+		&model.User{},
+		&model.TeamMember{},
+		&model.PullRequest{},
+		&model.PRReviewer{},
+		&model.PREvent{},
+		&model.OutboxMessage{},
+		&model.WebhookSubscription{},
+		&model.WebhookDelivery{},
+		&model.IntegrationDelivery{},
+		&model.OwnershipRule{},
+		&model.AwayPeriod{},
+		&model.TeamHoliday{},
+		&model.Escalation{},
+		&model.ReviewerCursor{},
+	)
+}
+
+// createUsers inserts users and makes each one a primary member of the team in its TeamName,
+// creating teams that do not exist yet.
+func createUsers(db *gorm.DB, users ...model.User) error {
+	if err := db.Create(&users).Error; err != nil {
+		return err
+	}
+	for _, u := range users {
+		if u.TeamName == "" {
+			continue
+		}
+		team := model.Team{Name: u.TeamName, RequiredReviewers: model.DefaultRequiredReviewers}
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&team).Error; err != nil {
+			return err
+		}
+		member := model.TeamMember{TeamName: u.TeamName, UserID: u.ID, Role: model.RoleMember, IsPrimary: true}
+		if err := db.Omit("User").Create(&member).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func serveJSON(router http.Handler, method, path string, payload any) *httptest.ResponseRecorder {
+	var body io.Reader
+	if payload != nil {
+		bodyBytes, _ := json.Marshal(payload)
+		body = bytes.NewBuffer(bodyBytes)
+	}
+	req, _ := http.NewRequest(method, path, body)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
@@ -0,0 +1,171 @@
+//go:build integration
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
+	"github.com/SeeXWH/pr-reviewer-service/internal/ownership"
+	"github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+	"github.com/SeeXWH/pr-reviewer-service/internal/selection"
+	"github.com/SeeXWH/pr-reviewer-service/internal/team"
+	"github.com/SeeXWH/pr-reviewer-service/internal/user"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/logger"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+func TestNotifySuite(t *testing.T) {
+	suite.Run(t, new(NotifySuite))
+}
+
+// NotifySuite routes notifications to a chat webhook that answers slower than the request budget.
+type NotifySuite struct {
+	suite.Suite
+	rawDB      *gorm.DB
+	dbWrapper  *db.PostgresDB
+	router     http.Handler
+	chat       *httptest.Server
+	mu         sync.Mutex
+	messages   []string
+	stopWorker context.CancelFunc
+	cleanUp    func()
+}
+
+const slowChatDelay = 700 * time.Millisecond
+
+func (s *NotifySuite) SetupSuite() {
+	ctx := context.Background()
+	log := logger.Setup()
+	mux := http.NewServeMux()
+
+	pgContainer, cleanup, err := SetupPostgresContainer()
+	s.Require().NoError(err)
+	s.cleanUp = cleanup
+
+	host, _ := pgContainer.Host(ctx)
+	natPort, _ := pgContainer.MappedPort(ctx, "5432")
+
+	s.chat = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(slowChatDelay)
+		var payload struct {
+			Text string `json:"text"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		s.mu.Lock()
+		s.messages = append(s.messages, payload.Text)
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	cfg := &configs.Config{
+		DB: configs.DB{
+			Username: "user",
+			Password: "password",
+			Dbname:   "testdb",
+			Host:     host,
+			Port:     natPort.Port(),
+		},
+		App: configs.App{
+			TimeOut: 300 * time.Millisecond,
+		},
+		Notify: configs.Notify{
+			ChatWebhookURL: s.chat.URL,
+			QueueSize:      16,
+			Workers:        1,
+			SendTimeout:    5 * time.Second,
+		},
+	}
+
+	var errDB error
+	for i := 0; i < 10; i++ {
+		s.dbWrapper, errDB = db.NewPostgresDB(cfg)
+		if errDB == nil {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	s.Require().NoError(errDB)
+	s.rawDB = s.dbWrapper.PostgresDB
+
+	s.Require().NoError(MigrateSchema(s.rawDB))
+
+	notifyService := notify.NewService(notify.NewRepository(s.dbWrapper), notify.NewNotifiers(cfg.Notify), cfg.Notify, log)
+	userService := user.NewService(user.NewRepository(s.dbWrapper), notifyService, log)
+	teamService := team.NewService(team.NewRepository(s.dbWrapper, user.NewRepository(s.dbWrapper)), notifyService, configs.Reviewers{}, log)
+	selectionService := selection.NewService(selection.NewRepository(s.dbWrapper), cfg.Reviewers, log)
+	ownershipService := ownership.NewService(ownership.NewRepository(s.dbWrapper), log)
+	prService := pullrequest.NewService(userService, teamService, selectionService, ownershipService, pullrequest.NewRepository(s.dbWrapper), notifyService, cfg.Reviewers, log)
+	pullrequest.NewHandler(mux, prService, cfg)
+	user.NewHandler(mux, userService, cfg)
+
+	workerCtx, stop := context.WithCancel(context.Background())
+	s.stopWorker = stop
+	go notifyService.Run(workerCtx)
+
+	s.router = mux
+}
+
+func (s *NotifySuite) TearDownSuite() {
+	s.stopWorker()
+	s.chat.Close()
+	if s.cleanUp != nil {
+		s.cleanUp()
+	}
+}
+
+func (s *NotifySuite) SetupTest() {
+	s.rawDB.Exec("TRUNCATE TABLE pr_events CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE outbox_messages CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pr_reviewers CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pull_requests CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE users CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE teams CASCADE")
+
+	s.mu.Lock()
+	s.messages = nil
+	s.mu.Unlock()
+
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "backend", RequiredReviewers: 1}).Error)
+	users := []model.User{
+		{ID: "u1", Username: "author", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "reviewer", IsActive: true, TeamName: "backend", Email: "reviewer@example.com"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+}
+
+func (s *NotifySuite) TestSlowChatDoesNotDelayRequests() {
+	started := time.Now()
+	rr := serveJSON(s.router, http.MethodPost, "/pullRequest/create", pullrequest.CreatePRRequestDTO{
+		PRID: "pr-1", Name: "Login", AuthorID: "u1",
+	})
+	s.Require().Equal(http.StatusCreated, rr.Code)
+	s.Less(time.Since(started), slowChatDelay)
+
+	rr = serveJSON(s.router, http.MethodPost, "/pullRequest/merge", pullrequest.MergePRRequestDTO{PRID: "pr-1"})
+	s.Require().Equal(http.StatusOK, rr.Code)
+
+	s.Eventually(func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.messages) == 2
+	}, 5*time.Second, 50*time.Millisecond)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Contains(s.messages[0], "Review requested")
+	s.Contains(s.messages[0], "@reviewer")
+	s.Contains(s.messages[1], "Merged")
+	s.Contains(s.messages[1], "@author")
+}
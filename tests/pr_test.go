@@ -8,12 +8,18 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/event"
 	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
+	"github.com/SeeXWH/pr-reviewer-service/internal/ownership"
 	"github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+	"github.com/SeeXWH/pr-reviewer-service/internal/selection"
+	"github.com/SeeXWH/pr-reviewer-service/internal/team"
 	"github.com/SeeXWH/pr-reviewer-service/internal/user"
 	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
 	"github.com/SeeXWH/pr-reviewer-service/pkg/logger"
@@ -57,6 +63,9 @@ func (s *PRSuite) SetupSuite() {
 		App: configs.App{
 			TimeOut: 500 * time.Millisecond,
 		},
+		Reviewers: configs.Reviewers{
+			TeamStrategies: map[string]string{"frontend": selection.StrategyRoundRobin},
+		},
 	}
 
 	var errDB error
@@ -70,14 +79,22 @@ func (s *PRSuite) SetupSuite() {
 	s.Require().NoError(errDB)
 	s.rawDB = s.dbWrapper.PostgresDB
 
-	err = s.rawDB.AutoMigrate(&model.Team{}, &model.User{}, &model.PullRequest{})
+	err = MigrateSchema(s.rawDB)
 	s.Require().NoError(err)
 
 	userRepo := user.NewRepository(s.dbWrapper)
-	userService := user.NewService(userRepo, log)
+	notifyService := notify.NewService(notify.NewRepository(s.dbWrapper), nil, cfg.Notify, log)
+	userService := user.NewService(userRepo, notifyService, log)
+	selectionRepo := selection.NewRepository(s.dbWrapper)
+	selectionService := selection.NewService(selectionRepo, cfg.Reviewers, log)
 	prRepo := pullrequest.NewRepository(s.dbWrapper)
-	prService := pullrequest.NewService(userService, prRepo, log)
+	teamRepo := team.NewRepository(s.dbWrapper, userRepo)
+	teamService := team.NewService(teamRepo, notifyService, configs.Reviewers{}, log)
+	ownershipRepo := ownership.NewRepository(s.dbWrapper)
+	ownershipService := ownership.NewService(ownershipRepo, log)
+	prService := pullrequest.NewService(userService, teamService, selectionService, ownershipService, prRepo, notifyService, cfg.Reviewers, log)
 	pullrequest.NewHandler(mux, prService, cfg)
+	ownership.NewHandler(mux, ownershipService, cfg)
 
 	s.router = mux
 }
@@ -90,9 +107,15 @@ func (s *PRSuite) TearDownSuite() {
 
 func (s *PRSuite) SetupTest() {
 	s.rawDB.Exec("TRUNCATE TABLE pr_reviewers CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pr_events CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE outbox_messages CASCADE")
 	s.rawDB.Exec("TRUNCATE TABLE pull_requests CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE away_periods CASCADE")
 	s.rawDB.Exec("TRUNCATE TABLE users CASCADE")
 	s.rawDB.Exec("TRUNCATE TABLE teams CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE reviewer_cursors CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE ownership_rules CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE team_holidays CASCADE")
 }
 
 func (s *PRSuite) TestCreatePR_Success() {
@@ -104,7 +127,7 @@ func (s *PRSuite) TestCreatePR_Success() {
 		{ID: "u2", Username: "Reviewer1", IsActive: true, TeamName: "backend"},
 		{ID: "u3", Username: "Reviewer2", IsActive: true, TeamName: "backend"},
 	}
-	s.rawDB.Create(&users)
+	createUsers(s.rawDB, users...)
 
 	reqDTO := pullrequest.CreatePRRequestDTO{
 		PRID:     "pr-100",
@@ -128,11 +151,256 @@ func (s *PRSuite) TestCreatePR_Success() {
 	s.Len(resp.PR.Reviewers, 2)
 }
 
+func (s *PRSuite) TestCreatePR_RequiredReviewers() {
+	s.rawDB.Create(&model.Team{Name: "security", RequiredReviewers: 3})
+
+	users := []model.User{
+		{ID: "s1", Username: "Author", IsActive: true, TeamName: "security"},
+		{ID: "s2", Username: "Reviewer1", IsActive: true, TeamName: "security"},
+		{ID: "s3", Username: "Reviewer2", IsActive: true, TeamName: "security"},
+		{ID: "s4", Username: "Reviewer3", IsActive: true, TeamName: "security"},
+		{ID: "s5", Username: "Reviewer4", IsActive: true, TeamName: "security"},
+	}
+	createUsers(s.rawDB, users...)
+
+	reqDTO := pullrequest.CreatePRRequestDTO{PRID: "pr-sec", Name: "Crypto", AuthorID: "s1"}
+	bodyBytes, _ := json.Marshal(reqDTO)
+
+	req, _ := http.NewRequest(http.MethodPost, "/pullRequest/create", bytes.NewBuffer(bodyBytes))
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+
+	s.Require().Equal(http.StatusCreated, rr.Code)
+
+	var resp pullrequest.PRResponseWrapper
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Len(resp.PR.Reviewers, 3)
+	s.NotContains(resp.PR.Reviewers, "s1")
+}
+
+func (s *PRSuite) TestCreatePR_Codeowners() {
+	s.rawDB.Create(&model.Team{Name: "backend", RequiredReviewers: 2})
+	s.rawDB.Create(&model.Team{Name: "dba"})
+
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "Reviewer1", IsActive: true, TeamName: "backend"},
+		{ID: "u3", Username: "Reviewer2", IsActive: true, TeamName: "backend"},
+		{ID: "d1", Username: "Dba", IsActive: true, TeamName: "dba"},
+	}
+	createUsers(s.rawDB, users...)
+
+	rr := s.postJSON("/team/uploadCodeowners", ownership.UploadRequestDTO{
+		TeamName:   "dba",
+		Codeowners: "# schema changes\n**/migrations/*.sql @d1\n",
+	})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = s.postJSON("/team/uploadCodeowners", ownership.UploadRequestDTO{TeamName: "dba", Codeowners: "*.sql ghost"})
+	s.Equal(http.StatusBadRequest, rr.Code)
+
+	rr = s.postJSON("/pullRequest/create", pullrequest.CreatePRRequestDTO{
+		PRID:         "pr-schema",
+		Name:         "Add index",
+		AuthorID:     "u1",
+		ChangedFiles: []string{"db/migrations/002_index.sql", "internal/user/repository.go"},
+	})
+	s.Require().Equal(http.StatusCreated, rr.Code, rr.Body.String())
+
+	var resp pullrequest.PRResponseWrapper
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Len(resp.PR.Reviewers, 2)
+	s.Contains(resp.PR.Reviewers, "d1")
+	s.NotContains(resp.PR.Reviewers, "u1")
+
+	var stored model.PullRequest
+	s.Require().NoError(s.rawDB.First(&stored, "pull_request_id = ?", "pr-schema").Error)
+	s.Equal([]string{"db/migrations/002_index.sql", "internal/user/repository.go"}, stored.ChangedFiles)
+}
+
+func (s *PRSuite) TestCreatePR_FallbackChain() {
+	s.rawDB.Create(&model.Team{Name: "solo", RequiredReviewers: 1, PartnerTeam: "platform"})
+	s.rawDB.Create(&model.Team{Name: "platform"})
+
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "solo"},
+		{ID: "u2", Username: "Inactive", IsActive: false, TeamName: "solo"},
+		{ID: "p1", Username: "Partner", IsActive: true, TeamName: "platform"},
+	}
+	createUsers(s.rawDB, users...)
+
+	rr := s.postJSON("/pullRequest/create", pullrequest.CreatePRRequestDTO{PRID: "pr-solo", Name: "Lonely", AuthorID: "u1"})
+	s.Require().Equal(http.StatusCreated, rr.Code, rr.Body.String())
+
+	var resp pullrequest.PRResponseWrapper
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal([]string{"p1"}, resp.PR.Reviewers)
+	s.Equal(pullrequest.LevelPartnerTeam, resp.PR.FallbackLevel)
+
+	rr = s.postJSON("/pullRequest/reassign", pullrequest.ReassignPRRequestDTO{PRID: "pr-solo", OldUserID: "p1"})
+	s.Equal(http.StatusConflict, rr.Code)
+	s.Contains(rr.Body.String(), "NO_CANDIDATE")
+}
+
+func (s *PRSuite) TestCreatePR_EscalatesUpHierarchy() {
+	s.Require().NoError(s.rawDB.Create(&[]model.Team{
+		{Name: "payments", RequiredReviewers: 1},
+		{Name: "cards", RequiredReviewers: 2, ParentTeam: "payments"},
+		{Name: "billing", ParentTeam: "payments"},
+	}).Error)
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "cards"},
+		{ID: "b1", Username: "Sibling", IsActive: true, TeamName: "billing"},
+		{ID: "h1", Username: "Head", IsActive: true, TeamName: "payments"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+
+	rr := s.postJSON("/pullRequest/create", pullrequest.CreatePRRequestDTO{PRID: "pr-cards", Name: "Cards", AuthorID: "u1"})
+	s.Require().Equal(http.StatusCreated, rr.Code, rr.Body.String())
+
+	var resp pullrequest.PRResponseWrapper
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal("cards", resp.PR.TeamName)
+	s.ElementsMatch([]string{"b1", "h1"}, resp.PR.Reviewers)
+	s.Equal(pullrequest.LevelParentTeam, resp.PR.FallbackLevel)
+}
+
+func (s *PRSuite) TestCreatePR_MultiTeamAuthor() {
+	s.rawDB.Create(&[]model.Team{{Name: "backend", RequiredReviewers: 1}, {Name: "platform", RequiredReviewers: 1}})
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "backend"},
+		{ID: "b1", Username: "Backender", IsActive: true, TeamName: "backend"},
+		{ID: "p1", Username: "Platformer", IsActive: true, TeamName: "platform"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+	s.Require().NoError(s.rawDB.Omit("User").Create(&model.TeamMember{TeamName: "platform", UserID: "u1", Role: model.RoleLead}).Error)
+
+	rr := s.postJSON("/pullRequest/create", pullrequest.CreatePRRequestDTO{PRID: "pr-1", Name: "Primary", AuthorID: "u1"})
+	s.Require().Equal(http.StatusCreated, rr.Code, rr.Body.String())
+	var resp pullrequest.PRResponseWrapper
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal("backend", resp.PR.TeamName)
+	s.Equal([]string{"b1"}, resp.PR.Reviewers)
+
+	rr = s.postJSON("/pullRequest/create", pullrequest.CreatePRRequestDTO{
+		PRID: "pr-2", Name: "Platform", AuthorID: "u1", TeamName: "platform",
+	})
+	s.Require().Equal(http.StatusCreated, rr.Code, rr.Body.String())
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal("platform", resp.PR.TeamName)
+	s.Equal([]string{"p1"}, resp.PR.Reviewers)
+
+	rr = s.postJSON("/pullRequest/create", pullrequest.CreatePRRequestDTO{
+		PRID: "pr-3", Name: "Ghost", AuthorID: "u1", TeamName: "ghost",
+	})
+	s.Equal(http.StatusNotFound, rr.Code)
+}
+
+func (s *PRSuite) TestCreatePR_PrefersWorkingHours() {
+	s.rawDB.Create(&model.Team{Name: "backend", RequiredReviewers: 1})
+
+	now := time.Now().UTC()
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "backend"},
+		{
+			ID: "u2", Username: "Asleep", IsActive: true, TeamName: "backend", Timezone: "UTC",
+			WorkStart: now.Add(2 * time.Hour).Format("15:04"), WorkEnd: now.Add(3 * time.Hour).Format("15:04"),
+		},
+		{ID: "u3", Username: "Awake", IsActive: true, TeamName: "backend"},
+	}
+	createUsers(s.rawDB, users...)
+
+	for _, id := range []string{"pr-wh-1", "pr-wh-2", "pr-wh-3"} {
+		rr := s.postJSON("/pullRequest/create", pullrequest.CreatePRRequestDTO{PRID: id, Name: "Late", AuthorID: "u1"})
+		s.Require().Equal(http.StatusCreated, rr.Code, rr.Body.String())
+
+		var resp pullrequest.PRResponseWrapper
+		s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+		s.Equal([]string{"u3"}, resp.PR.Reviewers)
+	}
+
+	s.rawDB.Create(&model.TeamHoliday{TeamName: "backend", Day: now.Format(time.DateOnly), Name: "Day off"})
+
+	rr := s.postJSON("/pullRequest/create", pullrequest.CreatePRRequestDTO{PRID: "pr-wh-4", Name: "Holiday", AuthorID: "u1"})
+	s.Require().Equal(http.StatusCreated, rr.Code, rr.Body.String())
+
+	var resp pullrequest.PRResponseWrapper
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Len(resp.PR.Reviewers, 1)
+}
+
+func (s *PRSuite) TestCreatePR_PrefersMatchingTags() {
+	s.rawDB.Create(&model.Team{Name: "backend", RequiredReviewers: 1})
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "Infra", IsActive: true, TeamName: "backend", Tags: []string{"infra"}},
+		{ID: "u3", Username: "DBA", IsActive: true, TeamName: "backend", Tags: []string{"database", "infra"}},
+	}
+	createUsers(s.rawDB, users...)
+
+	for _, id := range []string{"pr-tag-1", "pr-tag-2", "pr-tag-3"} {
+		rr := s.postJSON("/pullRequest/create", pullrequest.CreatePRRequestDTO{
+			PRID: id, Name: "Migration", AuthorID: "u1", Labels: []string{"Database"},
+		})
+		s.Require().Equal(http.StatusCreated, rr.Code, rr.Body.String())
+
+		var resp pullrequest.PRResponseWrapper
+		s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+		s.Equal([]string{"u3"}, resp.PR.Reviewers)
+		s.Equal([]string{"database"}, resp.PR.Labels)
+	}
+}
+
+func (s *PRSuite) TestManualReviewerChanges() {
+	s.rawDB.Create(&model.Team{Name: "backend", RequiredReviewers: 1})
+
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "Reviewer", IsActive: true, TeamName: "backend"},
+		{ID: "u3", Username: "Expert", IsActive: true, TeamName: "backend"},
+		{ID: "u4", Username: "Gone", IsActive: false, TeamName: "backend"},
+	}
+	createUsers(s.rawDB, users...)
+	pr := model.PullRequest{ID: "pr-m", Name: "Manual", AuthorID: "u1", Status: "OPEN", Reviewers: []*model.User{&users[1]}}
+	s.rawDB.Create(&pr)
+
+	rr := s.postJSON("/pullRequest/addReviewer", pullrequest.ReviewerRequestDTO{PRID: "pr-m", UserID: "u1"})
+	s.Equal(http.StatusBadRequest, rr.Code)
+	rr = s.postJSON("/pullRequest/addReviewer", pullrequest.ReviewerRequestDTO{PRID: "pr-m", UserID: "u4"})
+	s.Equal(http.StatusConflict, rr.Code)
+	s.Contains(rr.Body.String(), "REVIEWER_INACTIVE")
+
+	rr = s.postJSON("/pullRequest/reassign", pullrequest.ReassignPRRequestDTO{PRID: "pr-m", OldUserID: "u2", NewUserID: "u3"})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+	var reassigned pullrequest.ReassignResponseWrapper
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &reassigned))
+	s.Equal("u3", reassigned.ReplacedBy)
+
+	rr = s.postJSON("/pullRequest/addReviewer", pullrequest.ReviewerRequestDTO{PRID: "pr-m", UserID: "u2"})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+	var resp pullrequest.PRResponseWrapper
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.ElementsMatch([]string{"u2", "u3"}, resp.PR.Reviewers)
+
+	rr = s.postJSON("/pullRequest/removeReviewer", pullrequest.ReviewerRequestDTO{PRID: "pr-m", UserID: "u3"})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = s.postJSON("/pullRequest/reshuffle", pullrequest.ChangeStatusRequestDTO{PRID: "pr-m"})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal([]string{"u3"}, resp.PR.Reviewers)
+
+	var fromDB model.PullRequest
+	s.rawDB.Preload("Reviewers").First(&fromDB, "pull_request_id = ?", "pr-m")
+	s.Require().Len(fromDB.Reviewers, 1)
+	s.Equal("u3", fromDB.Reviewers[0].ID)
+}
+
 func (s *PRSuite) TestMergePR_Success() {
 	team := model.Team{Name: "backend"}
 	s.rawDB.Create(&team)
 	u1 := model.User{ID: "u1", TeamName: "backend"}
-	s.rawDB.Create(&u1)
+	createUsers(s.rawDB, u1)
 
 	pr := model.PullRequest{ID: "pr-200", Name: "Fix", AuthorID: "u1", Status: "OPEN"}
 	s.rawDB.Create(&pr)
@@ -167,7 +435,7 @@ func (s *PRSuite) TestReassign_Success() {
 		{ID: "u2", Username: "OldRev", IsActive: true, TeamName: "backend"},
 		{ID: "u3", Username: "NewRev", IsActive: true, TeamName: "backend"},
 	}
-	s.rawDB.Create(&users)
+	createUsers(s.rawDB, users...)
 
 	pr := model.PullRequest{
 		ID:        "pr-300",
@@ -202,3 +470,315 @@ func (s *PRSuite) TestReassign_Success() {
 	s.Len(prFromDB.Reviewers, 1)
 	s.Equal("u3", prFromDB.Reviewers[0].ID)
 }
+
+func (s *PRSuite) TestCreatePR_RoundRobin() {
+	team := model.Team{Name: "frontend"}
+	s.rawDB.Create(&team)
+
+	users := []model.User{
+		{ID: "f1", Username: "Author", IsActive: true, TeamName: "frontend"},
+		{ID: "f2", Username: "Reviewer1", IsActive: true, TeamName: "frontend"},
+		{ID: "f3", Username: "Reviewer2", IsActive: true, TeamName: "frontend"},
+		{ID: "f4", Username: "Reviewer3", IsActive: true, TeamName: "frontend"},
+	}
+	createUsers(s.rawDB, users...)
+
+	expected := [][]string{{"f2", "f3"}, {"f4", "f2"}}
+	for i, prID := range []string{"pr-rr-1", "pr-rr-2"} {
+		reqDTO := pullrequest.CreatePRRequestDTO{PRID: prID, Name: "Round", AuthorID: "f1"}
+		bodyBytes, _ := json.Marshal(reqDTO)
+
+		req, _ := http.NewRequest(http.MethodPost, "/pullRequest/create", bytes.NewBuffer(bodyBytes))
+		rr := httptest.NewRecorder()
+		s.router.ServeHTTP(rr, req)
+
+		s.Require().Equal(http.StatusCreated, rr.Code)
+
+		var resp pullrequest.PRResponseWrapper
+		s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+		s.Equal(expected[i], resp.PR.Reviewers)
+	}
+
+	var cursor model.ReviewerCursor
+	s.Require().NoError(s.rawDB.First(&cursor, "team_name = ?", "frontend").Error)
+	s.Equal("f2", cursor.LastUserID)
+}
+
+func (s *PRSuite) TestCreatePR_RoundRobinConcurrent() {
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "frontend"}).Error)
+	users := []model.User{
+		{ID: "f1", Username: "Author", IsActive: true, TeamName: "frontend"},
+		{ID: "f2", Username: "Reviewer1", IsActive: true, TeamName: "frontend"},
+		{ID: "f3", Username: "Reviewer2", IsActive: true, TeamName: "frontend"},
+		{ID: "f4", Username: "Reviewer3", IsActive: true, TeamName: "frontend"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+
+	prIDs := []string{"pr-rr-1", "pr-rr-2", "pr-rr-3"}
+	codes := make([]int, len(prIDs))
+	var wg sync.WaitGroup
+	for i, prID := range prIDs {
+		wg.Go(func() {
+			rr := serveJSON(s.router, http.MethodPost, "/pullRequest/create",
+				pullrequest.CreatePRRequestDTO{PRID: prID, Name: "Round", AuthorID: "f1"})
+			codes[i] = rr.Code
+		})
+	}
+	wg.Wait()
+	for _, code := range codes {
+		s.Require().Equal(http.StatusCreated, code)
+	}
+
+	// Three PRs of two reviewers each walk the rotation twice: nobody is picked more than the others.
+	var rows []reviewLoadRow
+	s.Require().NoError(s.rawDB.Table("pr_reviewers").Select("user_id, count(*) AS count").
+		Group("user_id").Order("user_id").Scan(&rows).Error)
+	s.Equal([]reviewLoadRow{{"f2", 2}, {"f3", 2}, {"f4", 2}}, rows)
+}
+
+func (s *PRSuite) TestReassign_ConcurrentForSameReviewer() {
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "backend"}).Error)
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "Reviewer1", IsActive: true, TeamName: "backend"},
+		{ID: "u3", Username: "Reviewer2", IsActive: true, TeamName: "backend"},
+		{ID: "u4", Username: "Spare1", IsActive: true, TeamName: "backend"},
+		{ID: "u5", Username: "Spare2", IsActive: true, TeamName: "backend"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users[:3]...))
+	rr := s.postJSON("/pullRequest/create", pullrequest.CreatePRRequestDTO{PRID: "pr-600", Name: "Race", AuthorID: "u1"})
+	s.Require().Equal(http.StatusCreated, rr.Code)
+	s.Require().NoError(createUsers(s.rawDB, users[3:]...))
+
+	codes := make([]int, 2)
+	var wg sync.WaitGroup
+	for i := range codes {
+		wg.Go(func() {
+			codes[i] = s.postJSON("/pullRequest/reassign", pullrequest.ReassignPRRequestDTO{PRID: "pr-600", OldUserID: "u2"}).Code
+		})
+	}
+	wg.Wait()
+
+	// The second reassign sees the first one's result: u2 is no longer assigned.
+	s.ElementsMatch([]int{http.StatusOK, http.StatusConflict}, codes)
+	var rows []model.PRReviewer
+	s.Require().NoError(s.rawDB.Find(&rows, "pull_request_id = ?", "pr-600").Error)
+	s.Len(rows, 2)
+}
+
+type reviewLoadRow struct {
+	UserID string
+	Count  int
+}
+
+func (s *PRSuite) TestLifecycle_DraftReadyCloseReopen() {
+	s.rawDB.Create(&model.Team{Name: "backend"})
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "Reviewer1", IsActive: true, TeamName: "backend"},
+	}
+	createUsers(s.rawDB, users...)
+
+	rr := s.postJSON("/pullRequest/create", pullrequest.CreatePRRequestDTO{
+		PRID: "pr-400", Name: "WIP", AuthorID: "u1", Draft: true,
+	})
+	s.Require().Equal(http.StatusCreated, rr.Code)
+	var resp pullrequest.PRResponseWrapper
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal(pullrequest.DraftStatus, resp.PR.Status)
+	s.Empty(resp.PR.Reviewers)
+
+	rr = s.postJSON("/pullRequest/merge", pullrequest.MergePRRequestDTO{PRID: "pr-400"})
+	s.Equal(http.StatusConflict, rr.Code)
+	s.Contains(rr.Body.String(), "INVALID_TRANSITION")
+
+	rr = s.postJSON("/pullRequest/markReady", pullrequest.ChangeStatusRequestDTO{PRID: "pr-400"})
+	s.Require().Equal(http.StatusOK, rr.Code)
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal(pullrequest.OpenStatus, resp.PR.Status)
+	s.Equal([]string{"u2"}, resp.PR.Reviewers)
+
+	rr = s.postJSON("/pullRequest/close", pullrequest.ChangeStatusRequestDTO{PRID: "pr-400"})
+	s.Require().Equal(http.StatusOK, rr.Code)
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal(pullrequest.ClosedStatus, resp.PR.Status)
+	s.NotNil(resp.PR.ClosedAt)
+
+	rr = s.postJSON("/pullRequest/reopen", pullrequest.ChangeStatusRequestDTO{PRID: "pr-400"})
+	s.Require().Equal(http.StatusOK, rr.Code)
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal(pullrequest.OpenStatus, resp.PR.Status)
+	s.Nil(resp.PR.ClosedAt)
+
+	var prFromDB model.PullRequest
+	s.rawDB.Preload("Reviewers").First(&prFromDB, "pull_request_id = ?", "pr-400")
+	s.Equal(pullrequest.OpenStatus, prFromDB.Status)
+	s.Len(prFromDB.Reviewers, 1)
+}
+
+func (s *PRSuite) TestMerge_RequiresApprovals() {
+	quorum := 2
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "backend", RequiredReviewers: 2, RequiredApprovals: &quorum}).Error)
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "Reviewer1", IsActive: true, TeamName: "backend"},
+		{ID: "u3", Username: "Reviewer2", IsActive: true, TeamName: "backend"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+
+	rr := s.postJSON("/pullRequest/create", pullrequest.CreatePRRequestDTO{PRID: "pr-500", Name: "Gate", AuthorID: "u1"})
+	s.Require().Equal(http.StatusCreated, rr.Code)
+
+	rr = s.postJSON("/pullRequest/submitReview", pullrequest.SubmitReviewRequestDTO{
+		PRID: "pr-500", UserID: "u2", Verdict: pullrequest.VerdictApproved,
+	})
+	s.Require().Equal(http.StatusOK, rr.Code)
+
+	rr = s.postJSON("/pullRequest/merge", pullrequest.MergePRRequestDTO{PRID: "pr-500"})
+	s.Equal(http.StatusConflict, rr.Code)
+	s.Contains(rr.Body.String(), "NOT_APPROVED")
+
+	rr = s.postJSON("/pullRequest/submitReview", pullrequest.SubmitReviewRequestDTO{
+		PRID: "pr-500", UserID: "u1", Verdict: pullrequest.VerdictApproved,
+	})
+	s.Equal(http.StatusConflict, rr.Code)
+	s.Contains(rr.Body.String(), "NOT_ASSIGNED")
+
+	rr = s.postJSON("/pullRequest/submitReview", pullrequest.SubmitReviewRequestDTO{
+		PRID: "pr-500", UserID: "u3", Verdict: pullrequest.VerdictApproved,
+	})
+	s.Require().Equal(http.StatusOK, rr.Code)
+	var resp pullrequest.PRResponseWrapper
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Require().Len(resp.PR.Reviews, 2)
+	for _, review := range resp.PR.Reviews {
+		s.Equal(pullrequest.VerdictApproved, review.Verdict)
+	}
+
+	rr = s.postJSON("/pullRequest/merge", pullrequest.MergePRRequestDTO{PRID: "pr-500"})
+	s.Require().Equal(http.StatusOK, rr.Code)
+
+	var rows []model.PRReviewer
+	s.rawDB.Find(&rows, "pull_request_id = ?", "pr-500")
+	s.Len(rows, 2)
+	for _, row := range rows {
+		s.Equal(pullrequest.VerdictApproved, row.Verdict)
+		s.NotNil(row.VerdictAt)
+	}
+}
+
+func (s *PRSuite) TestGetAndList() {
+	s.Require().NoError(s.rawDB.Create(&[]model.Team{{Name: "backend"}, {Name: "frontend"}}).Error)
+	users := []model.User{
+		{ID: "u1", Username: "Alice", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "Bob", IsActive: true, TeamName: "backend"},
+		{ID: "u3", Username: "Carol", IsActive: true, TeamName: "frontend"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+
+	base := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	merged := base.Add(time.Hour)
+	prs := []model.PullRequest{
+		{ID: "pr-1", Name: "A", AuthorID: "u1", TeamName: "backend", Status: "OPEN", CreatedAt: base, Reviewers: []*model.User{&users[1]}},
+		{ID: "pr-2", Name: "B", AuthorID: "u1", TeamName: "backend", Status: "MERGED", CreatedAt: base.Add(time.Minute), MergedAt: &merged},
+		{ID: "pr-3", Name: "C", AuthorID: "u2", TeamName: "backend", Status: "OPEN", CreatedAt: base.Add(2 * time.Minute)},
+		{ID: "pr-4", Name: "D", AuthorID: "u3", TeamName: "frontend", Status: "OPEN", CreatedAt: base.Add(3 * time.Minute)},
+	}
+	s.Require().NoError(s.rawDB.Create(&prs).Error)
+
+	rr := s.get("/pullRequest/get?pull_request_id=pr-1")
+	s.Require().Equal(http.StatusOK, rr.Code)
+	var single pullrequest.PRResponseWrapper
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &single))
+	s.Equal([]string{"u2"}, single.PR.Reviewers)
+
+	rr = s.get("/pullRequest/get?pull_request_id=ghost")
+	s.Equal(http.StatusNotFound, rr.Code)
+
+	var seen []string
+	cursor := ""
+	for {
+		rr = s.get("/pullRequest/list?team_name=backend&limit=2&cursor=" + cursor)
+		s.Require().Equal(http.StatusOK, rr.Code)
+		var page pullrequest.ListResponseDTO
+		s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &page))
+		for _, pr := range page.PullRequests {
+			seen = append(seen, pr.PRID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	s.Equal([]string{"pr-3", "pr-2", "pr-1"}, seen)
+
+	var list pullrequest.ListResponseDTO
+	rr = s.get("/pullRequest/list?reviewer_id=u2")
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &list))
+	s.Require().Len(list.PullRequests, 1)
+	s.Equal("pr-1", list.PullRequests[0].PRID)
+
+	rr = s.get("/pullRequest/list?status=MERGED&merged_from=2025-01-10T09:30:00Z")
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &list))
+	s.Require().Len(list.PullRequests, 1)
+	s.Equal("pr-2", list.PullRequests[0].PRID)
+
+	rr = s.get("/pullRequest/list?created_to=2025-01-10T09:02:00Z&author_id=u1")
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &list))
+	s.Len(list.PullRequests, 2)
+
+	rr = s.get("/pullRequest/list?status=UNKNOWN")
+	s.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func (s *PRSuite) TestHistory() {
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "backend", RequiredReviewers: 1}).Error)
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "Reviewer1", IsActive: true, TeamName: "backend"},
+		{ID: "u3", Username: "Reviewer2", IsActive: true, TeamName: "backend"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+
+	rr := s.postJSON("/pullRequest/create", pullrequest.CreatePRRequestDTO{PRID: "pr-600", Name: "Audit", AuthorID: "u1"})
+	s.Require().Equal(http.StatusCreated, rr.Code)
+	var created pullrequest.PRResponseWrapper
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &created))
+	s.Require().Len(created.PR.Reviewers, 1)
+	first := created.PR.Reviewers[0]
+
+	rr = s.postJSON("/pullRequest/reassign", pullrequest.ReassignPRRequestDTO{PRID: "pr-600", OldUserID: first})
+	s.Require().Equal(http.StatusOK, rr.Code)
+	rr = s.postJSON("/pullRequest/merge", pullrequest.MergePRRequestDTO{PRID: "pr-600"})
+	s.Require().Equal(http.StatusOK, rr.Code)
+
+	rr = s.get("/pullRequest/history?pull_request_id=pr-600")
+	s.Require().Equal(http.StatusOK, rr.Code)
+	var history pullrequest.HistoryResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &history))
+
+	types := make([]string, 0, len(history.Events))
+	for _, e := range history.Events {
+		types = append(types, e.Type)
+	}
+	s.Equal([]string{
+		event.TypeCreated,
+		event.TypeAssigned,
+		event.TypeReassigned,
+		event.TypeStatusChanged,
+	}, types)
+	s.Equal(first, history.Events[1].UserID)
+	s.Equal(first, history.Events[2].PreviousUserID)
+	s.Equal(pullrequest.MergeStatus, history.Events[3].ToStatus)
+
+	rr = s.get("/pullRequest/history?pull_request_id=ghost")
+	s.Equal(http.StatusNotFound, rr.Code)
+}
+
+func (s *PRSuite) postJSON(path string, payload any) *httptest.ResponseRecorder {
+	return serveJSON(s.router, http.MethodPost, path, payload)
+}
+
+func (s *PRSuite) get(path string) *httptest.ResponseRecorder {
+	return serveJSON(s.router, http.MethodGet, path, nil)
+}
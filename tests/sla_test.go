@@ -0,0 +1,157 @@
+//go:build integration
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
+	"github.com/SeeXWH/pr-reviewer-service/internal/ownership"
+	"github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+	"github.com/SeeXWH/pr-reviewer-service/internal/selection"
+	"github.com/SeeXWH/pr-reviewer-service/internal/sla"
+	"github.com/SeeXWH/pr-reviewer-service/internal/team"
+	"github.com/SeeXWH/pr-reviewer-service/internal/user"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/logger"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+func TestSLASuite(t *testing.T) {
+	suite.Run(t, new(SLASuite))
+}
+
+type SLASuite struct {
+	suite.Suite
+	rawDB      *gorm.DB
+	dbWrapper  *db.PostgresDB
+	router     http.Handler
+	slaService *sla.Service
+	cleanUp    func()
+}
+
+func (s *SLASuite) SetupSuite() {
+	ctx := context.Background()
+	log := logger.Setup()
+	mux := http.NewServeMux()
+
+	pgContainer, cleanup, err := SetupPostgresContainer()
+	s.Require().NoError(err)
+	s.cleanUp = cleanup
+
+	host, _ := pgContainer.Host(ctx)
+	natPort, _ := pgContainer.MappedPort(ctx, "5432")
+
+	cfg := &configs.Config{
+		DB: configs.DB{
+			Username: "user",
+			Password: "password",
+			Dbname:   "testdb",
+			Host:     host,
+			Port:     natPort.Port(),
+		},
+		App: configs.App{
+			TimeOut: 500 * time.Millisecond,
+		},
+		SLA: configs.SLA{
+			PollInterval: time.Minute,
+		},
+	}
+
+	var errDB error
+	for i := 0; i < 10; i++ {
+		s.dbWrapper, errDB = db.NewPostgresDB(cfg)
+		if errDB == nil {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	s.Require().NoError(errDB)
+	s.rawDB = s.dbWrapper.PostgresDB
+
+	err = MigrateSchema(s.rawDB)
+	s.Require().NoError(err)
+
+	notifyService := notify.NewService(notify.NewRepository(s.dbWrapper), nil, cfg.Notify, log)
+	userService := user.NewService(user.NewRepository(s.dbWrapper), notifyService, log)
+	teamService := team.NewService(team.NewRepository(s.dbWrapper, user.NewRepository(s.dbWrapper)), notifyService, configs.Reviewers{}, log)
+	selectionService := selection.NewService(selection.NewRepository(s.dbWrapper), cfg.Reviewers, log)
+	ownershipService := ownership.NewService(ownership.NewRepository(s.dbWrapper), log)
+	prRepo := pullrequest.NewRepository(s.dbWrapper)
+	prService := pullrequest.NewService(userService, teamService, selectionService, ownershipService, prRepo, notifyService, cfg.Reviewers, log)
+	s.slaService = sla.NewService(sla.NewRepository(s.dbWrapper), prService, cfg.SLA, log)
+	sla.NewHandler(mux, s.slaService, cfg)
+
+	s.router = mux
+}
+
+func (s *SLASuite) TearDownSuite() {
+	if s.cleanUp != nil {
+		s.cleanUp()
+	}
+}
+
+func (s *SLASuite) SetupTest() {
+	s.rawDB.Exec("TRUNCATE TABLE escalations CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pr_reviewers CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pr_events CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE outbox_messages CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pull_requests CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE users CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE teams CASCADE")
+}
+
+func (s *SLASuite) TestEscalateOnce() {
+	s.rawDB.Create(&model.Team{Name: "flaggers", RequiredReviewers: 1, SLAHours: 4, SLAPolicy: model.SLAPolicyFlag})
+	s.rawDB.Create(&model.Team{Name: "movers", RequiredReviewers: 1, SLAHours: 4, SLAPolicy: model.SLAPolicyReassign})
+
+	users := []model.User{
+		{ID: "a1", Username: "AuthorF", IsActive: true, TeamName: "flaggers"},
+		{ID: "f1", Username: "Slow", IsActive: true, TeamName: "flaggers"},
+		{ID: "a2", Username: "AuthorM", IsActive: true, TeamName: "movers"},
+		{ID: "m1", Username: "Slower", IsActive: true, TeamName: "movers"},
+		{ID: "m2", Username: "Fast", IsActive: true, TeamName: "movers"},
+	}
+	createUsers(s.rawDB, users...)
+	prs := []model.PullRequest{
+		{ID: "pr-f", Name: "Flag", AuthorID: "a1", TeamName: "flaggers", Status: "OPEN", Reviewers: []*model.User{&users[1]}},
+		{ID: "pr-m", Name: "Move", AuthorID: "a2", TeamName: "movers", Status: "OPEN", Reviewers: []*model.User{&users[3]}},
+		{ID: "pr-fresh", Name: "Fresh", AuthorID: "a2", TeamName: "movers", Status: "OPEN", Reviewers: []*model.User{&users[4]}},
+	}
+	s.rawDB.Create(&prs)
+	s.rawDB.Exec("UPDATE pr_reviewers SET assigned_at = ? WHERE pull_request_id IN ?", time.Now().Add(-6*time.Hour), []string{"pr-f", "pr-m"})
+
+	count, err := s.slaService.EscalateOnce(context.Background(), time.Now())
+	s.Require().NoError(err)
+	s.Equal(2, count)
+
+	var moved model.PullRequest
+	s.rawDB.Preload("Reviewers").First(&moved, "pull_request_id = ?", "pr-m")
+	s.Require().Len(moved.Reviewers, 1)
+	s.Equal("m2", moved.Reviewers[0].ID)
+
+	count, err = s.slaService.EscalateOnce(context.Background(), time.Now())
+	s.Require().NoError(err)
+	s.Zero(count)
+
+	req, _ := http.NewRequest(http.MethodGet, "/sla/escalations?team_name=movers", nil)
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	s.Require().Equal(http.StatusOK, rr.Code)
+
+	var resp sla.ListResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Require().Len(resp.Escalations, 1)
+	s.Equal("pr-m", resp.Escalations[0].PullRequestID)
+	s.Equal(sla.ActionReassigned, resp.Escalations[0].Action)
+	s.Equal("m2", resp.Escalations[0].NewUserID)
+}
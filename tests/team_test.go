@@ -13,7 +13,9 @@ import (
 
 	"github.com/SeeXWH/pr-reviewer-service/configs"
 	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
 	"github.com/SeeXWH/pr-reviewer-service/internal/team"
+	"github.com/SeeXWH/pr-reviewer-service/internal/user"
 	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
 	"github.com/SeeXWH/pr-reviewer-service/pkg/logger"
 
@@ -69,11 +71,12 @@ func (s *TeamSuite) SetupSuite() {
 	s.Require().NoError(errDB)
 	s.rawDB = s.dbWrapper.PostgresDB
 
-	err = s.rawDB.AutoMigrate(&model.Team{}, &model.User{}, &model.PullRequest{})
+	err = MigrateSchema(s.rawDB)
 	s.Require().NoError(err)
 
-	teamRepo := team.NewRepository(s.dbWrapper)
-	teamService := team.NewService(teamRepo, log)
+	notifyService := notify.NewService(notify.NewRepository(s.dbWrapper), nil, cfg.Notify, log)
+	teamRepo := team.NewRepository(s.dbWrapper, user.NewRepository(s.dbWrapper))
+	teamService := team.NewService(teamRepo, notifyService, configs.Reviewers{GlobalPool: []string{"g1"}}, log)
 
 	team.NewHandler(mux, teamService, cfg)
 
@@ -88,7 +91,10 @@ func (s *TeamSuite) TearDownSuite() {
 
 func (s *TeamSuite) SetupTest() {
 	s.rawDB.Exec("TRUNCATE TABLE pr_reviewers CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pr_events CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE outbox_messages CASCADE")
 	s.rawDB.Exec("TRUNCATE TABLE pull_requests CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE team_holidays CASCADE")
 	s.rawDB.Exec("TRUNCATE TABLE users CASCADE")
 	s.rawDB.Exec("TRUNCATE TABLE teams CASCADE")
 }
@@ -149,7 +155,7 @@ func (s *TeamSuite) TestCreateTeam_Duplicate() {
 func (s *TeamSuite) TestGetTeam_Success() {
 	teamName := "gamma-squad"
 	s.rawDB.Create(&model.Team{Name: teamName})
-	s.rawDB.Create(&model.User{ID: "g1", Username: "Gus", TeamName: teamName, IsActive: true})
+	createUsers(s.rawDB, model.User{ID: "g1", Username: "Gus", TeamName: teamName, IsActive: true})
 
 	req, _ := http.NewRequest(http.MethodGet, "/team/get?team_name="+teamName, nil)
 	rr := httptest.NewRecorder()
@@ -176,3 +182,299 @@ func (s *TeamSuite) TestGetTeam_NotFound() {
 	s.Equal(http.StatusNotFound, rr.Code)
 	s.Contains(rr.Body.String(), "NOT_FOUND")
 }
+
+func (s *TeamSuite) TestUpdateSettings_Success() {
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "delta-squad"}).Error)
+
+	bodyBytes := []byte(`{"team_name":"delta-squad","required_reviewers":1}`)
+	req, _ := http.NewRequest(http.MethodPost, "/team/updateSettings", bytes.NewBuffer(bodyBytes))
+	rr := httptest.NewRecorder()
+
+	s.router.ServeHTTP(rr, req)
+
+	s.Equal(http.StatusOK, rr.Code)
+
+	var resp team.InfoDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal(1, resp.RequiredReviewers)
+
+	var dbTeam model.Team
+	s.Require().NoError(s.rawDB.First(&dbTeam, "team_name = ?", "delta-squad").Error)
+	s.Equal(1, dbTeam.RequiredReviewers)
+}
+
+func (s *TeamSuite) TestUpdateSettings_Invalid() {
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "delta-squad"}).Error)
+
+	bodyBytes := []byte(`{"team_name":"delta-squad","required_reviewers":0}`)
+	req, _ := http.NewRequest(http.MethodPost, "/team/updateSettings", bytes.NewBuffer(bodyBytes))
+	rr := httptest.NewRecorder()
+
+	s.router.ServeHTTP(rr, req)
+
+	s.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func (s *TeamSuite) TestUpdateSettings_FallbackChain() {
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "delta-squad"}).Error)
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "platform"}).Error)
+	s.Require().NoError(createUsers(s.rawDB, model.User{ID: "lead", Username: "Lead", TeamName: "platform", IsActive: true}))
+
+	rr := serveJSON(s.router, http.MethodPost, "/team/updateSettings", map[string]any{
+		"team_name":    "delta-squad",
+		"partner_team": "platform",
+		"lead_user_id": "lead",
+	})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp team.InfoDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal("platform", resp.PartnerTeam)
+	s.Equal("lead", resp.LeadUserID)
+
+	rr = serveJSON(s.router, http.MethodPost, "/team/updateSettings", map[string]any{
+		"team_name":    "delta-squad",
+		"partner_team": "ghost",
+	})
+	s.Equal(http.StatusBadRequest, rr.Code)
+}
+
+// seedReviews creates backend (u1 author, u2 and u3 reviewers of pr-1, u4 spare) and an empty platform team.
+func (s *TeamSuite) seedReviews() {
+	s.Require().NoError(s.rawDB.Create(&[]model.Team{
+		{Name: "backend", RequiredReviewers: 2},
+		{Name: "platform", RequiredReviewers: 2},
+	}).Error)
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "Bob", IsActive: true, TeamName: "backend"},
+		{ID: "u3", Username: "Carol", IsActive: true, TeamName: "backend"},
+		{ID: "u4", Username: "Dan", IsActive: true, TeamName: "backend"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+	pr := model.PullRequest{
+		ID:        "pr-1",
+		Status:    "OPEN",
+		AuthorID:  "u1",
+		TeamName:  "backend",
+		Reviewers: []*model.User{&users[1], &users[2]},
+	}
+	s.Require().NoError(s.rawDB.Create(&pr).Error)
+}
+
+func (s *TeamSuite) reviewerIDs(prID string) []string {
+	var pr model.PullRequest
+	s.Require().NoError(s.rawDB.Preload("Reviewers").First(&pr, "pull_request_id = ?", prID).Error)
+	ids := make([]string, 0, len(pr.Reviewers))
+	for _, r := range pr.Reviewers {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func (s *TeamSuite) TestAddMembers_KeepsOtherTeams() {
+	s.seedReviews()
+
+	rr := serveJSON(s.router, http.MethodPost, "/team/addMembers", team.AddMembersRequestDTO{
+		TeamName: "platform",
+		Members: []team.UserCreateRequestDTO{
+			{UserID: "u2", Username: "Bob", IsActive: true, Role: model.RoleLead},
+			{UserID: "u5", Username: "Eve", IsActive: true},
+		},
+	})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp team.InfoDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal([]team.MemberDTO{
+		{UserID: "u2", Username: "Bob", IsActive: true, Role: model.RoleLead, IsPrimary: false},
+		{UserID: "u5", Username: "Eve", IsActive: true, Role: model.RoleMember, IsPrimary: true},
+	}, resp.Members)
+	s.ElementsMatch([]string{"u2", "u3"}, s.reviewerIDs("pr-1"))
+
+	var count int64
+	s.rawDB.Model(&model.TeamMember{}).Where("user_id = ?", "u2").Count(&count)
+	s.Equal(int64(2), count)
+
+	rr = serveJSON(s.router, http.MethodPost, "/team/addMembers", team.AddMembersRequestDTO{
+		TeamName: "platform",
+		Members:  []team.UserCreateRequestDTO{{UserID: "u2", Username: "Bob", IsActive: true, IsPrimary: true}},
+	})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+	var primary model.TeamMember
+	s.Require().NoError(s.rawDB.First(&primary, "user_id = ? AND is_primary", "u2").Error)
+	s.Equal("platform", primary.TeamName)
+
+	rr = serveJSON(s.router, http.MethodPost, "/team/addMembers", team.AddMembersRequestDTO{
+		TeamName: "ghost",
+		Members:  []team.UserCreateRequestDTO{{UserID: "u6", Username: "Ghost"}},
+	})
+	s.Equal(http.StatusNotFound, rr.Code)
+}
+
+func (s *TeamSuite) TestRemoveMember_ReassignsReviews() {
+	s.seedReviews()
+
+	rr := serveJSON(s.router, http.MethodPost, "/team/removeMember", team.RemoveMemberRequestDTO{TeamName: "backend", UserID: "u2"})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp team.ReassignmentResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal(1, resp.ReassignedPRs)
+	s.Equal([]team.AssignmentDTO{{PullRequestID: "pr-1", UserID: "u4"}}, resp.Assignments)
+	s.ElementsMatch([]string{"u3", "u4"}, s.reviewerIDs("pr-1"))
+
+	var removed model.User
+	s.Require().NoError(s.rawDB.First(&removed, "user_id = ?", "u2").Error)
+	s.True(removed.IsActive)
+	var count int64
+	s.rawDB.Model(&model.TeamMember{}).Where("user_id = ?", "u2").Count(&count)
+	s.Zero(count)
+
+	rr = serveJSON(s.router, http.MethodPost, "/team/removeMember", team.RemoveMemberRequestDTO{TeamName: "backend", UserID: "u2"})
+	s.Equal(http.StatusNotFound, rr.Code)
+}
+
+func (s *TeamSuite) TestRename_Cascades() {
+	s.seedReviews()
+	s.Require().NoError(s.rawDB.Model(&model.Team{}).Where("team_name = ?", "platform").
+		Update("partner_team", "backend").Error)
+	s.Require().NoError(s.rawDB.Create(&model.TeamHoliday{TeamName: "backend", Day: "2025-01-01"}).Error)
+
+	rr := serveJSON(s.router, http.MethodPost, "/team/rename", team.RenameRequestDTO{TeamName: "backend", NewTeamName: "core"})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp team.InfoDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal("core", resp.TeamName)
+	s.Len(resp.Members, 4)
+
+	var platform model.Team
+	s.Require().NoError(s.rawDB.First(&platform, "team_name = ?", "platform").Error)
+	s.Equal("core", platform.PartnerTeam)
+	var holidays int64
+	s.rawDB.Model(&model.TeamHoliday{}).Where("team_name = ?", "core").Count(&holidays)
+	s.Equal(int64(1), holidays)
+	var pr model.PullRequest
+	s.Require().NoError(s.rawDB.First(&pr, "pull_request_id = ?", "pr-1").Error)
+	s.Equal("core", pr.TeamName)
+
+	rr = serveJSON(s.router, http.MethodPost, "/team/rename", team.RenameRequestDTO{TeamName: "core", NewTeamName: "platform"})
+	s.Equal(http.StatusBadRequest, rr.Code)
+	rr = serveJSON(s.router, http.MethodPost, "/team/rename", team.RenameRequestDTO{TeamName: "backend", NewTeamName: "x"})
+	s.Equal(http.StatusNotFound, rr.Code)
+}
+
+func (s *TeamSuite) TestDelete_ReassignsToPartner() {
+	s.seedReviews()
+	s.Require().NoError(createUsers(s.rawDB, model.User{ID: "p1", Username: "Pat", IsActive: true, TeamName: "platform"}))
+	s.Require().NoError(s.rawDB.Model(&model.Team{}).Where("team_name = ?", "backend").
+		Update("partner_team", "platform").Error)
+
+	rr := serveJSON(s.router, http.MethodPost, "/team/delete", team.DeleteRequestDTO{TeamName: "backend"})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp team.ReassignmentResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal(1, resp.ReassignedPRs)
+	s.Equal([]string{"p1"}, s.reviewerIDs("pr-1"))
+	s.Equal([]team.ShortPRDTO{{PullRequestID: "pr-1", MissingReviewers: 1}}, resp.ShortPRs)
+
+	var count int64
+	s.rawDB.Model(&model.Team{}).Where("team_name = ?", "backend").Count(&count)
+	s.Zero(count)
+	s.rawDB.Model(&model.TeamMember{}).Where("team_name = ?", "backend").Count(&count)
+	s.Zero(count)
+	var pr model.PullRequest
+	s.Require().NoError(s.rawDB.First(&pr, "pull_request_id = ?", "pr-1").Error)
+	s.Equal("platform", pr.TeamName)
+
+	rr = serveJSON(s.router, http.MethodPost, "/team/delete", team.DeleteRequestDTO{TeamName: "backend"})
+	s.Equal(http.StatusNotFound, rr.Code)
+}
+
+func (s *TeamSuite) TestDelete_WithoutPartnerUsesFallbackChain() {
+	s.seedReviews()
+	s.Require().NoError(createUsers(s.rawDB,
+		model.User{ID: "e1", Username: "Eve", IsActive: true, TeamName: "eng"},
+		model.User{ID: "m1", Username: "Mia", IsActive: true, TeamName: "mobile"},
+		model.User{ID: "g1", Username: "Gus", IsActive: true},
+	))
+	s.Require().NoError(s.rawDB.Model(&model.Team{}).Where("team_name IN ?", []string{"backend", "mobile"}).
+		Update("parent_team", "eng").Error)
+	pr := model.PullRequest{ID: "pr-2", Status: "OPEN", AuthorID: "m1", TeamName: "backend"}
+	s.Require().NoError(s.rawDB.Create(&pr).Error)
+	s.Require().NoError(s.rawDB.Create(&model.PRReviewer{PullRequestID: "pr-2", UserID: "u2"}).Error)
+
+	rr := serveJSON(s.router, http.MethodPost, "/team/delete", team.DeleteRequestDTO{TeamName: "backend"})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp team.ReassignmentResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal(2, resp.ReassignedPRs)
+	s.Empty(resp.ShortPRs)
+
+	// pr-1's author has no team left, so the PR moves to the parent and takes its member and a sibling.
+	s.ElementsMatch([]string{"e1", "m1"}, s.reviewerIDs("pr-1"))
+	// pr-2 moves to its author's team; with the author excluded the chain reaches the parent and the global pool.
+	s.ElementsMatch([]string{"e1", "g1"}, s.reviewerIDs("pr-2"))
+
+	var prs []model.PullRequest
+	s.Require().NoError(s.rawDB.Order("pull_request_id").Find(&prs).Error)
+	s.Require().Len(prs, 2)
+	s.Equal("eng", prs[0].TeamName)
+	s.Equal("mobile", prs[1].TeamName)
+}
+
+func (s *TeamSuite) TestHierarchy() {
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "finance"}).Error)
+	for _, name := range []string{"payments", "tax"} {
+		rr := serveJSON(s.router, http.MethodPost, "/team/add", team.CreateRequestDTO{TeamName: name, ParentTeam: "finance"})
+		s.Require().Equal(http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	rr := serveJSON(s.router, http.MethodPost, "/team/add", team.CreateRequestDTO{
+		TeamName:   "billing",
+		ParentTeam: "payments",
+		Members:    []team.UserCreateRequestDTO{{UserID: "b1", Username: "Bea", IsActive: true}},
+	})
+	s.Require().Equal(http.StatusCreated, rr.Code, rr.Body.String())
+
+	rr = serveJSON(s.router, http.MethodPost, "/team/add", team.CreateRequestDTO{TeamName: "orphan", ParentTeam: "ghost"})
+	s.Equal(http.StatusBadRequest, rr.Code)
+	rr = serveJSON(s.router, http.MethodPost, "/team/updateSettings", map[string]any{
+		"team_name":   "finance",
+		"parent_team": "billing",
+	})
+	s.Equal(http.StatusBadRequest, rr.Code)
+
+	rr = serveJSON(s.router, http.MethodGet, "/team/get?team_name=finance&include_subteams=true", nil)
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+	var tree team.InfoDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &tree))
+	s.Require().Len(tree.SubTeams, 2)
+	s.Equal("payments", tree.SubTeams[0].TeamName)
+	s.Equal("tax", tree.SubTeams[1].TeamName)
+	s.Require().Len(tree.SubTeams[0].SubTeams, 1)
+	billing := tree.SubTeams[0].SubTeams[0]
+	s.Equal("billing", billing.TeamName)
+	s.Equal("payments", billing.ParentTeam)
+	s.Require().Len(billing.Members, 1)
+	s.Equal("b1", billing.Members[0].UserID)
+
+	rr = serveJSON(s.router, http.MethodGet, "/team/get?team_name=finance", nil)
+	s.Require().Equal(http.StatusOK, rr.Code)
+	var flat team.InfoDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &flat))
+	s.Empty(flat.SubTeams)
+
+	rr = serveJSON(s.router, http.MethodPost, "/team/rename", team.RenameRequestDTO{TeamName: "payments", NewTeamName: "pay"})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+	var dbTeam model.Team
+	s.Require().NoError(s.rawDB.First(&dbTeam, "team_name = ?", "billing").Error)
+	s.Equal("pay", dbTeam.ParentTeam)
+
+	rr = serveJSON(s.router, http.MethodPost, "/team/delete", team.DeleteRequestDTO{TeamName: "pay"})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+	s.Require().NoError(s.rawDB.First(&dbTeam, "team_name = ?", "billing").Error)
+	s.Equal("finance", dbTeam.ParentTeam)
+}
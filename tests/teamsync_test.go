@@ -0,0 +1,212 @@
+//go:build integration
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/event"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
+	"github.com/SeeXWH/pr-reviewer-service/internal/teamsync"
+	"github.com/SeeXWH/pr-reviewer-service/internal/user"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/logger"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+func TestTeamSyncSuite(t *testing.T) {
+	suite.Run(t, new(TeamSyncSuite))
+}
+
+type TeamSyncSuite struct {
+	suite.Suite
+	rawDB     *gorm.DB
+	dbWrapper *db.PostgresDB
+	router    http.Handler
+	cleanUp   func()
+}
+
+func (s *TeamSyncSuite) SetupSuite() {
+	ctx := context.Background()
+	log := logger.Setup()
+	mux := http.NewServeMux()
+
+	pgContainer, cleanup, err := SetupPostgresContainer()
+	s.Require().NoError(err)
+	s.cleanUp = cleanup
+
+	host, _ := pgContainer.Host(ctx)
+	natPort, _ := pgContainer.MappedPort(ctx, "5432")
+
+	cfg := &configs.Config{
+		DB: configs.DB{
+			Username: "user",
+			Password: "password",
+			Dbname:   "testdb",
+			Host:     host,
+			Port:     natPort.Port(),
+		},
+		App: configs.App{
+			TimeOut: 2 * time.Second,
+		},
+	}
+
+	var errDB error
+	for i := 0; i < 10; i++ {
+		s.dbWrapper, errDB = db.NewPostgresDB(cfg)
+		if errDB == nil {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	s.Require().NoError(errDB)
+	s.rawDB = s.dbWrapper.PostgresDB
+
+	s.Require().NoError(MigrateSchema(s.rawDB))
+
+	notifyService := notify.NewService(notify.NewRepository(s.dbWrapper), nil, cfg.Notify, log)
+	syncRepo := teamsync.NewRepository(s.dbWrapper, user.NewRepository(s.dbWrapper))
+	teamsync.NewHandler(mux, teamsync.NewService(syncRepo, notifyService, log), cfg)
+
+	s.router = mux
+}
+
+func (s *TeamSyncSuite) TearDownSuite() {
+	if s.cleanUp != nil {
+		s.cleanUp()
+	}
+}
+
+func (s *TeamSyncSuite) SetupTest() {
+	s.rawDB.Exec("TRUNCATE TABLE pr_reviewers CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pr_events CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE outbox_messages CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pull_requests CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE users CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE teams CASCADE")
+}
+
+const syncTeamsYAML = `
+teams:
+  - name: backend
+    members:
+      - {user_id: u1, username: Author}
+      - {user_id: u3, username: Hero}
+      - {user_id: u5, username: Newbie, tags: [Go]}
+  - name: frontend
+    members:
+      - {user_id: u2, username: Mover, role: lead, primary: true}
+      - {user_id: u3, username: Hero}
+`
+
+func (s *TeamSyncSuite) seed() {
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "backend", RequiredReviewers: 2}).Error)
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "Mover", IsActive: true, TeamName: "backend"},
+		{ID: "u3", Username: "Hero", IsActive: true, TeamName: "backend"},
+		{ID: "u4", Username: "Leaver", IsActive: true, TeamName: "backend"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+	pr := model.PullRequest{
+		ID:        "pr-1",
+		Status:    "OPEN",
+		AuthorID:  "u1",
+		TeamName:  "backend",
+		Reviewers: []*model.User{&users[1], &users[3]},
+	}
+	s.Require().NoError(s.rawDB.Create(&pr).Error)
+}
+
+func (s *TeamSyncSuite) memberships(userID string) []model.TeamMember {
+	var members []model.TeamMember
+	s.Require().NoError(s.rawDB.Order("team_name").Find(&members, "user_id = ?", userID).Error)
+	return members
+}
+
+func (s *TeamSyncSuite) TestPlan_DoesNotChangeAnything() {
+	s.seed()
+
+	rr := serveJSON(s.router, http.MethodPost, "/team/syncPlan", teamsync.SyncRequestDTO{TeamsYAML: syncTeamsYAML})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp teamsync.PlanResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal([]string{"frontend"}, resp.NewTeams)
+	s.Equal([]teamsync.ChangeDTO{
+		{Action: teamsync.ActionAdd, UserID: "u5", TeamName: "backend", Role: model.RoleMember},
+		{Action: teamsync.ActionJoin, UserID: "u2", TeamName: "frontend", Role: model.RoleLead},
+		{Action: teamsync.ActionJoin, UserID: "u3", TeamName: "frontend", Role: model.RoleMember},
+		{Action: teamsync.ActionRemove, UserID: "u2", TeamName: "backend"},
+		{Action: teamsync.ActionRemove, UserID: "u4", TeamName: "backend"},
+	}, resp.Changes)
+
+	var count int64
+	s.rawDB.Model(&model.User{}).Where("user_id = ?", "u5").Count(&count)
+	s.Zero(count)
+}
+
+func (s *TeamSyncSuite) TestApply_ReassignsReviews() {
+	s.seed()
+
+	rr := serveJSON(s.router, http.MethodPost, "/team/syncApply", teamsync.SyncRequestDTO{TeamsYAML: syncTeamsYAML})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp teamsync.ApplyResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal(2, resp.ReassignedCount)
+
+	u2 := s.memberships("u2")
+	s.Require().Len(u2, 1)
+	s.Equal("frontend", u2[0].TeamName)
+	s.Equal(model.RoleLead, u2[0].Role)
+	s.True(u2[0].IsPrimary)
+	u3 := s.memberships("u3")
+	s.Require().Len(u3, 2)
+	s.True(u3[0].IsPrimary, "backend stays primary")
+	s.False(u3[1].IsPrimary)
+	s.Empty(s.memberships("u4"))
+	u5 := s.memberships("u5")
+	s.Require().Len(u5, 1)
+	s.True(u5[0].IsPrimary)
+
+	var u4, u5User model.User
+	s.Require().NoError(s.rawDB.First(&u4, "user_id = ?", "u4").Error)
+	s.Require().NoError(s.rawDB.First(&u5User, "user_id = ?", "u5").Error)
+	s.True(u4.IsActive, "leaving a team keeps the user")
+	s.Equal([]string{"go"}, u5User.Tags)
+
+	var pr model.PullRequest
+	s.Require().NoError(s.rawDB.Preload("Reviewers").First(&pr, "pull_request_id = ?", "pr-1").Error)
+	var reviewerIDs []string
+	for _, r := range pr.Reviewers {
+		reviewerIDs = append(reviewerIDs, r.ID)
+	}
+	s.ElementsMatch([]string{"u3", "u5"}, reviewerIDs)
+
+	var unassigned []model.PREvent
+	s.rawDB.Order("user_id").Find(&unassigned, "pull_request_id = ? AND type = ?", "pr-1", event.TypeUnassigned)
+	s.Require().Len(unassigned, 2)
+	s.Equal("removed from team", unassigned[0].Details)
+	s.Equal("removed from team", unassigned[1].Details)
+
+	rr = serveJSON(s.router, http.MethodPost, "/team/syncPlan", teamsync.SyncRequestDTO{TeamsYAML: syncTeamsYAML})
+	s.Require().Equal(http.StatusOK, rr.Code)
+	var plan teamsync.PlanResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &plan))
+	s.Empty(plan.NewTeams)
+	s.Empty(plan.Changes)
+}
+
+func (s *TeamSyncSuite) TestApply_BadFile() {
+	rr := serveJSON(s.router, http.MethodPost, "/team/syncApply", teamsync.SyncRequestDTO{TeamsYAML: "teams: [{name: a}, {name: a}]"})
+	s.Equal(http.StatusBadRequest, rr.Code)
+}
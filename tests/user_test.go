@@ -12,7 +12,9 @@ import (
 	"time"
 
 	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/event"
 	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
 	"github.com/SeeXWH/pr-reviewer-service/internal/user"
 	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
 	"github.com/SeeXWH/pr-reviewer-service/pkg/logger"
@@ -30,6 +32,7 @@ type UserSuite struct {
 	rawDB     *gorm.DB
 	dbWrapper *db.PostgresDB
 	router    http.Handler
+	userRepo  *user.Repository
 	cleanUp   func()
 }
 
@@ -69,11 +72,13 @@ func (s *UserSuite) SetupSuite() {
 	s.Require().NoError(errDB)
 	s.rawDB = s.dbWrapper.PostgresDB
 
-	err = s.rawDB.AutoMigrate(&model.Team{}, &model.User{}, &model.PullRequest{})
+	err = MigrateSchema(s.rawDB)
 	s.Require().NoError(err)
 
 	userRepo := user.NewRepository(s.dbWrapper)
-	userService := user.NewService(userRepo, log)
+	notifyService := notify.NewService(notify.NewRepository(s.dbWrapper), nil, cfg.Notify, log)
+	userService := user.NewService(userRepo, notifyService, log)
+	s.userRepo = userRepo
 
 	user.NewHandler(mux, userService, cfg)
 
@@ -88,7 +93,10 @@ func (s *UserSuite) TearDownSuite() {
 
 func (s *UserSuite) SetupTest() {
 	s.rawDB.Exec("TRUNCATE TABLE pr_reviewers CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pr_events CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE outbox_messages CASCADE")
 	s.rawDB.Exec("TRUNCATE TABLE pull_requests CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE away_periods CASCADE")
 	s.rawDB.Exec("TRUNCATE TABLE users CASCADE")
 	s.rawDB.Exec("TRUNCATE TABLE teams CASCADE")
 }
@@ -98,7 +106,7 @@ func (s *UserSuite) TestSetIsActive() {
 	s.Require().NoError(s.rawDB.Create(&team).Error)
 
 	u1 := model.User{ID: "u1", Username: "Alice", IsActive: true, TeamName: "backend"}
-	s.Require().NoError(s.rawDB.Create(&u1).Error)
+	s.Require().NoError(createUsers(s.rawDB, u1))
 
 	reqBody := user.SetActiveRequestDTO{
 		UserID:   "u1",
@@ -125,6 +133,95 @@ func (s *UserSuite) TestSetIsActive() {
 	s.False(dbUser.IsActive)
 }
 
+func (s *UserSuite) TestUpdateProfile() {
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "backend"}).Error)
+	u1 := model.User{ID: "u1", Username: "Alice", IsActive: true, TeamName: "backend", Tags: []string{"frontend"}}
+	s.Require().NoError(createUsers(s.rawDB, u1))
+
+	email := "alice@example.com"
+	rr := serveJSON(s.router, http.MethodPost, "/users/update", user.UpdateRequestDTO{
+		UserID: "u1",
+		Email:  &email,
+		Tags:   []string{"Database", "infra"},
+	})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp user.ResponseWrapper
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal("Alice", resp.User.Username)
+	s.Equal([]string{"database", "infra"}, resp.User.Tags)
+
+	var dbUser model.User
+	s.Require().NoError(s.rawDB.First(&dbUser, "user_id = ?", "u1").Error)
+	s.Equal(email, dbUser.Email)
+	s.Equal([]string{"database", "infra"}, dbUser.Tags)
+
+	rr = serveJSON(s.router, http.MethodPost, "/users/update", user.UpdateRequestDTO{UserID: "u1", Tags: []string{}})
+	s.Require().Equal(http.StatusOK, rr.Code)
+	s.Require().NoError(s.rawDB.First(&dbUser, "user_id = ?", "u1").Error)
+	s.Empty(dbUser.Tags)
+	s.Equal(email, dbUser.Email)
+
+	rr = serveJSON(s.router, http.MethodPost, "/users/update", user.UpdateRequestDTO{UserID: "ghost", Tags: []string{"x"}})
+	s.Equal(http.StatusNotFound, rr.Code)
+}
+
+func (s *UserSuite) TestGetAndList() {
+	s.Require().NoError(createUsers(s.rawDB,
+		model.User{ID: "u1", Username: "Alice", IsActive: true, TeamName: "backend"},
+		model.User{ID: "u2", Username: "alan_b", IsActive: false, TeamName: "backend"},
+		model.User{ID: "u3", Username: "Bob", IsActive: true, TeamName: "backend"},
+		model.User{ID: "u4", Username: "Alex", IsActive: true, TeamName: "frontend"},
+	))
+	s.Require().NoError(s.rawDB.Create(&model.TeamMember{TeamName: "frontend", UserID: "u1"}).Error)
+
+	rr := serveJSON(s.router, http.MethodGet, "/users/get?user_id=u1", nil)
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+	var got user.ResponseWrapper
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &got))
+	s.Equal("Alice", got.User.Username)
+	s.Equal("backend", got.User.TeamName)
+
+	rr = serveJSON(s.router, http.MethodGet, "/users/get?user_id=ghost", nil)
+	s.Equal(http.StatusNotFound, rr.Code)
+
+	list := func(query string) user.ListResponseDTO {
+		rr := serveJSON(s.router, http.MethodGet, "/users/list?"+query, nil)
+		s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+		var resp user.ListResponseDTO
+		s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+		return resp
+	}
+	ids := func(resp user.ListResponseDTO) []string {
+		out := make([]string, 0, len(resp.Users))
+		for _, u := range resp.Users {
+			out = append(out, u.UserID)
+		}
+		return out
+	}
+
+	frontend := list("team_name=frontend")
+	s.Equal([]string{"u1", "u4"}, ids(frontend))
+	s.Equal("frontend", frontend.Users[0].TeamName)
+
+	s.Equal([]string{"u1", "u3"}, ids(list("team_name=backend&is_active=true")))
+	s.Equal([]string{"u1", "u2", "u4"}, ids(list("username_prefix=al")))
+	s.Empty(list("username_prefix=al_").Users)
+	s.Equal([]string{"u2"}, ids(list("username_prefix=alan_")))
+
+	first := list("limit=3")
+	s.Equal([]string{"u1", "u2", "u3"}, ids(first))
+	s.Require().NotEmpty(first.NextCursor)
+	second := list("limit=3&cursor=" + first.NextCursor)
+	s.Equal([]string{"u4"}, ids(second))
+	s.Empty(second.NextCursor)
+
+	rr = serveJSON(s.router, http.MethodGet, "/users/list?is_active=maybe", nil)
+	s.Equal(http.StatusBadRequest, rr.Code)
+	rr = serveJSON(s.router, http.MethodGet, "/users/list?cursor=!!", nil)
+	s.Equal(http.StatusBadRequest, rr.Code)
+}
+
 func (s *UserSuite) TestMassDeactivate() {
 	team := model.Team{Name: "backend"}
 	s.Require().NoError(s.rawDB.Create(&team).Error)
@@ -134,7 +231,7 @@ func (s *UserSuite) TestMassDeactivate() {
 		{ID: "u2", Username: "FiredGuy", IsActive: true, TeamName: "backend"},
 		{ID: "u3", Username: "Hero", IsActive: true, TeamName: "backend"},
 	}
-	s.Require().NoError(s.rawDB.Create(&users).Error)
+	s.Require().NoError(createUsers(s.rawDB, users...))
 
 	pr := model.PullRequest{
 		ID:        "pr-1",
@@ -163,6 +260,15 @@ func (s *UserSuite) TestMassDeactivate() {
 
 	s.Equal(1, resp.DeactivatedCount)
 	s.Equal(1, resp.ReassignedPRs)
+	s.False(resp.DryRun)
+	s.Equal([]user.PRChangeDTO{{
+		PullRequestID:    "pr-1",
+		TeamName:         "backend",
+		RemovedReviewers: []string{"u2"},
+		AddedReviewers:   []string{"u3"},
+		MissingReviewers: 1,
+	}}, resp.PullRequests)
+	s.Equal(1, resp.ShortPRs)
 
 	var u2FromDB model.User
 	s.rawDB.First(&u2FromDB, "user_id = ?", "u2")
@@ -173,6 +279,212 @@ func (s *UserSuite) TestMassDeactivate() {
 
 	s.Require().Len(prFromDB.Reviewers, 1)
 	s.Equal("u3", prFromDB.Reviewers[0].ID)
+
+	var events []model.PREvent
+	s.rawDB.Order("id").Find(&events, "pull_request_id = ?", "pr-1")
+	s.Require().Len(events, 2)
+	s.Equal(event.TypeUnassigned, events[0].Type)
+	s.Equal("u2", events[0].UserID)
+	s.Equal(event.TypeAssigned, events[1].Type)
+	s.Equal("u3", events[1].UserID)
+}
+
+func (s *UserSuite) TestMassDeactivate_DryRun() {
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "Leaving", IsActive: true, TeamName: "backend"},
+		{ID: "u3", Username: "Hero", IsActive: true, TeamName: "backend"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+	s.Require().NoError(s.rawDB.Create(&model.PullRequest{
+		ID:        "pr-1",
+		Status:    "OPEN",
+		AuthorID:  "u1",
+		TeamName:  "backend",
+		Reviewers: []*model.User{&users[1]},
+	}).Error)
+
+	rr := serveJSON(s.router, http.MethodPost, "/users/massDeactivate", user.MassDeactivateRequestDTO{
+		TeamName: "backend",
+		UserIDs:  []string{"u2"},
+		DryRun:   true,
+	})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp user.MassDeactivateResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.True(resp.DryRun)
+	s.Equal(1, resp.DeactivatedCount)
+	s.Equal(1, resp.ReassignedPRs)
+	s.Equal([]user.PRChangeDTO{{
+		PullRequestID:    "pr-1",
+		TeamName:         "backend",
+		RemovedReviewers: []string{"u2"},
+		AddedReviewers:   []string{"u3"},
+		MissingReviewers: 1,
+	}}, resp.PullRequests)
+	s.Equal(1, resp.ShortPRs)
+
+	var u2FromDB model.User
+	s.Require().NoError(s.rawDB.First(&u2FromDB, "user_id = ?", "u2").Error)
+	s.True(u2FromDB.IsActive)
+
+	var prFromDB model.PullRequest
+	s.Require().NoError(s.rawDB.Preload("Reviewers").First(&prFromDB, "pull_request_id = ?", "pr-1").Error)
+	s.Require().Len(prFromDB.Reviewers, 1)
+	s.Equal("u2", prFromDB.Reviewers[0].ID)
+
+	var events int64
+	s.rawDB.Model(&model.PREvent{}).Where("pull_request_id = ?", "pr-1").Count(&events)
+	s.Zero(events)
+	var outbox int64
+	s.rawDB.Model(&model.OutboxMessage{}).Count(&outbox)
+	s.Zero(outbox)
+}
+
+func (s *UserSuite) TestMassDeactivate_SpreadsReplacements() {
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "backend", RequiredReviewers: 2}).Error)
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "LeavingA", IsActive: true, TeamName: "backend"},
+		{ID: "u3", Username: "LeavingB", IsActive: true, TeamName: "backend"},
+		{ID: "u4", Username: "Stays", IsActive: true, TeamName: "backend"},
+		{ID: "u5", Username: "Idle", IsActive: true, TeamName: "backend"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+	prs := []model.PullRequest{
+		{ID: "pr-1", Status: "OPEN", AuthorID: "u1", TeamName: "backend", Reviewers: []*model.User{&users[1], &users[3]}},
+		{ID: "pr-2", Status: "OPEN", AuthorID: "u1", TeamName: "backend", Reviewers: []*model.User{&users[2], &users[3]}},
+		{ID: "pr-3", Status: "OPEN", AuthorID: "u1", TeamName: "backend", Reviewers: []*model.User{&users[1], &users[2]}},
+	}
+	s.Require().NoError(s.rawDB.Create(&prs).Error)
+
+	rr := serveJSON(s.router, http.MethodPost, "/users/massDeactivate", user.MassDeactivateRequestDTO{
+		TeamName: "backend",
+		UserIDs:  []string{"u2", "u3"},
+	})
+	s.Require().Equal(http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp user.MassDeactivateResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Equal(3, resp.ReassignedPRs)
+	s.Equal(0, resp.ShortPRs)
+	s.Require().Len(resp.PullRequests, 3)
+	s.Equal([]string{"u5"}, resp.PullRequests[0].AddedReviewers)
+	s.Equal([]string{"u5"}, resp.PullRequests[1].AddedReviewers)
+	s.ElementsMatch([]string{"u4", "u5"}, resp.PullRequests[2].AddedReviewers)
+	s.ElementsMatch([]string{"u2", "u3"}, resp.PullRequests[2].RemovedReviewers)
+
+	var reviewers []string
+	s.rawDB.Table("pr_reviewers").Where("pull_request_id = ?", "pr-1").Order("user_id").Pluck("user_id", &reviewers)
+	s.Equal([]string{"u4", "u5"}, reviewers)
+}
+
+func (s *UserSuite) TestMassDeactivate_PrefersLeastLoaded() {
+	team := model.Team{Name: "backend", RequiredReviewers: 1}
+	s.Require().NoError(s.rawDB.Create(&team).Error)
+
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "Leaving", IsActive: true, TeamName: "backend"},
+		{ID: "u3", Username: "Busy", IsActive: true, TeamName: "backend"},
+		{ID: "u4", Username: "Idle", IsActive: true, TeamName: "backend"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+
+	prs := []model.PullRequest{
+		{ID: "pr-1", Status: "OPEN", AuthorID: "u1", Reviewers: []*model.User{&users[1]}},
+		{ID: "pr-2", Status: "OPEN", AuthorID: "u1", Reviewers: []*model.User{&users[2]}},
+		{ID: "pr-3", Status: "OPEN", AuthorID: "u1", Reviewers: []*model.User{&users[2]}},
+		{ID: "pr-4", Status: "MERGED", AuthorID: "u1", Reviewers: []*model.User{&users[3]}},
+	}
+	s.Require().NoError(s.rawDB.Create(&prs).Error)
+
+	reqBody := user.MassDeactivateRequestDTO{
+		TeamName: "backend",
+		UserIDs:  []string{"u2"},
+	}
+	bodyBytes, _ := json.Marshal(reqBody)
+
+	req, _ := http.NewRequest(http.MethodPost, "/users/massDeactivate", bytes.NewBuffer(bodyBytes))
+	rr := httptest.NewRecorder()
+
+	s.router.ServeHTTP(rr, req)
+
+	s.Equal(http.StatusOK, rr.Code)
+
+	var prFromDB model.PullRequest
+	s.rawDB.Preload("Reviewers").First(&prFromDB, "pull_request_id = ?", "pr-1")
+
+	s.Require().Len(prFromDB.Reviewers, 1)
+	s.Equal("u4", prFromDB.Reviewers[0].ID)
+}
+
+func (s *UserSuite) TestAwayPeriod_SkipsAndReassigns() {
+	team := model.Team{Name: "backend", RequiredReviewers: 1}
+	s.Require().NoError(s.rawDB.Create(&team).Error)
+
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "Vacationer", IsActive: true, TeamName: "backend"},
+		{ID: "u3", Username: "Hero", IsActive: true, TeamName: "backend"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+
+	pr := model.PullRequest{
+		ID:        "pr-1",
+		Status:    "OPEN",
+		AuthorID:  "u1",
+		Reviewers: []*model.User{&users[1]},
+	}
+	s.Require().NoError(s.rawDB.Create(&pr).Error)
+
+	now := time.Now()
+	reqBody := user.AwayPeriodRequestDTO{
+		UserID:   "u2",
+		StartsAt: now.Add(-time.Hour),
+		EndsAt:   now.Add(24 * time.Hour),
+		Reason:   "vacation",
+	}
+	bodyBytes, _ := json.Marshal(reqBody)
+
+	req, _ := http.NewRequest(http.MethodPost, "/users/addAwayPeriod", bytes.NewBuffer(bodyBytes))
+	rr := httptest.NewRecorder()
+
+	s.router.ServeHTTP(rr, req)
+
+	s.Require().Equal(http.StatusCreated, rr.Code)
+
+	candidates, err := s.userRepo.GetReviewCandidates(context.Background(), "backend", []string{"u1"})
+	s.Require().NoError(err)
+	s.Require().Len(candidates, 1)
+	s.Equal("u3", candidates[0].ID)
+
+	result, err := s.userRepo.ReassignAwayReviews(context.Background(), now)
+	s.Require().NoError(err)
+	s.Equal(1, result.PeriodCount)
+	s.Equal(1, result.ReassignedCount)
+
+	var prFromDB model.PullRequest
+	s.rawDB.Preload("Reviewers").First(&prFromDB, "pull_request_id = ?", "pr-1")
+	s.Require().Len(prFromDB.Reviewers, 1)
+	s.Equal("u3", prFromDB.Reviewers[0].ID)
+
+	result, err = s.userRepo.ReassignAwayReviews(context.Background(), now)
+	s.Require().NoError(err)
+	s.Equal(0, result.PeriodCount)
+
+	req, _ = http.NewRequest(http.MethodGet, "/users/listAwayPeriods?user_id=u2", nil)
+	rr = httptest.NewRecorder()
+
+	s.router.ServeHTTP(rr, req)
+
+	s.Require().Equal(http.StatusOK, rr.Code)
+
+	var resp user.AwayPeriodsResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &resp))
+	s.Require().Len(resp.Periods, 1)
+	s.NotNil(resp.Periods[0].ReassignedAt)
 }
 
 func (s *UserSuite) TestGetReview() {
@@ -183,7 +495,7 @@ func (s *UserSuite) TestGetReview() {
 		model.User{ID: "u2", Username: "Anton", IsActive: true, TeamName: "backend"},
 		model.User{ID: "u3", Username: "Kirill", IsActive: true, TeamName: "backend"},
 	}
-	s.Require().NoError(s.rawDB.Create(&users).Error)
+	s.Require().NoError(createUsers(s.rawDB, users...))
 
 	pr1 := model.PullRequest{
 		ID:        "pr-1",
@@ -0,0 +1,247 @@
+//go:build integration
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SeeXWH/pr-reviewer-service/configs"
+	"github.com/SeeXWH/pr-reviewer-service/internal/event"
+	"github.com/SeeXWH/pr-reviewer-service/internal/model"
+	"github.com/SeeXWH/pr-reviewer-service/internal/notify"
+	"github.com/SeeXWH/pr-reviewer-service/internal/ownership"
+	"github.com/SeeXWH/pr-reviewer-service/internal/pullrequest"
+	"github.com/SeeXWH/pr-reviewer-service/internal/selection"
+	"github.com/SeeXWH/pr-reviewer-service/internal/team"
+	"github.com/SeeXWH/pr-reviewer-service/internal/user"
+	"github.com/SeeXWH/pr-reviewer-service/internal/webhook"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/db"
+	"github.com/SeeXWH/pr-reviewer-service/pkg/logger"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+func TestWebhookSuite(t *testing.T) {
+	suite.Run(t, new(WebhookSuite))
+}
+
+type WebhookSuite struct {
+	suite.Suite
+	rawDB      *gorm.DB
+	dbWrapper  *db.PostgresDB
+	router     http.Handler
+	dispatcher *webhook.Dispatcher
+	cleanUp    func()
+}
+
+type receivedHook struct {
+	header http.Header
+	body   []byte
+}
+
+func (s *WebhookSuite) SetupSuite() {
+	ctx := context.Background()
+	log := logger.Setup()
+	mux := http.NewServeMux()
+
+	pgContainer, cleanup, err := SetupPostgresContainer()
+	s.Require().NoError(err)
+	s.cleanUp = cleanup
+
+	host, _ := pgContainer.Host(ctx)
+	natPort, _ := pgContainer.MappedPort(ctx, "5432")
+
+	cfg := &configs.Config{
+		DB: configs.DB{
+			Username: "user",
+			Password: "password",
+			Dbname:   "testdb",
+			Host:     host,
+			Port:     natPort.Port(),
+		},
+		App: configs.App{
+			TimeOut: 500 * time.Millisecond,
+		},
+		Webhooks: configs.Webhooks{
+			PollInterval:   time.Second,
+			RequestTimeout: time.Second,
+			MaxAttempts:    2,
+			BaseBackoff:    time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+			BatchSize:      50,
+		},
+	}
+
+	var errDB error
+	for i := 0; i < 10; i++ {
+		s.dbWrapper, errDB = db.NewPostgresDB(cfg)
+		if errDB == nil {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	s.Require().NoError(errDB)
+	s.rawDB = s.dbWrapper.PostgresDB
+
+	s.Require().NoError(MigrateSchema(s.rawDB))
+
+	notifyService := notify.NewService(notify.NewRepository(s.dbWrapper), nil, cfg.Notify, log)
+	userService := user.NewService(user.NewRepository(s.dbWrapper), notifyService, log)
+	teamService := team.NewService(team.NewRepository(s.dbWrapper, user.NewRepository(s.dbWrapper)), notifyService, configs.Reviewers{}, log)
+	selectionService := selection.NewService(selection.NewRepository(s.dbWrapper), cfg.Reviewers, log)
+	ownershipService := ownership.NewService(ownership.NewRepository(s.dbWrapper), log)
+	prService := pullrequest.NewService(userService, teamService, selectionService, ownershipService, pullrequest.NewRepository(s.dbWrapper), notifyService, cfg.Reviewers, log)
+	pullrequest.NewHandler(mux, prService, cfg)
+
+	webhookRepo := webhook.NewRepository(s.dbWrapper)
+	webhook.NewHandler(mux, webhook.NewService(webhookRepo, log), cfg)
+	s.dispatcher = webhook.NewDispatcher(webhookRepo, cfg.Webhooks, log)
+
+	s.router = mux
+}
+
+func (s *WebhookSuite) TearDownSuite() {
+	if s.cleanUp != nil {
+		s.cleanUp()
+	}
+}
+
+func (s *WebhookSuite) SetupTest() {
+	s.rawDB.Exec("TRUNCATE TABLE webhook_deliveries CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE webhook_subscriptions CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE outbox_messages CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pr_events CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pr_reviewers CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE pull_requests CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE users CASCADE")
+	s.rawDB.Exec("TRUNCATE TABLE teams CASCADE")
+
+	s.Require().NoError(s.rawDB.Create(&model.Team{Name: "backend", RequiredReviewers: 1}).Error)
+	users := []model.User{
+		{ID: "u1", Username: "Author", IsActive: true, TeamName: "backend"},
+		{ID: "u2", Username: "Reviewer", IsActive: true, TeamName: "backend"},
+	}
+	s.Require().NoError(createUsers(s.rawDB, users...))
+}
+
+func (s *WebhookSuite) TestDeliversSignedPayload() {
+	var mu sync.Mutex
+	var received []receivedHook
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, receivedHook{header: r.Header.Clone(), body: body})
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	rr := s.postJSON("/webhooks/register", webhook.RegisterRequestDTO{
+		URL:        receiver.URL,
+		Secret:     "topsecret",
+		EventTypes: []string{event.TypeCreated},
+	})
+	s.Require().Equal(http.StatusCreated, rr.Code)
+	var registered webhook.RegisterResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &registered))
+
+	rr = s.get("/webhooks/list")
+	s.Require().Equal(http.StatusOK, rr.Code)
+	var list webhook.ListResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &list))
+	s.Require().Len(list.Subscriptions, 1)
+	s.Equal(registered.Subscription.SubscriptionID, list.Subscriptions[0].SubscriptionID)
+
+	rr = s.postJSON("/pullRequest/create", pullrequest.CreatePRRequestDTO{PRID: "pr-1", Name: "Hook", AuthorID: "u1"})
+	s.Require().Equal(http.StatusCreated, rr.Code)
+
+	s.Require().NoError(s.dispatcher.DispatchOnce(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	s.Require().Len(received, 1)
+	hook := received[0]
+	s.Equal(webhook.Sign("topsecret", hook.body), hook.header.Get(webhook.SignatureHeader))
+	s.Equal(event.TypeCreated, hook.header.Get(webhook.EventHeader))
+
+	var payload event.Payload
+	s.Require().NoError(json.Unmarshal(hook.body, &payload))
+	s.Equal("pr-1", payload.PullRequestID)
+	s.Equal(event.TypeCreated, payload.Type)
+
+	var delivery model.WebhookDelivery
+	s.Require().NoError(s.rawDB.First(&delivery).Error)
+	s.Equal(webhook.DeliveryDelivered, delivery.Status)
+	s.Equal(1, delivery.Attempts)
+}
+
+func (s *WebhookSuite) TestDeadLetterAfterRetries() {
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	rr := s.postJSON("/webhooks/register", webhook.RegisterRequestDTO{
+		URL:        receiver.URL,
+		EventTypes: []string{event.TypeCreated},
+	})
+	s.Require().Equal(http.StatusCreated, rr.Code)
+
+	rr = s.postJSON("/pullRequest/create", pullrequest.CreatePRRequestDTO{PRID: "pr-2", Name: "Hook", AuthorID: "u1"})
+	s.Require().Equal(http.StatusCreated, rr.Code)
+
+	s.Require().NoError(s.dispatcher.DispatchOnce(context.Background()))
+	var delivery model.WebhookDelivery
+	s.Require().NoError(s.rawDB.First(&delivery).Error)
+	s.Equal(webhook.DeliveryPending, delivery.Status)
+	s.Equal(1, delivery.Attempts)
+
+	time.Sleep(10 * time.Millisecond)
+	s.Require().NoError(s.dispatcher.DispatchOnce(context.Background()))
+	s.Require().NoError(s.rawDB.First(&delivery).Error)
+	s.Equal(webhook.DeliveryDead, delivery.Status)
+	s.Equal(2, delivery.Attempts)
+	s.Equal("unexpected status 503", delivery.LastError)
+	s.Equal(int32(2), calls.Load())
+
+	time.Sleep(10 * time.Millisecond)
+	s.Require().NoError(s.dispatcher.DispatchOnce(context.Background()))
+	s.Equal(int32(2), calls.Load())
+}
+
+func (s *WebhookSuite) TestDeleteSubscription() {
+	rr := s.postJSON("/webhooks/register", webhook.RegisterRequestDTO{URL: "http://127.0.0.1:1/hook"})
+	s.Require().Equal(http.StatusCreated, rr.Code)
+	var registered webhook.RegisterResponseDTO
+	s.Require().NoError(json.Unmarshal(rr.Body.Bytes(), &registered))
+	s.NotEmpty(registered.Secret)
+
+	id := registered.Subscription.SubscriptionID
+	rr = s.postJSON("/webhooks/delete", webhook.DeleteRequestDTO{SubscriptionID: id})
+	s.Equal(http.StatusNoContent, rr.Code)
+
+	rr = s.postJSON("/webhooks/delete", webhook.DeleteRequestDTO{SubscriptionID: id})
+	s.Equal(http.StatusNotFound, rr.Code)
+
+	rr = s.postJSON("/webhooks/register", webhook.RegisterRequestDTO{URL: "not a url"})
+	s.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func (s *WebhookSuite) postJSON(path string, payload any) *httptest.ResponseRecorder {
+	return serveJSON(s.router, http.MethodPost, path, payload)
+}
+
+func (s *WebhookSuite) get(path string) *httptest.ResponseRecorder {
+	return serveJSON(s.router, http.MethodGet, path, nil)
+}